@@ -0,0 +1,83 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ymotongpoo/ga/internal/analytics"
+	"github.com/ymotongpoo/ga/internal/config"
+)
+
+// handleCache はレスポンスのキャッシュを操作する
+// clear はすべてのエントリを削除し、stats はエントリ数と合計サイズを表示する
+func (app *CLIApp) handleCache(args []string) error {
+	if len(args) == 0 {
+		return &usageError{err: fmt.Errorf("操作を指定してください（ga cache clear または ga cache stats）")}
+	}
+	action := args[0]
+
+	fs := newCommandFlagSet("cache " + action)
+	if err := parseCommandFlags(fs, args[1:]); err != nil {
+		return err
+	}
+
+	cache, err := newResponseCache()
+	if err != nil {
+		return err
+	}
+
+	switch action {
+	case "clear":
+		removed, err := cache.Clear()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ キャッシュを削除しました（%d 件）: %s\n", removed, cache.Dir())
+		return nil
+	case "stats":
+		stats, err := cache.Stats()
+		if err != nil {
+			return err
+		}
+		printCacheStats(os.Stdout, cache.Dir(), stats)
+		return nil
+	default:
+		return &usageError{err: fmt.Errorf("不明な操作です: %s（clear または stats を指定してください）", action)}
+	}
+}
+
+// newResponseCache は既定の保存先のレスポンスのキャッシュを作成する
+func newResponseCache() (*analytics.ResponseCache, error) {
+	dir, err := analytics.DefaultResponseCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return analytics.NewResponseCache(dir, config.DefaultCacheTTL, false), nil
+}
+
+// printCacheStats はキャッシュの使用状況を表示する
+func printCacheStats(w io.Writer, dir string, stats *analytics.CacheStats) {
+	fmt.Fprintf(w, "保存先: %s\n", dir)
+	fmt.Fprintf(w, "エントリ数: %d\n", stats.Entries)
+	fmt.Fprintf(w, "合計サイズ: %.2f KB\n", float64(stats.Bytes)/1024)
+	if stats.Entries == 0 {
+		return
+	}
+	fmt.Fprintf(w, "最も古いエントリ: %s\n", stats.Oldest.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "最も新しいエントリ: %s\n", stats.Newest.Format("2006-01-02 15:04:05"))
+}
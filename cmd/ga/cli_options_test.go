@@ -15,8 +15,15 @@
 package main
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/analytics"
+	"github.com/ymotongpoo/ga/internal/config"
 )
 
 func TestParseArgs_FormatOption(t *testing.T) {
@@ -304,3 +311,388 @@ func TestCLIOptions_DefaultValues(t *testing.T) {
 		t.Errorf("Expected default login false, got %v", options.Login)
 	}
 }
+
+func TestApplyConfigOverrides_DateOptions(t *testing.T) {
+	app := NewCLIApp()
+
+	testCases := []struct {
+		name     string
+		args     []string
+		base     config.Config
+		expected config.Config
+	}{
+		{
+			name:     "上書きなし",
+			args:     []string{},
+			base:     config.Config{StartDate: "2024-01-01", EndDate: "2024-01-31"},
+			expected: config.Config{StartDate: "2024-01-01", EndDate: "2024-01-31"},
+		},
+		{
+			name:     "名前付き期間で開始日と終了日を置き換え",
+			args:     []string{"--date-range", "last_month"},
+			base:     config.Config{StartDate: "2024-01-01", EndDate: "2024-01-31"},
+			expected: config.Config{DateRange: "last_month"},
+		},
+		{
+			name:     "開始日と終了日で名前付き期間を置き換え",
+			args:     []string{"--start-date", "7daysAgo", "--end-date", "yesterday"},
+			base:     config.Config{DateRange: "last_month"},
+			expected: config.Config{StartDate: "7daysAgo", EndDate: "yesterday"},
+		},
+		{
+			name:     "タイムゾーンの上書き",
+			args:     []string{"--timezone", "Asia/Tokyo"},
+			base:     config.Config{DateRange: "last_week", Timezone: "UTC"},
+			expected: config.Config{DateRange: "last_week", Timezone: "Asia/Tokyo"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options, err := app.parseArgs(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			cfg := tc.base
+			app.applyConfigOverrides(&cfg, options)
+
+			if cfg.StartDate != tc.expected.StartDate || cfg.EndDate != tc.expected.EndDate ||
+				cfg.DateRange != tc.expected.DateRange || cfg.Timezone != tc.expected.Timezone {
+				t.Errorf("Expected %+v, got %+v", tc.expected, cfg)
+			}
+		})
+	}
+}
+
+func TestApplyConfigOverrides_ConcurrencyOptions(t *testing.T) {
+	app := NewCLIApp()
+
+	testCases := []struct {
+		name     string
+		args     []string
+		base     *config.Concurrency
+		expected *config.Concurrency
+	}{
+		{
+			name:     "上書きなし",
+			args:     []string{},
+			base:     nil,
+			expected: nil,
+		},
+		{
+			name:     "設定ファイルにない場合は作成",
+			args:     []string{"--max-in-flight", "4", "--rps", "2.5"},
+			base:     nil,
+			expected: &config.Concurrency{MaxInFlight: 4, RequestsPerSecond: 2.5},
+		},
+		{
+			name:     "指定した項目のみ上書き",
+			args:     []string{"--max-in-flight-per-property", "1"},
+			base:     &config.Concurrency{MaxInFlight: 8, MaxInFlightPerProperty: 4, Burst: 3},
+			expected: &config.Concurrency{MaxInFlight: 8, MaxInFlightPerProperty: 1, Burst: 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options, err := app.parseArgs(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			cfg := config.Config{Concurrency: tc.base}
+			app.applyConfigOverrides(&cfg, options)
+
+			if (cfg.Concurrency == nil) != (tc.expected == nil) ||
+				(cfg.Concurrency != nil && *cfg.Concurrency != *tc.expected) {
+				t.Errorf("Expected %+v, got %+v", tc.expected, cfg.Concurrency)
+			}
+		})
+	}
+}
+
+func TestParseArgs_NegativeConcurrency(t *testing.T) {
+	app := NewCLIApp()
+
+	for _, args := range [][]string{
+		{"--max-in-flight", "-1"},
+		{"--max-in-flight-per-property", "-2"},
+		{"--rps", "-0.5"},
+	} {
+		if _, err := app.parseArgs(args); err == nil || !strings.Contains(err.Error(), "0以上") {
+			t.Errorf("parseArgs(%v) error = %v, want containing %q", args, err, "0以上")
+		}
+	}
+}
+
+func TestApplyConfigOverrides_ContinueOnError(t *testing.T) {
+	app := NewCLIApp()
+
+	options, err := app.parseArgs([]string{"--continue-on-error", "--failure-report", "failures.json"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if options.FailureReportPath != "failures.json" {
+		t.Errorf("FailureReportPath = %q, want %q", options.FailureReportPath, "failures.json")
+	}
+
+	cfg := config.Config{}
+	app.applyConfigOverrides(&cfg, options)
+	if !cfg.ContinueOnError {
+		t.Error("--continue-on-error should enable continue_on_error")
+	}
+
+	// 指定しない場合は設定ファイルの値を維持する
+	options, err = app.parseArgs([]string{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cfg = config.Config{ContinueOnError: true}
+	app.applyConfigOverrides(&cfg, options)
+	if !cfg.ContinueOnError {
+		t.Error("continue_on_error in the config file should be kept")
+	}
+}
+
+func TestApplyConfigOverrides_Strict(t *testing.T) {
+	app := NewCLIApp()
+
+	options, err := app.parseArgs([]string{"--strict"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cfg := config.Config{}
+	app.applyConfigOverrides(&cfg, options)
+	if !cfg.Strict {
+		t.Error("--strict should enable strict")
+	}
+
+	// 指定しない場合は設定ファイルの値を維持する
+	options, err = app.parseArgs([]string{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cfg = config.Config{Strict: true}
+	app.applyConfigOverrides(&cfg, options)
+	if !cfg.Strict {
+		t.Error("strict in the config file should be kept")
+	}
+}
+
+func TestWriteFailureReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "failures.json")
+	failures := []analytics.RequestFailure{{
+		PropertyID: "123456789",
+		StreamID:   "1234567",
+		Type:       "AUTH_ERROR",
+		Code:       "AUTH001",
+		HTTPStatus: 403,
+		Message:    "アクセス権限エラー: プロパティ 123456789 のレポート取得",
+		Cause:      "googleapi: Error 403: permission denied",
+	}}
+
+	if err := writeFailureReport(path, failures); err != nil {
+		t.Fatalf("writeFailureReport() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var got []analytics.RequestFailure
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("JSONの解析に失敗しました: %v", err)
+	}
+	if len(got) != 1 || got[0] != failures[0] {
+		t.Errorf("failures = %+v, want %+v", got, failures)
+	}
+
+	// 失敗がない場合は空の一覧を書き出す
+	if err := writeFailureReport(path, nil); err != nil {
+		t.Fatalf("writeFailureReport() error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); strings.TrimSpace(string(data)) != "[]" {
+		t.Errorf("失敗なしの一覧 = %q, want []", data)
+	}
+}
+
+func TestApplyConfigOverrides_SchemaOptions(t *testing.T) {
+	app := NewCLIApp()
+
+	testCases := []struct {
+		name      string
+		args      []string
+		base      config.Config
+		wantMode  string
+		wantValue string
+	}{
+		{
+			name:      "上書きなし",
+			args:      []string{},
+			base:      config.Config{SchemaMode: "split", NullValue: "NA"},
+			wantMode:  "split",
+			wantValue: "NA",
+		},
+		{
+			name:      "モードとnull値の上書き",
+			args:      []string{"--schema-mode", "split", "--null-value", "(not set)"},
+			base:      config.Config{},
+			wantMode:  "split",
+			wantValue: "(not set)",
+		},
+		{
+			name:      "空文字のnull値で上書き",
+			args:      []string{"--null-value", ""},
+			base:      config.Config{NullValue: "NA"},
+			wantMode:  "",
+			wantValue: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options, err := app.parseArgs(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			cfg := tc.base
+			app.applyConfigOverrides(&cfg, options)
+
+			if cfg.SchemaMode != tc.wantMode || cfg.NullValue != tc.wantValue {
+				t.Errorf("SchemaMode = %q, NullValue = %q, want %q, %q", cfg.SchemaMode, cfg.NullValue, tc.wantMode, tc.wantValue)
+			}
+		})
+	}
+}
+
+func TestApplyConfigOverrides_RetryOptions(t *testing.T) {
+	app := NewCLIApp()
+
+	options, err := app.parseArgs([]string{
+		"--max-retries", "0",
+		"--retry-base-delay", "500ms",
+		"--retry-max-delay", "10s",
+		"--retry-backoff-factor", "1.5",
+		"--retry-jitter", "0",
+		"--retry-codes", "429, 503",
+		"--retry-budget", "2m",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cfg := config.Config{}
+	app.applyConfigOverrides(&cfg, options)
+	if cfg.Retry == nil {
+		t.Fatal("Retry should be set by the retry options")
+	}
+	r := cfg.Retry
+	if r.MaxRetries == nil || *r.MaxRetries != 0 {
+		t.Errorf("MaxRetries = %v, want 0", r.MaxRetries)
+	}
+	if r.BaseDelay != 500*time.Millisecond || r.MaxDelay != 10*time.Second || r.Budget != 2*time.Minute {
+		t.Errorf("BaseDelay, MaxDelay, Budget = %v, %v, %v", r.BaseDelay, r.MaxDelay, r.Budget)
+	}
+	if r.BackoffFactor != 1.5 {
+		t.Errorf("BackoffFactor = %g, want 1.5", r.BackoffFactor)
+	}
+	if r.Jitter == nil || *r.Jitter != 0 {
+		t.Errorf("Jitter = %v, want 0", r.Jitter)
+	}
+	if len(r.RetryableCodes) != 2 || r.RetryableCodes[0] != 429 || r.RetryableCodes[1] != 503 {
+		t.Errorf("RetryableCodes = %v, want [429 503]", r.RetryableCodes)
+	}
+
+	// 指定しない場合は設定ファイルの値を維持する
+	options, err = app.parseArgs([]string{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	maxRetries := 5
+	cfg = config.Config{Retry: &config.Retry{MaxRetries: &maxRetries}}
+	app.applyConfigOverrides(&cfg, options)
+	if cfg.Retry.MaxRetries == nil || *cfg.Retry.MaxRetries != 5 {
+		t.Errorf("max_retries in the config file should be kept, got %v", cfg.Retry.MaxRetries)
+	}
+}
+
+func TestParseArgs_InvalidRetryOptions(t *testing.T) {
+	app := NewCLIApp()
+
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{args: []string{"--max-retries", "-1"}, wantErr: "0以上"},
+		{args: []string{"--retry-budget", "-1s"}, wantErr: "0以上"},
+		{args: []string{"--retry-codes", "429,abc"}, wantErr: "abc はHTTPステータスコードではありません"},
+		{args: []string{"--retry-codes", ","}, wantErr: "HTTPステータスコードを指定してください"},
+	}
+
+	for _, tt := range tests {
+		if _, err := app.parseArgs(tt.args); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("parseArgs(%v) error = %v, want containing %q", tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+func TestApplyConfigOverrides_NumberFormatOptions(t *testing.T) {
+	app := NewCLIApp()
+
+	options, err := app.parseArgs([]string{"--locale", "de-DE", "--decimal-places", "0", "--duration-format", "hms"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cfg := config.Config{}
+	app.applyConfigOverrides(&cfg, options)
+	f := cfg.NumberFormat
+	if f == nil {
+		t.Fatal("NumberFormat should be set by the number format options")
+	}
+	if f.Locale != "de-DE" || f.DecimalPlaces == nil || *f.DecimalPlaces != 0 || f.Duration != config.DurationFormatHMS {
+		t.Errorf("NumberFormat = %+v", f)
+	}
+
+	// 指定しない場合は設定ファイルの値を維持する
+	options, err = app.parseArgs([]string{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cfg = config.Config{NumberFormat: &config.NumberFormat{Locale: "ja-JP"}}
+	app.applyConfigOverrides(&cfg, options)
+	if cfg.NumberFormat.Locale != "ja-JP" || cfg.NumberFormat.DecimalPlaces != nil {
+		t.Errorf("number_format in the config file should be kept, got %+v", cfg.NumberFormat)
+	}
+}
+
+func TestApplyConfigOverrides_NoCache(t *testing.T) {
+	app := NewCLIApp()
+
+	options, err := app.parseArgs([]string{"--no-cache"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cfg := config.Config{Cache: &config.Cache{TTL: time.Hour, KeepClosedRanges: true}}
+	app.applyConfigOverrides(&cfg, options)
+	if cfg.Cache.Enabled() {
+		t.Error("--no-cache should disable the cache")
+	}
+	if cfg.Cache.TTL != time.Hour || !cfg.Cache.KeepClosedRanges {
+		t.Errorf("cache settings in the config file should be kept, got %+v", cfg.Cache)
+	}
+
+	// 指定しない場合は設定ファイルの値を維持する
+	options, err = app.parseArgs([]string{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cfg = config.Config{}
+	app.applyConfigOverrides(&cfg, options)
+	if cfg.Cache != nil {
+		t.Errorf("Cache should not be created without --no-cache, got %+v", cfg.Cache)
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/analytics"
+	"github.com/ymotongpoo/ga/internal/config"
+	"golang.org/x/oauth2"
+)
+
+// minRealtimeInterval はga realtime の更新間隔の下限（APIクォータ保護のため）
+const minRealtimeInterval = 10 * time.Second
+
+// usageError はサブコマンドの引数の誤りを表すエラー（終了コード2）
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+// commands は利用できるサブコマンドの一覧を返す
+func (app *CLIApp) commands(ctx context.Context) []Command {
+	return []Command{
+		{
+			Name:        "validate",
+			Description: "設定ファイルをGA4のメタデータで検証する",
+			Handler: func(args []string) error {
+				return app.handleValidate(ctx, args)
+			},
+		},
+		{
+			Name:        "discover",
+			Description: "Admin APIでアカウント・プロパティ・データストリームを一覧表示する",
+			Handler: func(args []string) error {
+				return app.handleDiscover(ctx, args)
+			},
+		},
+		{
+			Name:        "cache",
+			Description: "レスポンスのキャッシュを削除する（clear）・使用状況を表示する（stats）",
+			Handler: func(args []string) error {
+				return app.handleCache(args)
+			},
+		},
+		{
+			Name:        "realtime",
+			Description: "リアルタイムレポートを取得する（一定間隔で更新可能）",
+			Handler: func(args []string) error {
+				return app.handleRealtime(ctx, args)
+			},
+		},
+	}
+}
+
+// runCommand はサブコマンドを実行して終了コードを返す
+func (app *CLIApp) runCommand(ctx context.Context, name string, args []string) int {
+	for _, cmd := range app.commands(ctx) {
+		if cmd.Name != name {
+			continue
+		}
+
+		if err := cmd.Handler(args); err != nil {
+			if _, ok := err.(*usageError); ok {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 2
+			}
+			fmt.Fprintf(os.Stderr, "%s エラー: %v\n", name, err)
+			return app.getExitCodeFromError(err)
+		}
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "Error: 不明なサブコマンドです: %s\n\n使用方法については 'ga --help' を実行してください\n", name)
+	return 2
+}
+
+// newCommandFlagSet はサブコマンド用のFlagSetを作成する
+func newCommandFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("ga "+name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseCommandFlags はサブコマンドの引数を解析する
+func parseCommandFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return &usageError{err: fmt.Errorf("無効なオプションが指定されました: %v\n\n使用方法については 'ga --help' を実行してください", err)}
+	}
+	return nil
+}
+
+// handleValidate は設定ファイルを読み込み、構造とディメンション・メトリクスを検証する
+// --offline を指定した場合はキャッシュ済みのメタデータのみを使用し、認証も行わない
+func (app *CLIApp) handleValidate(ctx context.Context, args []string) error {
+	fs := newCommandFlagSet("validate")
+	configPath := fs.String("config", "ga.yaml", "設定ファイルのパス")
+	offline := fs.Bool("offline", false, "キャッシュ済みのメタデータのみで検証する")
+	if err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+
+	cfg, err := app.configService.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
+	}
+
+	if err := app.configService.ValidateConfig(cfg); err != nil {
+		return fmt.Errorf("設定ファイルの検証に失敗しました: %w", err)
+	}
+
+	var token *oauth2.Token
+	if !*offline {
+		token, err = app.getToken(ctx)
+		if err != nil {
+			return err
+		}
+		if err := app.resolveProperties(ctx, cfg, token); err != nil {
+			return err
+		}
+	} else if cfg.PropertiesAll {
+		fmt.Printf("⚠️  --offline ではプロパティを解決しないため、all_properties.template のディメンションとメトリクスは検証しません\n")
+	}
+
+	if err := app.validateMetadata(ctx, cfg, token); err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ 設定ファイル '%s' は有効です\n", *configPath)
+	return nil
+}
+
+// handleRealtime はリアルタイムレポートを取得し、表形式で表示するかNDJSONファイルに追記する
+// --interval を指定した場合は中断されるまで一定間隔で取得を繰り返す
+func (app *CLIApp) handleRealtime(ctx context.Context, args []string) error {
+	fs := newCommandFlagSet("realtime")
+	configPath := fs.String("config", "ga.yaml", "設定ファイルのパス")
+	interval := fs.Duration("interval", 0, "更新間隔（例: 30s, 1m）。0の場合は1回のみ取得する")
+	outputPath := fs.String("output", "", "NDJSONを追記するファイルのパス（指定しない場合は表形式で表示）")
+	dimensions := fs.String("dimensions", "", "ディメンション（カンマ区切り。設定ファイルの realtime.dimensions を上書き）")
+	metrics := fs.String("metrics", "", "メトリクス（カンマ区切り。設定ファイルの realtime.metrics を上書き）")
+	if err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+	if *interval != 0 && *interval < minRealtimeInterval {
+		return &usageError{err: fmt.Errorf("--interval は %v 以上を指定してください: %v", minRealtimeInterval, *interval)}
+	}
+
+	cfg, err := app.configService.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
+	}
+
+	applyRealtimeOverrides(cfg, *dimensions, *metrics)
+	if cfg.Realtime == nil {
+		return &usageError{err: fmt.Errorf("設定ファイルに realtime を記述するか、--metrics を指定してください")}
+	}
+
+	if err := app.configService.ValidateConfig(cfg); err != nil {
+		return fmt.Errorf("設定ファイルの検証に失敗しました: %w", err)
+	}
+	app.outputService.SetNumberFormat(cfg.NumberFormat)
+
+	token, err := app.getToken(ctx)
+	if err != nil {
+		return err
+	}
+	if err := app.resolveProperties(ctx, cfg, token); err != nil {
+		return err
+	}
+
+	app.analyticsService, err = analytics.NewAnalyticsService(ctx, token, cfg)
+	if err != nil {
+		return fmt.Errorf("分析サービスの初期化に失敗しました: %w", err)
+	}
+
+	// Ctrl+C で更新ループを終了する
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	for {
+		if err := app.refreshRealtime(ctx, cfg, *outputPath, *interval > 0); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if *interval == 0 {
+				return err
+			}
+			// 一時的な失敗で監視を止めないよう、警告を表示して次の更新を待つ
+			fmt.Fprintf(os.Stderr, "⚠️  リアルタイムデータの取得に失敗しました: %v\n", err)
+		}
+
+		if *interval == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(*interval):
+		}
+	}
+}
+
+// refreshRealtime はリアルタイムレポートを1回取得して出力する
+// clear がtrueの場合は表の描画前に画面を消去する
+func (app *CLIApp) refreshRealtime(ctx context.Context, cfg *config.Config, outputPath string, clear bool) error {
+	data, err := app.analyticsService.GetRealtimeData(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Format("2006-01-02 15:04:05")
+
+	if outputPath != "" {
+		if err := app.outputService.AppendNDJSON(data, outputPath); err != nil {
+			return fmt.Errorf("データ出力に失敗しました: %w", err)
+		}
+		fmt.Printf("[%s] %d レコードを %s に追記しました\n", now, data.Summary.TotalRows, outputPath)
+		return nil
+	}
+
+	if clear {
+		fmt.Print("\033[H\033[2J")
+	}
+	fmt.Printf("リアルタイムレポート（%s） 更新: %s\n\n", data.Summary.DateRange, now)
+	if err := app.outputService.WriteTable(data, os.Stdout); err != nil {
+		return fmt.Errorf("データ出力に失敗しました: %w", err)
+	}
+	fmt.Printf("\n%d レコード\n", data.Summary.TotalRows)
+	return nil
+}
+
+// applyRealtimeOverrides はコマンドラインで指定されたディメンションとメトリクスで
+// realtime 設定を上書きする（設定ファイルに realtime がない場合は作成する）
+func applyRealtimeOverrides(cfg *config.Config, dimensions, metrics string) {
+	if dimensions == "" && metrics == "" {
+		return
+	}
+	if cfg.Realtime == nil {
+		cfg.Realtime = &config.Realtime{}
+	}
+	if dimensions != "" {
+		cfg.Realtime.Dimensions = splitList(dimensions)
+	}
+	if metrics != "" {
+		cfg.Realtime.Metrics = splitList(metrics)
+	}
+}
+
+// splitList はカンマ区切りの文字列を空白を除いたリストに分割する
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,328 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/admin"
+	"github.com/ymotongpoo/ga/internal/analytics"
+	"github.com/ymotongpoo/ga/internal/config"
+	"github.com/ymotongpoo/ga/internal/metadata"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// setupMetadataCache はテスト用のキャッシュディレクトリにメタデータを保存する
+func setupMetadataCache(t *testing.T) {
+	t.Helper()
+
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	t.Setenv("HOME", cacheHome)
+
+	dir, err := metadata.DefaultCacheDir()
+	if err != nil {
+		t.Fatalf("DefaultCacheDir() error = %v", err)
+	}
+	err = metadata.NewCache(dir, metadata.DefaultTTL).Save(&metadata.Metadata{
+		PropertyID: "987654321",
+		FetchedAt:  time.Now(),
+		Dimensions: []metadata.Field{{APIName: "date"}, {APIName: "pagePath"}},
+		Metrics:    []metadata.Field{{APIName: "sessions"}, {APIName: "activeUsers"}},
+	})
+	if err != nil {
+		t.Fatalf("メタデータの保存に失敗しました: %v", err)
+	}
+}
+
+// writeValidateConfig はテスト用の設定ファイルを作成する
+func writeValidateConfig(t *testing.T, dimension string) string {
+	t.Helper()
+
+	configFile := filepath.Join(t.TempDir(), "ga.yaml")
+	content := `
+start_date: "2023-01-01"
+end_date: "2023-01-31"
+account: "123456789"
+properties:
+  - property: "987654321"
+    streams:
+      - stream: "1234567"
+        dimensions:
+          - "` + dimension + `"
+        metrics:
+          - "sessions"
+`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("設定ファイルの作成に失敗しました: %v", err)
+	}
+	return configFile
+}
+
+// writeAllPropertiesConfig はテスト用の properties: all の設定ファイルを作成する
+func writeAllPropertiesConfig(t *testing.T) string {
+	t.Helper()
+
+	configFile := filepath.Join(t.TempDir(), "ga.yaml")
+	content := `
+start_date: "2023-01-01"
+end_date: "2023-01-31"
+account: "123456789"
+properties: all
+all_properties:
+  template:
+    dimensions:
+      - "date"
+    metrics:
+      - "sessions"
+`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("設定ファイルの作成に失敗しました: %v", err)
+	}
+	return configFile
+}
+
+func TestCLIApp_Run_ValidateOffline(t *testing.T) {
+	setupMetadataCache(t)
+
+	testCases := []struct {
+		name         string
+		args         []string
+		expectedCode int
+	}{
+		{
+			name:         "有効な設定",
+			args:         []string{"validate", "--offline", "--config", writeValidateConfig(t, "pagePath")},
+			expectedCode: 0,
+		},
+		{
+			name:         "不明なディメンション",
+			args:         []string{"validate", "--offline", "--config", writeValidateConfig(t, "pagePaht")},
+			expectedCode: 1,
+		},
+		{
+			name:         "properties: all",
+			args:         []string{"validate", "--offline", "--config", writeAllPropertiesConfig(t)},
+			expectedCode: 0,
+		},
+		{
+			name:         "無効なオプション",
+			args:         []string{"validate", "--unknown"},
+			expectedCode: 2,
+		},
+		{
+			name:         "不明なサブコマンド",
+			args:         []string{"unknown"},
+			expectedCode: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := NewCLIApp()
+			app.initializeServices()
+
+			if code := app.Run(context.Background(), tc.args); code != tc.expectedCode {
+				t.Errorf("Run(%v) = %d, want %d", tc.args, code, tc.expectedCode)
+			}
+		})
+	}
+}
+
+func TestApplyRealtimeOverrides(t *testing.T) {
+	cfg := &config.Config{}
+	applyRealtimeOverrides(cfg, "", "")
+	if cfg.Realtime != nil {
+		t.Fatal("オプション未指定で realtime が作成されました")
+	}
+
+	applyRealtimeOverrides(cfg, "country, deviceCategory", "activeUsers")
+	if cfg.Realtime == nil {
+		t.Fatal("realtime が作成されていません")
+	}
+	if want := []string{"country", "deviceCategory"}; !reflect.DeepEqual(cfg.Realtime.Dimensions, want) {
+		t.Errorf("Dimensions = %v, want %v", cfg.Realtime.Dimensions, want)
+	}
+	if want := []string{"activeUsers"}; !reflect.DeepEqual(cfg.Realtime.Metrics, want) {
+		t.Errorf("Metrics = %v, want %v", cfg.Realtime.Metrics, want)
+	}
+}
+
+func TestCLIApp_Run_RealtimeErrors(t *testing.T) {
+	t.Setenv("GA_CLIENT_ID", "")
+	t.Setenv("GA_CLIENT_SECRET", "")
+	configFile := writeValidateConfig(t, "pagePath")
+
+	testCases := []struct {
+		name         string
+		args         []string
+		expectedCode int
+	}{
+		{
+			name:         "更新間隔が短すぎる",
+			args:         []string{"realtime", "--config", configFile, "--interval", "1s"},
+			expectedCode: 2,
+		},
+		{
+			name:         "realtime設定なし",
+			args:         []string{"realtime", "--config", configFile},
+			expectedCode: 2,
+		},
+		{
+			name:         "リアルタイム非対応のメトリクス",
+			args:         []string{"realtime", "--config", configFile, "--metrics", "sessions"},
+			expectedCode: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := NewCLIApp()
+			app.initializeServices()
+
+			if code := app.Run(context.Background(), tc.args); code != tc.expectedCode {
+				t.Errorf("Run(%v) = %d, want %d", tc.args, code, tc.expectedCode)
+			}
+		})
+	}
+}
+
+func TestCLIApp_Run_Cache(t *testing.T) {
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	t.Setenv("HOME", cacheHome)
+
+	dir, err := analytics.DefaultResponseCacheDir()
+	if err != nil {
+		t.Fatalf("DefaultResponseCacheDir() error = %v", err)
+	}
+	request := &analyticsdata.RunReportRequest{
+		DateRanges: []*analyticsdata.DateRange{{StartDate: "2024-01-01", EndDate: "2024-01-31"}},
+		Metrics:    []*analyticsdata.Metric{{Name: "sessions"}},
+	}
+	cache := analytics.NewResponseCache(dir, time.Hour, false)
+	if err := cache.Save("987654321", request, &analyticsdata.RunReportResponse{RowCount: 0}); err != nil {
+		t.Fatalf("レスポンスの保存に失敗しました: %v", err)
+	}
+
+	testCases := []struct {
+		name         string
+		args         []string
+		expectedCode int
+		wantEntries  int
+	}{
+		{name: "stats", args: []string{"cache", "stats"}, expectedCode: 0, wantEntries: 1},
+		{name: "操作なし", args: []string{"cache"}, expectedCode: 2, wantEntries: 1},
+		{name: "不明な操作", args: []string{"cache", "purge"}, expectedCode: 2, wantEntries: 1},
+		{name: "clear", args: []string{"cache", "clear"}, expectedCode: 0, wantEntries: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := NewCLIApp()
+			app.initializeServices()
+
+			if code := app.Run(context.Background(), tc.args); code != tc.expectedCode {
+				t.Errorf("Run(%v) = %d, want %d", tc.args, code, tc.expectedCode)
+			}
+			stats, err := cache.Stats()
+			if err != nil {
+				t.Fatalf("Stats() error = %v", err)
+			}
+			if stats.Entries != tc.wantEntries {
+				t.Errorf("エントリ数 = %d, want %d", stats.Entries, tc.wantEntries)
+			}
+		})
+	}
+}
+
+func TestPrintCacheStats(t *testing.T) {
+	var buf bytes.Buffer
+	printCacheStats(&buf, "/tmp/ga/reports", &analytics.CacheStats{})
+	want := "保存先: /tmp/ga/reports\nエントリ数: 0\n合計サイズ: 0.00 KB\n"
+	if buf.String() != want {
+		t.Errorf("printCacheStats() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	fetchedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.Local)
+	printCacheStats(&buf, "/tmp/ga/reports", &analytics.CacheStats{Entries: 2, Bytes: 2048, Oldest: fetchedAt, Newest: fetchedAt.Add(time.Hour)})
+	for _, line := range []string{"エントリ数: 2", "合計サイズ: 2.00 KB", "最も古いエントリ: 2024-03-01 12:00:00", "最も新しいエントリ: 2024-03-01 13:00:00"} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("printCacheStats() に %q が含まれていません:\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestPrintAccounts(t *testing.T) {
+	accounts := []admin.Account{
+		{
+			ID:          "100",
+			DisplayName: "Example Inc.",
+			Properties: []admin.Property{
+				{
+					ID:          "111",
+					DisplayName: "Example Web",
+					Streams: []admin.DataStream{
+						{ID: "1001", Type: admin.StreamTypeWeb, DisplayName: "Web", DefaultURI: "https://www.example.com", MeasurementID: "G-EXAMPLE"},
+						{ID: "1002", Type: admin.StreamTypeAndroid, DisplayName: "Android", PackageName: "com.example.app"},
+					},
+				},
+				{ID: "222", DisplayName: "Empty"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	printAccounts(&buf, accounts)
+
+	want := `アカウント 100: Example Inc.
+  プロパティ 111: Example Web
+    ストリーム 1001 [web] Web - https://www.example.com, G-EXAMPLE
+    ストリーム 1002 [android] Android - com.example.app
+  プロパティ 222: Empty
+    （データストリームなし）
+`
+	if buf.String() != want {
+		t.Errorf("printAccounts() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	printAccounts(&buf, nil)
+	if buf.String() != "アクセスできるアカウントがありません\n" {
+		t.Errorf("printAccounts(nil) = %q", buf.String())
+	}
+}
+
+func TestCLIApp_SaveDiscovered_MultipleAccounts(t *testing.T) {
+	app := NewCLIApp()
+	app.initializeServices()
+	path := filepath.Join(t.TempDir(), "ga.yaml")
+
+	err := app.saveDiscovered(path, []admin.Account{{ID: "100"}, {ID: "200"}})
+	if _, ok := err.(*usageError); !ok {
+		t.Errorf("saveDiscovered() error = %v, want usageError", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("複数のアカウントで設定ファイルが作成されています: %v", err)
+	}
+}
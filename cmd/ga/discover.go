@@ -0,0 +1,148 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ymotongpoo/ga/internal/admin"
+)
+
+// handleDiscover はAdmin APIでアカウント・プロパティ・データストリームを取得して表示する
+// --write を指定した場合は取得結果で設定ファイルを作成・更新する
+func (app *CLIApp) handleDiscover(ctx context.Context, args []string) error {
+	fs := newCommandFlagSet("discover")
+	configPath := fs.String("config", "ga.yaml", "設定ファイルのパス")
+	accountID := fs.String("account", "", "対象のアカウントID（省略時は設定ファイルの account、未設定の場合はすべてのアカウント）")
+	write := fs.Bool("write", false, "取得結果で設定ファイルを作成・更新する")
+	jsonOutput := fs.Bool("json", false, "取得結果をJSONで表示する")
+	if err := parseCommandFlags(fs, args); err != nil {
+		return err
+	}
+
+	account := *accountID
+	if account == "" {
+		if _, err := os.Stat(*configPath); err == nil {
+			cfg, err := app.configService.LoadConfig(*configPath)
+			if err != nil {
+				return fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
+			}
+			account = cfg.Account
+		}
+	}
+
+	token, err := app.getToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	service, err := admin.NewService(ctx, token)
+	if err != nil {
+		return fmt.Errorf("Admin APIサービスの初期化に失敗しました: %w", err)
+	}
+
+	accounts, err := service.Discover(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		data, err := json.MarshalIndent(accounts, "", "  ")
+		if err != nil {
+			return fmt.Errorf("取得結果のJSONへの変換に失敗しました: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Println()
+		printAccounts(os.Stdout, accounts)
+	}
+
+	if !*write {
+		return nil
+	}
+	return app.saveDiscovered(*configPath, accounts)
+}
+
+// saveDiscovered は取得したアカウントのプロパティとストリームを設定ファイルに反映する
+// 設定ファイルには1つのアカウントのみを記述できるため、複数のアカウントが見つかった場合はエラーにする
+func (app *CLIApp) saveDiscovered(path string, accounts []admin.Account) error {
+	switch len(accounts) {
+	case 0:
+		return fmt.Errorf("アクセスできるアカウントがありません")
+	case 1:
+	default:
+		var ids []string
+		for _, account := range accounts {
+			ids = append(ids, account.ID)
+		}
+		return &usageError{err: fmt.Errorf("複数のアカウント（%s）が見つかりました。--account で対象のアカウントを指定してください", strings.Join(ids, ", "))}
+	}
+
+	changes, err := app.configService.SaveDiscovered(path, accounts[0].ID, admin.ConfigProperties(accounts[0]))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if len(changes) == 0 {
+		fmt.Printf("設定ファイル '%s' に変更はありません\n", path)
+		return nil
+	}
+	for _, change := range changes {
+		fmt.Printf("  - %s\n", change)
+	}
+	fmt.Printf("✅ 設定ファイル '%s' を更新しました\n", path)
+	return nil
+}
+
+// printAccounts はアカウント・プロパティ・データストリームを階層構造で表示する
+func printAccounts(w io.Writer, accounts []admin.Account) {
+	if len(accounts) == 0 {
+		fmt.Fprintln(w, "アクセスできるアカウントがありません")
+		return
+	}
+
+	for _, account := range accounts {
+		fmt.Fprintf(w, "アカウント %s: %s\n", account.ID, account.DisplayName)
+		for _, property := range account.Properties {
+			fmt.Fprintf(w, "  プロパティ %s: %s\n", property.ID, property.DisplayName)
+			if len(property.Streams) == 0 {
+				fmt.Fprintln(w, "    （データストリームなし）")
+			}
+			for _, stream := range property.Streams {
+				fmt.Fprintf(w, "    ストリーム %s [%s] %s%s\n", stream.ID, stream.Type, stream.DisplayName, streamDetail(stream))
+			}
+		}
+	}
+}
+
+// streamDetail はデータストリームの種類ごとの識別情報（URL、パッケージ名など）を表示用に返す
+func streamDetail(stream admin.DataStream) string {
+	var details []string
+	for _, detail := range []string{stream.DefaultURI, stream.MeasurementID, stream.PackageName, stream.BundleID} {
+		if detail != "" {
+			details = append(details, detail)
+		}
+	}
+	if len(details) == 0 {
+		return ""
+	}
+	return " - " + strings.Join(details, ", ")
+}
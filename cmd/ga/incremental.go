@@ -0,0 +1,91 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"github.com/ymotongpoo/ga/internal/incremental"
+	"github.com/ymotongpoo/ga/internal/output"
+)
+
+// incrementalRun は差分取得の状態と、今回取得するストリーム・期間を表す構造体
+type incrementalRun struct {
+	statePath string
+	state     *incremental.State
+	fetches   []incremental.Fetch
+}
+
+// planIncremental は incremental が指定されている場合に状態ファイルを読み込み、取得する期間を決める
+// --full-refresh を指定した場合と出力ファイルがない場合は状態を使わずに期間全体を取得する
+// incremental が指定されていない場合はnilを返す
+func planIncremental(cfg *config.Config, options *CLIOptions) (*incrementalRun, error) {
+	if cfg.Incremental == nil {
+		return nil, nil
+	}
+
+	run := &incrementalRun{statePath: cfg.Incremental.StateFileOrDefault(options.OutputPath)}
+
+	_, statErr := os.Stat(options.OutputPath)
+	switch {
+	case options.FullRefresh:
+		fmt.Printf("--full-refresh が指定されたため、期間全体を取得して '%s' を作り直します\n", options.OutputPath)
+		run.state = incremental.NewState()
+	case os.IsNotExist(statErr):
+		fmt.Printf("出力ファイル '%s' がないため、期間全体を取得します\n", options.OutputPath)
+		run.state = incremental.NewState()
+	default:
+		state, err := incremental.Load(run.statePath)
+		if err != nil {
+			return nil, err
+		}
+		run.state = state
+	}
+
+	fetches, err := run.state.Plan(cfg, cfg.Incremental.OverlapDaysOrDefault())
+	if err != nil {
+		return nil, fmt.Errorf("差分取得の期間の決定に失敗しました: %w", err)
+	}
+	run.fetches = fetches
+	return run, nil
+}
+
+// replacements は既存の出力から置き換えるストリームと期間を返す
+// 取得に失敗したストリームは既存の行を残すため含めない
+func replacements(fetches []incremental.Fetch) []output.Replacement {
+	replacements := make([]output.Replacement, 0, len(fetches))
+	for _, fetch := range fetches {
+		replacements = append(replacements, output.Replacement{
+			PropertyID: fetch.PropertyID,
+			StreamID:   fetch.StreamID,
+			StartDate:  fetch.StartDate,
+			EndDate:    fetch.EndDate,
+		})
+	}
+	return replacements
+}
+
+// save は出力に反映したストリームの取得済みの最終日を状態ファイルに記録する
+func (r *incrementalRun) save(fetches []incremental.Fetch) error {
+	r.state.Record(fetches, time.Now())
+	if err := r.state.Save(r.statePath); err != nil {
+		return err
+	}
+	fmt.Printf("差分取得の状態を保存しました: %s\n", r.statePath)
+	return nil
+}
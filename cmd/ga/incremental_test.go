@@ -0,0 +1,128 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"github.com/ymotongpoo/ga/internal/incremental"
+)
+
+// incrementalTestConfig は差分取得のテスト用の解決済みの設定を作成する
+func incrementalTestConfig() *config.Config {
+	return &config.Config{
+		StartDate:   "2024-01-01",
+		EndDate:     "2024-01-31",
+		Properties:  []config.Property{{ID: "111", Streams: []config.Stream{{ID: "1"}}}},
+		Incremental: &config.Incremental{},
+	}
+}
+
+// writeIncrementalState は取得済みの最終日を記録した状態ファイルと出力ファイルを作成する
+func writeIncrementalState(t *testing.T, outputPath, lastDate string) {
+	t.Helper()
+
+	if err := os.WriteFile(outputPath, []byte("property_id,stream_id,date,sessions\n"), 0644); err != nil {
+		t.Fatalf("出力ファイルの作成に失敗しました: %v", err)
+	}
+	state := incremental.NewState()
+	state.Record([]incremental.Fetch{{PropertyID: "111", StreamID: "1", EndDate: lastDate}}, time.Now())
+	if err := state.Save(outputPath + ".state.json"); err != nil {
+		t.Fatalf("状態ファイルの作成に失敗しました: %v", err)
+	}
+}
+
+func TestPlanIncremental(t *testing.T) {
+	testCases := []struct {
+		name          string
+		fullRefresh   bool
+		writeOutput   bool
+		wantStartDate string
+	}{
+		{name: "状態ファイルから再開", writeOutput: true, wantStartDate: "2024-01-18"},
+		{name: "--full-refresh", writeOutput: true, fullRefresh: true, wantStartDate: "2024-01-01"},
+		{name: "出力ファイルがない", wantStartDate: "2024-01-01"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "daily.csv")
+			if tc.writeOutput {
+				writeIncrementalState(t, outputPath, "2024-01-20")
+			}
+
+			run, err := planIncremental(incrementalTestConfig(), &CLIOptions{OutputPath: outputPath, FullRefresh: tc.fullRefresh})
+			if err != nil {
+				t.Fatalf("planIncremental() error = %v", err)
+			}
+			if len(run.fetches) != 1 || run.fetches[0].StartDate != tc.wantStartDate {
+				t.Errorf("fetches = %+v, want start %s", run.fetches, tc.wantStartDate)
+			}
+			if run.statePath != outputPath+".state.json" {
+				t.Errorf("statePath = %s", run.statePath)
+			}
+		})
+	}
+}
+
+func TestPlanIncremental_Disabled(t *testing.T) {
+	cfg := incrementalTestConfig()
+	cfg.Incremental = nil
+
+	run, err := planIncremental(cfg, &CLIOptions{OutputPath: "daily.csv"})
+	if err != nil || run != nil {
+		t.Errorf("planIncremental() = %+v, %v, want nil", run, err)
+	}
+}
+
+func TestReplacements(t *testing.T) {
+	got := replacements([]incremental.Fetch{{PropertyID: "111", StreamID: "1", StartDate: "2024-01-29", EndDate: "2024-01-31"}})
+	if len(got) != 1 || got[0].PropertyID != "111" || got[0].StreamID != "1" || got[0].StartDate != "2024-01-29" || got[0].EndDate != "2024-01-31" {
+		t.Errorf("replacements() = %+v", got)
+	}
+}
+
+func TestCLIApp_Run_IncrementalRequiresOutput(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "ga.yaml")
+	content := `
+start_date: "2023-01-01"
+end_date: "2023-01-31"
+account: "123456789"
+incremental:
+  overlap_days: 2
+properties:
+  - property: "987654321"
+    streams:
+      - stream: "1234567"
+        dimensions:
+          - "date"
+        metrics:
+          - "sessions"
+`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatalf("設定ファイルの作成に失敗しました: %v", err)
+	}
+
+	app := NewCLIApp()
+	app.initializeServices()
+	if code := app.Run(context.Background(), []string{"--config", configFile}); code != 2 {
+		t.Errorf("Run() = %d, want 2", code)
+	}
+}
@@ -16,15 +16,24 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	stderrors "errors"
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/ymotongpoo/ga/internal/admin"
 	"github.com/ymotongpoo/ga/internal/analytics"
 	"github.com/ymotongpoo/ga/internal/auth"
 	"github.com/ymotongpoo/ga/internal/config"
 	"github.com/ymotongpoo/ga/internal/errors"
+	"github.com/ymotongpoo/ga/internal/incremental"
+	"github.com/ymotongpoo/ga/internal/metadata"
 	"github.com/ymotongpoo/ga/internal/output"
+	"golang.org/x/oauth2"
 )
 
 func main() {
@@ -75,8 +84,24 @@ func (app *CLIApp) initializeServices() error {
 	return nil
 }
 
+// exitCodePartialSuccess は --continue-on-error 指定時に一部のリクエストが失敗した場合の終了コード
+const exitCodePartialSuccess = 3
+
+// partialSuccessError は一部のリクエストが失敗したが、取得できたデータは出力したことを表すエラー
+type partialSuccessError struct {
+	failures []analytics.RequestFailure
+}
+
+// Error はerrorインターフェースの実装
+func (e *partialSuccessError) Error() string {
+	return fmt.Sprintf("%d 件のリクエストが失敗しました（取得できたデータのみ出力しました）", len(e.failures))
+}
+
 // getExitCodeFromError はエラーから適切な終了コードを取得する
 func (app *CLIApp) getExitCodeFromError(err error) int {
+	if _, ok := err.(*partialSuccessError); ok {
+		return exitCodePartialSuccess
+	}
 	if gaErr, ok := err.(*errors.GAError); ok {
 		switch gaErr.Type {
 		case errors.AuthError:
@@ -97,6 +122,11 @@ func (app *CLIApp) getExitCodeFromError(err error) int {
 // Run はCLIアプリケーションのメインエントリーポイント
 // 適切な終了コードを返す（0: 成功, 1: 一般的なエラー, 2: 使用方法エラー）
 func (app *CLIApp) Run(ctx context.Context, args []string) int {
+	// サブコマンドの処理
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		return app.runCommand(ctx, args[0], args[1:])
+	}
+
 	options, err := app.parseArgs(args)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -133,7 +163,11 @@ func (app *CLIApp) Run(ctx context.Context, args []string) int {
 	// デフォルト動作: データ取得
 	if err := app.handleDataRetrieval(ctx, options); err != nil {
 		exitCode := app.getExitCodeFromError(err)
-		fmt.Fprintf(os.Stderr, "データ取得エラー: %v\n", err)
+		if exitCode == exitCodePartialSuccess {
+			fmt.Fprintf(os.Stderr, "⚠️  一部のデータ取得に失敗しました: %v\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "データ取得エラー: %v\n", err)
+		}
 		return exitCode
 	}
 
@@ -158,6 +192,30 @@ func (app *CLIApp) parseArgs(args []string) (*CLIOptions, error) {
 	fs.BoolVar(&options.Help, "help", false, "ヘルプを表示する")
 	fs.BoolVar(&options.Version, "version", false, "バージョン情報を表示する")
 	fs.BoolVar(&options.Login, "login", false, "OAuth認証を実行する")
+	fs.StringVar(&options.StartDate, "start-date", "", "集計開始日（設定ファイルの start_date を上書き）")
+	fs.StringVar(&options.EndDate, "end-date", "", "集計終了日（設定ファイルの end_date を上書き）")
+	fs.StringVar(&options.DateRange, "date-range", "", "名前付き期間（設定ファイルの date_range を上書き）")
+	fs.StringVar(&options.Timezone, "timezone", "", "日付を解決するタイムゾーン（設定ファイルの timezone を上書き）")
+	fs.IntVar(&options.MaxInFlight, "max-in-flight", 0, "全体で同時に実行するAPI呼び出しの最大数（設定ファイルの concurrency.max_in_flight を上書き）")
+	fs.IntVar(&options.MaxInFlightPerProperty, "max-in-flight-per-property", 0, "プロパティごとに同時に実行するAPI呼び出しの最大数")
+	fs.Float64Var(&options.RequestsPerSecond, "rps", 0, "1秒あたりのAPI呼び出し数の上限（設定ファイルの concurrency.requests_per_second を上書き）")
+	fs.BoolVar(&options.ContinueOnError, "continue-on-error", false, "一部のリクエストが失敗しても取得できたデータを出力する")
+	fs.StringVar(&options.FailureReportPath, "failure-report", "", "失敗したリクエストの一覧を書き出すJSONファイルのパス")
+	fs.BoolVar(&options.Strict, "strict", false, "サンプリングまたはしきい値が適用されたデータを取得した場合は失敗する")
+	fs.StringVar(&options.SchemaMode, "schema-mode", "", "ストリームごとに列が異なる場合の出力方法 (union または split)")
+	fs.StringVar(&options.NullValue, "null-value", "", "union で列を持たないストリームの値（設定ファイルの null_value を上書き）")
+	fs.IntVar(&options.MaxRetries, "max-retries", 0, "API呼び出しの最大リトライ回数（設定ファイルの retry.max_retries を上書き）")
+	fs.DurationVar(&options.RetryBaseDelay, "retry-base-delay", 0, "最初のリトライまでの待機時間（例: 1s）")
+	fs.DurationVar(&options.RetryMaxDelay, "retry-max-delay", 0, "リトライの待機時間の上限（例: 30s）")
+	fs.Float64Var(&options.RetryBackoffFactor, "retry-backoff-factor", 0, "リトライごとに待機時間を増やす倍率")
+	fs.Float64Var(&options.RetryJitter, "retry-jitter", 0, "待機時間をランダムに短くする最大の割合 (0から1)")
+	fs.StringVar(&options.RetryCodes, "retry-codes", "", "リトライするHTTPステータスコードのカンマ区切りリスト（例: 429,500,503）")
+	fs.DurationVar(&options.RetryBudget, "retry-budget", 0, "1回の実行でリトライの待機に使う合計時間の上限（例: 5m）")
+	fs.StringVar(&options.Locale, "locale", "", "CSVのメトリクスの桁区切りと小数点を決めるロケール（例: ja-JP, de-DE）")
+	fs.IntVar(&options.DecimalPlaces, "decimal-places", 0, "CSVの小数のメトリクスを丸める桁数")
+	fs.StringVar(&options.DurationFormat, "duration-format", "", "CSVの時間のメトリクスの書式 (seconds または hms)")
+	fs.BoolVar(&options.FullRefresh, "full-refresh", false, "incremental の状態を使わずに期間全体を取得し、出力ファイルを作り直す")
+	fs.BoolVar(&options.NoCache, "no-cache", false, "レスポンスのキャッシュを使わずにAPIから取得する")
 
 	// 短縮形のフラグも追加
 	fs.BoolVar(&options.Help, "h", false, "ヘルプを表示する")
@@ -177,6 +235,37 @@ func (app *CLIApp) parseArgs(args []string) (*CLIOptions, error) {
 		return nil, fmt.Errorf("設定ファイルパスが指定されていません")
 	}
 
+	// 空文字も null_value として指定できるよう、指定の有無を記録する
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "null-value":
+			options.NullValueSet = true
+		case "max-retries":
+			options.MaxRetriesSet = true
+		case "retry-jitter":
+			options.RetryJitterSet = true
+		case "decimal-places":
+			options.DecimalPlacesSet = true
+		}
+	})
+
+	// 同時実行数とレートの検証
+	if options.MaxInFlight < 0 || options.MaxInFlightPerProperty < 0 || options.RequestsPerSecond < 0 {
+		return nil, fmt.Errorf("--max-in-flight, --max-in-flight-per-property, --rps には0以上の値を指定してください")
+	}
+
+	// リトライ設定の検証（値の範囲は設定ファイルと同じ検証を読み込み後に行う）
+	if options.MaxRetries < 0 || options.RetryBaseDelay < 0 || options.RetryMaxDelay < 0 || options.RetryBackoffFactor < 0 || options.RetryBudget < 0 {
+		return nil, fmt.Errorf("--max-retries, --retry-base-delay, --retry-max-delay, --retry-backoff-factor, --retry-budget には0以上の値を指定してください")
+	}
+	if options.RetryCodes != "" {
+		codes, err := parseRetryCodes(options.RetryCodes)
+		if err != nil {
+			return nil, err
+		}
+		options.RetryableCodes = codes
+	}
+
 	// 出力形式の検証（ParseOutputFormatを使用して詳細なエラーメッセージを提供）
 	if _, err := output.ParseOutputFormat(options.OutputFormat); err != nil {
 		return nil, fmt.Errorf("出力形式エラー: %w", err)
@@ -191,6 +280,12 @@ func (app *CLIApp) showHelp() {
 	fmt.Println()
 	fmt.Println("使用方法:")
 	fmt.Println("  ga [オプション]")
+	fmt.Println("  ga <サブコマンド> [オプション]")
+	fmt.Println()
+	fmt.Println("サブコマンド:")
+	for _, cmd := range app.commands(context.Background()) {
+		fmt.Printf("  %-16s %s\n", cmd.Name, cmd.Description)
+	}
 	fmt.Println()
 	fmt.Println("オプション:")
 	fmt.Println("  --config PATH    設定ファイルのパス (デフォルト: ga.yaml)")
@@ -198,6 +293,31 @@ func (app *CLIApp) showHelp() {
 	fmt.Println("  --format FORMAT  出力形式 (csv または json, デフォルト: csv)")
 	fmt.Println("  --debug          デバッグモードを有効にする")
 	fmt.Println("  --login          OAuth認証を実行する")
+	fmt.Println("  --start-date DATE  集計開始日 (YYYY-MM-DD, today, yesterday, NdaysAgo)")
+	fmt.Println("  --end-date DATE    集計終了日 (YYYY-MM-DD, today, yesterday, NdaysAgo)")
+	fmt.Println("  --date-range NAME  名前付き期間 (last_7_days, last_week, last_month,")
+	fmt.Println("                     month_to_date, last_quarter, year_to_date)")
+	fmt.Println("  --timezone TZ      日付を解決するタイムゾーン (例: Asia/Tokyo)")
+	fmt.Println("  --max-in-flight N  全体で同時に実行するAPI呼び出しの最大数 (デフォルト: 10)")
+	fmt.Println("  --max-in-flight-per-property N  プロパティごとの同時実行数 (デフォルト: 5)")
+	fmt.Println("  --rps N            1秒あたりのAPI呼び出し数の上限 (デフォルト: 10)")
+	fmt.Println("  --continue-on-error  一部のリクエストが失敗しても取得できたデータを出力する (終了コード 3)")
+	fmt.Println("  --failure-report PATH  失敗したリクエストの一覧をJSONで書き出す")
+	fmt.Println("  --strict         サンプリングまたはしきい値が適用されたデータを取得した場合は失敗する")
+	fmt.Println("  --schema-mode MODE  ストリームごとに列が異なる場合の出力方法 (union または split, デフォルト: union)")
+	fmt.Println("  --null-value VALUE  union で列を持たないストリームの値 (デフォルト: 空文字)")
+	fmt.Println("  --max-retries N    API呼び出しの最大リトライ回数 (デフォルト: 3)")
+	fmt.Println("  --retry-base-delay D  最初のリトライまでの待機時間 (デフォルト: 1s)")
+	fmt.Println("  --retry-max-delay D   リトライの待機時間の上限 (デフォルト: 30s)")
+	fmt.Println("  --retry-backoff-factor F  リトライごとに待機時間を増やす倍率 (デフォルト: 2)")
+	fmt.Println("  --retry-jitter F      待機時間をランダムに短くする最大の割合 (デフォルト: 0.2)")
+	fmt.Println("  --retry-codes LIST    リトライするHTTPステータスコード (例: 429,503)")
+	fmt.Println("  --retry-budget D      リトライの待機に使う合計時間の上限 (例: 5m)")
+	fmt.Println("  --locale LOCALE    CSVのメトリクスの桁区切りと小数点 (例: ja-JP, de-DE)")
+	fmt.Println("  --decimal-places N CSVの小数のメトリクスを丸める桁数")
+	fmt.Println("  --duration-format FORMAT  CSVの時間のメトリクスの書式 (seconds または hms)")
+	fmt.Println("  --full-refresh     incremental の状態を使わずに期間全体を取得し、出力ファイルを作り直す")
+	fmt.Println("  --no-cache         レスポンスのキャッシュを読み書きせずにAPIから取得する")
 	fmt.Println("  --help, -h       このヘルプメッセージを表示する")
 	fmt.Println("  --version, -v    バージョン情報を表示する")
 	fmt.Println()
@@ -208,6 +328,16 @@ func (app *CLIApp) showHelp() {
 	fmt.Println("  ga --format json             # JSON形式で出力")
 	fmt.Println("  ga --output data.json --format json  # JSONファイルに出力")
 	fmt.Println("  ga --login                   # OAuth認証を実行")
+	fmt.Println("  ga --date-range last_month   # 先月のデータを取得")
+	fmt.Println("  ga --max-in-flight 4 --rps 2 # 同時実行数とAPI呼び出しの頻度を抑えて取得")
+	fmt.Println("  ga --continue-on-error --failure-report failures.json  # 失敗したプロパティを除いて出力")
+	fmt.Println("  ga --output daily.csv --full-refresh  # incremental の出力ファイルを作り直す")
+	fmt.Println("  ga validate --offline        # キャッシュ済みのメタデータで設定を検証")
+	fmt.Println("  ga discover --write          # Admin APIで取得したプロパティとストリームで ga.yaml を作成・更新")
+	fmt.Println("  ga --no-cache                # キャッシュを使わずにAPIから取得")
+	fmt.Println("  ga cache clear               # レスポンスのキャッシュを削除")
+	fmt.Println("  ga realtime --interval 30s   # リアルタイムレポートを30秒ごとに更新表示")
+	fmt.Println("  ga realtime --interval 1m --output live.ndjson  # 1分ごとにNDJSONを追記")
 }
 
 // showVersion はバージョン情報を表示する
@@ -260,25 +390,47 @@ func (app *CLIApp) handleDataRetrieval(ctx context.Context, options *CLIOptions)
 		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
 	}
 
+	// コマンドラインオプションで設定を上書き
+	app.applyConfigOverrides(config, options)
+
 	// 設定の検証
 	if err := app.configService.ValidateConfig(config); err != nil {
 		return fmt.Errorf("設定ファイルの検証に失敗しました: %w", err)
 	}
+	if config.Incremental != nil && (options.OutputPath == "" || options.OutputPath == "-") {
+		return errors.NewConfigError("incremental を指定した場合は --output で出力ファイルを指定してください", nil)
+	}
 
-	// 認証サービスを初期化（環境変数からOAuth設定を取得）
-	clientID := os.Getenv("GA_CLIENT_ID")
-	clientSecret := os.Getenv("GA_CLIENT_SECRET")
+	// 相対日付・名前付き期間を実行開始時点の日付に解決
+	if err := config.ResolveDates(time.Now()); err != nil {
+		return fmt.Errorf("期間の解決に失敗しました: %w", err)
+	}
+	fmt.Printf("集計期間: %s - %s\n", config.StartDate, config.EndDate)
 
-	if clientID == "" || clientSecret == "" {
-		return fmt.Errorf("OAuth認証に必要な環境変数が設定されていません。GA_CLIENT_ID と GA_CLIENT_SECRET を設定してください")
+	// 認証トークンを取得
+	token, err := app.getToken(ctx)
+	if err != nil {
+		return err
 	}
 
-	app.authService = auth.NewGoogleAnalyticsAuthService(clientID, clientSecret)
+	// properties: all の場合はアカウント配下のプロパティを解決
+	if err := app.resolveProperties(ctx, config, token); err != nil {
+		return err
+	}
 
-	// 認証トークンを取得
-	token, err := app.authService.GetCredentials(ctx)
+	// 差分取得の場合は前回の取得以降の期間のみを取得する
+	run, err := planIncremental(config, options)
 	if err != nil {
-		return fmt.Errorf("認証トークンの取得に失敗しました。'ga --login' で認証を行ってください: %w", err)
+		return err
+	}
+	if run != nil && len(run.fetches) == 0 {
+		fmt.Println("新しく取得するデータはありません")
+		return nil
+	}
+
+	// ディメンションとメトリクスをプロパティのメタデータで検証
+	if err := app.validateMetadata(ctx, config, token); err != nil {
+		return err
 	}
 
 	// 分析サービスを初期化
@@ -290,9 +442,23 @@ func (app *CLIApp) handleDataRetrieval(ctx context.Context, options *CLIOptions)
 	// データ取得
 	reportData, err := app.analyticsService.GetReportData(ctx, config)
 	if err != nil {
+		// 取得を中止した場合も、失敗したリクエストの一覧を書き出す
+		var fetchErr *analytics.FetchError
+		if options.FailureReportPath != "" && stderrors.As(err, &fetchErr) {
+			if err := writeFailureReport(options.FailureReportPath, fetchErr.Failures); err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+			}
+		}
 		return fmt.Errorf("データ取得に失敗しました: %w", err)
 	}
 
+	// 失敗したリクエストの一覧を書き出す（失敗がない場合は空の一覧）
+	if options.FailureReportPath != "" {
+		if err := writeFailureReport(options.FailureReportPath, reportData.Failures); err != nil {
+			return err
+		}
+	}
+
 	// 出力形式を解析
 	format, err := output.ParseOutputFormat(options.OutputFormat)
 	if err != nil {
@@ -300,14 +466,248 @@ func (app *CLIApp) handleDataRetrieval(ctx context.Context, options *CLIOptions)
 	}
 
 	// データ出力
-	if err := app.outputService.WriteOutput(reportData, options.OutputPath, format); err != nil {
+	app.outputService.SetNumberFormat(config.NumberFormat)
+	var succeeded []incremental.Fetch
+	if run != nil {
+		succeeded = incremental.Succeeded(run.fetches, reportData.Failures)
+	}
+	if run != nil && !options.FullRefresh {
+		err = app.outputService.MergeOutput(reportData, options.OutputPath, format, replacements(succeeded))
+	} else {
+		err = app.outputService.WriteOutput(reportData, options.OutputPath, format)
+	}
+	if err != nil {
 		return fmt.Errorf("データ出力に失敗しました: %w", err)
 	}
 
+	// 出力に反映したストリームの取得済みの最終日を記録する
+	if run != nil {
+		if err := run.save(succeeded); err != nil {
+			return err
+		}
+	}
+
 	fmt.Printf("データ取得が完了しました。取得レコード数: %d\n", reportData.Summary.TotalRows)
+
+	if len(reportData.Failures) > 0 {
+		return &partialSuccessError{failures: reportData.Failures}
+	}
+	return nil
+}
+
+// writeFailureReport は失敗したリクエストの一覧をJSONファイルに書き出す
+func writeFailureReport(path string, failures []analytics.RequestFailure) error {
+	if failures == nil {
+		failures = []analytics.RequestFailure{}
+	}
+
+	data, err := json.MarshalIndent(failures, "", "  ")
+	if err != nil {
+		return fmt.Errorf("失敗したリクエストの一覧の作成に失敗しました: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return errors.NewOutputError(fmt.Sprintf("失敗したリクエストの一覧 '%s' の書き込みに失敗しました", path), err)
+	}
 	return nil
 }
 
+// getToken は環境変数のOAuth設定で認証サービスを初期化し、保存済みの認証トークンを取得する
+func (app *CLIApp) getToken(ctx context.Context) (*oauth2.Token, error) {
+	clientID := os.Getenv("GA_CLIENT_ID")
+	clientSecret := os.Getenv("GA_CLIENT_SECRET")
+
+	if clientID == "" || clientSecret == "" {
+		return nil, fmt.Errorf("OAuth認証に必要な環境変数が設定されていません。GA_CLIENT_ID と GA_CLIENT_SECRET を設定してください")
+	}
+
+	app.authService = auth.NewGoogleAnalyticsAuthService(clientID, clientSecret)
+
+	token, err := app.authService.GetCredentials(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("認証トークンの取得に失敗しました。'ga --login' で認証を行ってください: %w", err)
+	}
+	return token, nil
+}
+
+// resolveProperties は properties: all の設定をAdmin APIでアカウント配下のプロパティとストリームに置き換える
+func (app *CLIApp) resolveProperties(ctx context.Context, cfg *config.Config, token *oauth2.Token) error {
+	if !cfg.PropertiesAll {
+		return nil
+	}
+
+	service, err := admin.NewService(ctx, token)
+	if err != nil {
+		return fmt.Errorf("Admin APIサービスの初期化に失敗しました: %w", err)
+	}
+	if err := service.ResolveProperties(ctx, cfg); err != nil {
+		return fmt.Errorf("プロパティの解決に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// validateMetadata はMetadata API（またはそのキャッシュ）で設定のディメンションとメトリクスを検証する
+func (app *CLIApp) validateMetadata(ctx context.Context, cfg *config.Config, token *oauth2.Token) error {
+	cache, err := newMetadataCache()
+	if err != nil {
+		return err
+	}
+
+	var source metadata.Source
+	if token == nil {
+		source = metadata.NewOfflineService(cache)
+	} else {
+		service, err := metadata.NewService(ctx, token, cache)
+		if err != nil {
+			return fmt.Errorf("メタデータサービスの初期化に失敗しました: %w", err)
+		}
+		source = service
+	}
+
+	return metadata.ValidateConfig(ctx, cfg, source)
+}
+
+// newMetadataCache は既定の保存先にメタデータキャッシュを作成する
+func newMetadataCache() (*metadata.Cache, error) {
+	dir, err := metadata.DefaultCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return metadata.NewCache(dir, metadata.DefaultTTL), nil
+}
+
+// applyConfigOverrides はコマンドラインオプションで設定ファイルの値を上書きする
+func (app *CLIApp) applyConfigOverrides(cfg *config.Config, options *CLIOptions) {
+	if options.DateRange != "" {
+		cfg.DateRange = options.DateRange
+		cfg.StartDate = ""
+		cfg.EndDate = ""
+	}
+	if options.StartDate != "" {
+		cfg.StartDate = options.StartDate
+		cfg.DateRange = ""
+	}
+	if options.EndDate != "" {
+		cfg.EndDate = options.EndDate
+		cfg.DateRange = ""
+	}
+	if options.Timezone != "" {
+		cfg.Timezone = options.Timezone
+	}
+
+	if options.ContinueOnError {
+		cfg.ContinueOnError = true
+	}
+	if options.Strict {
+		cfg.Strict = true
+	}
+	if options.SchemaMode != "" {
+		cfg.SchemaMode = options.SchemaMode
+	}
+	if options.NullValueSet {
+		cfg.NullValue = options.NullValue
+	}
+	if options.NoCache {
+		if cfg.Cache == nil {
+			cfg.Cache = &config.Cache{}
+		}
+		cfg.Cache.Disabled = true
+	}
+
+	applyRetryOverrides(cfg, options)
+	applyNumberFormatOverrides(cfg, options)
+
+	if options.MaxInFlight > 0 || options.MaxInFlightPerProperty > 0 || options.RequestsPerSecond > 0 {
+		if cfg.Concurrency == nil {
+			cfg.Concurrency = &config.Concurrency{}
+		}
+		if options.MaxInFlight > 0 {
+			cfg.Concurrency.MaxInFlight = options.MaxInFlight
+		}
+		if options.MaxInFlightPerProperty > 0 {
+			cfg.Concurrency.MaxInFlightPerProperty = options.MaxInFlightPerProperty
+		}
+		if options.RequestsPerSecond > 0 {
+			cfg.Concurrency.RequestsPerSecond = options.RequestsPerSecond
+		}
+	}
+}
+
+// applyNumberFormatOverrides は数値の書式に関するコマンドラインオプションで設定を上書きする
+func applyNumberFormatOverrides(cfg *config.Config, options *CLIOptions) {
+	if options.Locale == "" && !options.DecimalPlacesSet && options.DurationFormat == "" {
+		return
+	}
+
+	if cfg.NumberFormat == nil {
+		cfg.NumberFormat = &config.NumberFormat{}
+	}
+	if options.Locale != "" {
+		cfg.NumberFormat.Locale = options.Locale
+	}
+	if options.DecimalPlacesSet {
+		decimalPlaces := options.DecimalPlaces
+		cfg.NumberFormat.DecimalPlaces = &decimalPlaces
+	}
+	if options.DurationFormat != "" {
+		cfg.NumberFormat.Duration = options.DurationFormat
+	}
+}
+
+// applyRetryOverrides はリトライに関するコマンドラインオプションで設定を上書きする
+func applyRetryOverrides(cfg *config.Config, options *CLIOptions) {
+	if !options.MaxRetriesSet && !options.RetryJitterSet && options.RetryBaseDelay == 0 && options.RetryMaxDelay == 0 &&
+		options.RetryBackoffFactor == 0 && len(options.RetryableCodes) == 0 && options.RetryBudget == 0 {
+		return
+	}
+
+	if cfg.Retry == nil {
+		cfg.Retry = &config.Retry{}
+	}
+	if options.MaxRetriesSet {
+		maxRetries := options.MaxRetries
+		cfg.Retry.MaxRetries = &maxRetries
+	}
+	if options.RetryBaseDelay > 0 {
+		cfg.Retry.BaseDelay = options.RetryBaseDelay
+	}
+	if options.RetryMaxDelay > 0 {
+		cfg.Retry.MaxDelay = options.RetryMaxDelay
+	}
+	if options.RetryBackoffFactor > 0 {
+		cfg.Retry.BackoffFactor = options.RetryBackoffFactor
+	}
+	if options.RetryJitterSet {
+		jitter := options.RetryJitter
+		cfg.Retry.Jitter = &jitter
+	}
+	if len(options.RetryableCodes) > 0 {
+		cfg.Retry.RetryableCodes = options.RetryableCodes
+	}
+	if options.RetryBudget > 0 {
+		cfg.Retry.Budget = options.RetryBudget
+	}
+}
+
+// parseRetryCodes は --retry-codes のカンマ区切りのHTTPステータスコードを解析する
+func parseRetryCodes(value string) ([]int, error) {
+	var codes []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		code, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("--retry-codes: %s はHTTPステータスコードではありません", part)
+		}
+		codes = append(codes, code)
+	}
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("--retry-codes にHTTPステータスコードを指定してください")
+	}
+	return codes, nil
+}
+
 // CLIOptions はコマンドライン引数を表す構造体
 type CLIOptions struct {
 	ConfigPath   string
@@ -317,6 +717,41 @@ type CLIOptions struct {
 	Help         bool
 	Version      bool
 	Login        bool
+	StartDate    string
+	EndDate      string
+	DateRange    string
+	Timezone     string
+
+	MaxInFlight            int     // 全体で同時に実行するAPI呼び出しの最大数（0は設定ファイルの値）
+	MaxInFlightPerProperty int     // プロパティごとに同時に実行するAPI呼び出しの最大数（0は設定ファイルの値）
+	RequestsPerSecond      float64 // 1秒あたりのAPI呼び出し数の上限（0は設定ファイルの値）
+
+	ContinueOnError   bool   // 一部のリクエストが失敗しても取得できたデータを出力する
+	FailureReportPath string // 失敗したリクエストの一覧を書き出すJSONファイルのパス
+	Strict            bool   // サンプリングまたはしきい値が適用されたデータを取得した場合は失敗する
+
+	SchemaMode   string // ストリームごとに列が異なる場合の出力方法（空は設定ファイルの値）
+	NullValue    string // union で列を持たないストリームの値
+	NullValueSet bool   // --null-value が指定されたかどうか
+
+	MaxRetries         int           // 最大リトライ回数
+	MaxRetriesSet      bool          // --max-retries が指定されたかどうか（0回も指定できるようにする）
+	RetryBaseDelay     time.Duration // 最初のリトライまでの待機時間（0は設定ファイルの値）
+	RetryMaxDelay      time.Duration // リトライの待機時間の上限（0は設定ファイルの値）
+	RetryBackoffFactor float64       // リトライごとに待機時間を増やす倍率（0は設定ファイルの値）
+	RetryJitter        float64       // 待機時間をランダムに短くする最大の割合
+	RetryJitterSet     bool          // --retry-jitter が指定されたかどうか
+	RetryCodes         string        // --retry-codes に指定された文字列
+	RetryableCodes     []int         // RetryCodes を解析したHTTPステータスコード
+	RetryBudget        time.Duration // リトライの待機に使う合計時間の上限（0は設定ファイルの値）
+
+	Locale           string // CSVのメトリクスの桁区切りと小数点を決めるロケール（空は設定ファイルの値）
+	DecimalPlaces    int    // CSVの小数のメトリクスを丸める桁数
+	DecimalPlacesSet bool   // --decimal-places が指定されたかどうか（0桁も指定できるようにする）
+	DurationFormat   string // CSVの時間のメトリクスの書式（空は設定ファイルの値）
+
+	FullRefresh bool // incremental の状態を使わずに期間全体を取得し、出力ファイルを作り直す
+	NoCache     bool // レスポンスのキャッシュを読み書きしない
 }
 
 // Command はサブコマンドを表す構造体
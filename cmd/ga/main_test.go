@@ -22,6 +22,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/ymotongpoo/ga/internal/analytics"
 	gaerrors "github.com/ymotongpoo/ga/internal/errors"
 )
 
@@ -103,6 +104,11 @@ func TestCLIApp_getExitCodeFromError(t *testing.T) {
 			err:      errors.New("generic error"),
 			wantCode: 1,
 		},
+		{
+			name:     "Partial success",
+			err:      &partialSuccessError{failures: []analytics.RequestFailure{{PropertyID: "123"}}},
+			wantCode: exitCodePartialSuccess,
+		},
 	}
 
 	for _, tt := range tests {
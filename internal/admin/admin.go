@@ -0,0 +1,289 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admin はGoogle Analytics Admin APIでアカウント・プロパティ・データストリームの一覧を取得する
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/analyticsadmin/v1beta"
+	"google.golang.org/api/option"
+)
+
+// データストリームの種類
+const (
+	StreamTypeWeb     = "web"
+	StreamTypeAndroid = "android"
+	StreamTypeIOS     = "ios"
+)
+
+// streamTypes はAdmin APIのデータストリームの種類と表示用の名前の対応
+var streamTypes = map[string]string{
+	"WEB_DATA_STREAM":         StreamTypeWeb,
+	"ANDROID_APP_DATA_STREAM": StreamTypeAndroid,
+	"IOS_APP_DATA_STREAM":     StreamTypeIOS,
+}
+
+// Account はGoogle Analytics アカウントとそのプロパティを表す構造体
+type Account struct {
+	ID          string     `json:"id"`
+	DisplayName string     `json:"display_name"`
+	Properties  []Property `json:"properties"`
+}
+
+// Property はGA4プロパティとそのデータストリームを表す構造体
+type Property struct {
+	ID          string       `json:"id"`
+	DisplayName string       `json:"display_name"`
+	Streams     []DataStream `json:"streams"`
+}
+
+// DataStream はデータストリームを表す構造体
+type DataStream struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"` // web, android, ios のいずれか
+	DisplayName string `json:"display_name"`
+
+	DefaultURI    string `json:"default_uri,omitempty"`    // ウェブストリームのURL
+	MeasurementID string `json:"measurement_id,omitempty"` // ウェブストリームの測定ID（G-XXXXXXX）
+	PackageName   string `json:"package_name,omitempty"`   // Androidアプリのパッケージ名
+	BundleID      string `json:"bundle_id,omitempty"`      // iOSアプリのバンドルID
+}
+
+// Service はAdmin APIからアカウント・プロパティ・データストリームを取得する
+type Service struct {
+	admin *analyticsadmin.Service
+}
+
+// NewService はAdmin APIを利用する新しいServiceを作成する
+func NewService(ctx context.Context, token *oauth2.Token) (*Service, error) {
+	tokenSource := oauth2.StaticTokenSource(token)
+
+	admin, err := analyticsadmin.NewService(ctx, option.WithTokenSource(tokenSource))
+	if err != nil {
+		return nil, fmt.Errorf("Analytics Admin APIサービスの作成に失敗しました: %w", err)
+	}
+
+	return newService(admin), nil
+}
+
+// newService はServiceを作成する
+func newService(admin *analyticsadmin.Service) *Service {
+	return &Service{admin: admin}
+}
+
+// Discover はアクセスできるアカウントと、その配下のプロパティ・データストリームを取得する
+// accountIDを指定した場合はそのアカウントのみを対象とする
+func (s *Service) Discover(ctx context.Context, accountID string) ([]Account, error) {
+	accounts, err := s.listAccounts(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range accounts {
+		for j := range accounts[i].Properties {
+			property := &accounts[i].Properties[j]
+			streams, err := s.listDataStreams(ctx, property.ID)
+			if err != nil {
+				return nil, err
+			}
+			property.Streams = streams
+		}
+	}
+
+	return accounts, nil
+}
+
+// ResolveProperties は properties: all の設定を、account 配下で all_properties の条件に一致する
+// プロパティとそのデータストリームに置き換える（各ストリームには all_properties.template を適用する）
+func (s *Service) ResolveProperties(ctx context.Context, cfg *config.Config) error {
+	if !cfg.PropertiesAll {
+		return nil
+	}
+
+	accounts, err := s.listAccounts(ctx, cfg.Account)
+	if err != nil {
+		return err
+	}
+
+	var properties []config.Property
+	for _, property := range accounts[0].Properties {
+		matched, err := cfg.AllProperties.Matches(property.DisplayName)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+
+		streams, err := s.listDataStreams(ctx, property.ID)
+		if err != nil {
+			return err
+		}
+
+		cp := config.Property{ID: property.ID}
+		for _, stream := range streams {
+			var defaultURL string
+			if stream.Type == StreamTypeWeb {
+				defaultURL = baseURL(stream.DefaultURI)
+			}
+			cp.Streams = append(cp.Streams, cfg.AllProperties.StreamFor(stream.ID, defaultURL))
+
+			// プロパティ全体のデータを取得する場合は、同じデータを重複して取得しないよう1つのストリームのみにする
+			if cfg.AllProperties.Template.PropertyWide {
+				break
+			}
+		}
+		if len(cp.Streams) == 0 {
+			fmt.Printf("⚠️  プロパティ %s（%s）にはデータストリームがないため対象外にします\n", property.ID, property.DisplayName)
+			continue
+		}
+
+		fmt.Printf("  - プロパティ %s: %s（ストリーム %d 件）\n", property.ID, property.DisplayName, len(cp.Streams))
+		properties = append(properties, cp)
+	}
+
+	if len(properties) == 0 {
+		return fmt.Errorf("アカウント %s に all_properties の条件に一致するプロパティがありません", cfg.Account)
+	}
+
+	fmt.Printf("対象プロパティ: %d 件\n", len(properties))
+	cfg.Properties = properties
+	return nil
+}
+
+// listAccounts はアクセスできるアカウントとプロパティの一覧を取得する（データストリームは含まない）
+// accountIDを指定した場合はそのアカウントのみを返し、見つからない場合はエラーにする
+func (s *Service) listAccounts(ctx context.Context, accountID string) ([]Account, error) {
+	var accounts []Account
+
+	fmt.Println("アカウントとプロパティの一覧を取得中...")
+	err := s.admin.AccountSummaries.List().PageSize(200).Pages(ctx, func(response *analyticsadmin.GoogleAnalyticsAdminV1betaListAccountSummariesResponse) error {
+		for _, summary := range response.AccountSummaries {
+			account := Account{ID: resourceID(summary.Account), DisplayName: summary.DisplayName}
+			if accountID != "" && account.ID != accountID {
+				continue
+			}
+			for _, ps := range summary.PropertySummaries {
+				account.Properties = append(account.Properties, Property{ID: resourceID(ps.Property), DisplayName: ps.DisplayName})
+			}
+			accounts = append(accounts, account)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("アカウントの一覧の取得に失敗しました: %w", err)
+	}
+	if accountID != "" && len(accounts) == 0 {
+		return nil, fmt.Errorf("アカウント %s が見つかりません（アクセス権があるか確認してください）", accountID)
+	}
+
+	return accounts, nil
+}
+
+// listDataStreams はプロパティのデータストリームの一覧を取得する
+func (s *Service) listDataStreams(ctx context.Context, propertyID string) ([]DataStream, error) {
+	fmt.Printf("プロパティ %s のデータストリームを取得中...\n", propertyID)
+
+	var streams []DataStream
+	parent := fmt.Sprintf("properties/%s", propertyID)
+	err := s.admin.Properties.DataStreams.List(parent).PageSize(200).Pages(ctx, func(response *analyticsadmin.GoogleAnalyticsAdminV1betaListDataStreamsResponse) error {
+		for _, ds := range response.DataStreams {
+			streams = append(streams, newDataStream(ds))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("プロパティ %s のデータストリームの取得に失敗しました: %w", propertyID, err)
+	}
+	return streams, nil
+}
+
+// newDataStream はAdmin APIのデータストリームをDataStreamに変換する
+func newDataStream(ds *analyticsadmin.GoogleAnalyticsAdminV1betaDataStream) DataStream {
+	stream := DataStream{
+		ID:          resourceID(ds.Name),
+		Type:        streamTypes[ds.Type],
+		DisplayName: ds.DisplayName,
+	}
+	if stream.Type == "" {
+		stream.Type = strings.ToLower(ds.Type)
+	}
+
+	if web := ds.WebStreamData; web != nil {
+		stream.DefaultURI = web.DefaultUri
+		stream.MeasurementID = web.MeasurementId
+	}
+	if android := ds.AndroidAppStreamData; android != nil {
+		stream.PackageName = android.PackageName
+	}
+	if ios := ds.IosAppStreamData; ios != nil {
+		stream.BundleID = ios.BundleId
+	}
+	return stream
+}
+
+// resourceID はリソース名（例: properties/123/dataStreams/456）の末尾のIDを返す
+func resourceID(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// 新しく追加するストリームの既定のディメンションとメトリクス（追加後に必要に応じて変更する）
+var (
+	defaultWebDimensions = []string{"date", "pagePath"}
+	defaultAppDimensions = []string{"date", "unifiedScreenName"}
+	defaultMetrics       = []string{"sessions", "activeUsers", "screenPageViews"}
+)
+
+// ConfigProperties はアカウントのプロパティとデータストリームを設定ファイルのプロパティに変換する
+// ウェブストリームの defaultUri は base_url に設定する。データストリームのないプロパティは含めない
+func ConfigProperties(account Account) []config.Property {
+	var properties []config.Property
+	for _, property := range account.Properties {
+		cp := config.Property{ID: property.ID}
+		for _, stream := range property.Streams {
+			cs := config.Stream{
+				ID:         stream.ID,
+				Dimensions: defaultAppDimensions,
+				Metrics:    defaultMetrics,
+			}
+			if stream.Type == StreamTypeWeb {
+				cs.BaseURL = baseURL(stream.DefaultURI)
+				cs.Dimensions = defaultWebDimensions
+			}
+			cp.Streams = append(cp.Streams, cs)
+		}
+		if len(cp.Streams) > 0 {
+			properties = append(properties, cp)
+		}
+	}
+	return properties
+}
+
+// baseURL はウェブストリームの defaultUri を base_url の形式（http:// または https:// で始まり、末尾の / なし）にする
+func baseURL(defaultURI string) string {
+	uri := strings.TrimRight(strings.TrimSpace(defaultURI), "/")
+	if uri == "" {
+		return ""
+	}
+	if !strings.HasPrefix(uri, "http://") && !strings.HasPrefix(uri, "https://") {
+		uri = "https://" + uri
+	}
+	return uri
+}
@@ -0,0 +1,305 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"google.golang.org/api/analyticsadmin/v1beta"
+	"google.golang.org/api/option"
+)
+
+// newTestService はテスト用のAdmin APIサーバーに接続するServiceを作成する
+func newTestService(t *testing.T, handler http.HandlerFunc) *Service {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	admin, err := analyticsadmin.NewService(context.Background(),
+		option.WithEndpoint(server.URL+"/"),
+		option.WithHTTPClient(server.Client()),
+	)
+	if err != nil {
+		t.Fatalf("analyticsadmin.NewService() error = %v", err)
+	}
+	return newService(admin)
+}
+
+// fakeAdminAPI はアカウントの一覧（2ページ）とデータストリームを返すAdmin APIのハンドラー
+func fakeAdminAPI(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var response any
+		switch r.URL.Path {
+		case "/v1beta/accountSummaries":
+			if r.URL.Query().Get("pageToken") == "" {
+				response = &analyticsadmin.GoogleAnalyticsAdminV1betaListAccountSummariesResponse{
+					AccountSummaries: []*analyticsadmin.GoogleAnalyticsAdminV1betaAccountSummary{
+						{
+							Account:     "accounts/100",
+							DisplayName: "Example Inc.",
+							PropertySummaries: []*analyticsadmin.GoogleAnalyticsAdminV1betaPropertySummary{
+								{Property: "properties/111", DisplayName: "Example Web"},
+								{Property: "properties/222", DisplayName: "Example App"},
+							},
+						},
+					},
+					NextPageToken: "page2",
+				}
+			} else {
+				response = &analyticsadmin.GoogleAnalyticsAdminV1betaListAccountSummariesResponse{
+					AccountSummaries: []*analyticsadmin.GoogleAnalyticsAdminV1betaAccountSummary{
+						{
+							Account:     "accounts/200",
+							DisplayName: "Other Inc.",
+							PropertySummaries: []*analyticsadmin.GoogleAnalyticsAdminV1betaPropertySummary{
+								{Property: "properties/333", DisplayName: "Other Web"},
+							},
+						},
+					},
+				}
+			}
+		case "/v1beta/properties/111/dataStreams":
+			response = &analyticsadmin.GoogleAnalyticsAdminV1betaListDataStreamsResponse{
+				DataStreams: []*analyticsadmin.GoogleAnalyticsAdminV1betaDataStream{
+					{
+						Name:        "properties/111/dataStreams/1001",
+						Type:        "WEB_DATA_STREAM",
+						DisplayName: "Web",
+						WebStreamData: &analyticsadmin.GoogleAnalyticsAdminV1betaDataStreamWebStreamData{
+							DefaultUri:    "www.example.com/",
+							MeasurementId: "G-EXAMPLE",
+						},
+					},
+				},
+			}
+		case "/v1beta/properties/222/dataStreams":
+			response = &analyticsadmin.GoogleAnalyticsAdminV1betaListDataStreamsResponse{
+				DataStreams: []*analyticsadmin.GoogleAnalyticsAdminV1betaDataStream{
+					{
+						Name:        "properties/222/dataStreams/2001",
+						Type:        "ANDROID_APP_DATA_STREAM",
+						DisplayName: "Android",
+						AndroidAppStreamData: &analyticsadmin.GoogleAnalyticsAdminV1betaDataStreamAndroidAppStreamData{
+							PackageName: "com.example.app",
+						},
+					},
+					{
+						Name:        "properties/222/dataStreams/2002",
+						Type:        "IOS_APP_DATA_STREAM",
+						DisplayName: "iOS",
+						IosAppStreamData: &analyticsadmin.GoogleAnalyticsAdminV1betaDataStreamIosAppStreamData{
+							BundleId: "com.example.ios",
+						},
+					},
+				},
+			}
+		case "/v1beta/properties/333/dataStreams":
+			response = &analyticsadmin.GoogleAnalyticsAdminV1betaListDataStreamsResponse{}
+		default:
+			t.Errorf("予期しないリクエストパス: %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+func TestService_Discover(t *testing.T) {
+	service := newTestService(t, fakeAdminAPI(t))
+
+	accounts, err := service.Discover(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+
+	want := []Account{
+		{
+			ID:          "100",
+			DisplayName: "Example Inc.",
+			Properties: []Property{
+				{
+					ID:          "111",
+					DisplayName: "Example Web",
+					Streams: []DataStream{
+						{ID: "1001", Type: StreamTypeWeb, DisplayName: "Web", DefaultURI: "www.example.com/", MeasurementID: "G-EXAMPLE"},
+					},
+				},
+				{
+					ID:          "222",
+					DisplayName: "Example App",
+					Streams: []DataStream{
+						{ID: "2001", Type: StreamTypeAndroid, DisplayName: "Android", PackageName: "com.example.app"},
+						{ID: "2002", Type: StreamTypeIOS, DisplayName: "iOS", BundleID: "com.example.ios"},
+					},
+				},
+			},
+		},
+		{
+			ID:          "200",
+			DisplayName: "Other Inc.",
+			Properties:  []Property{{ID: "333", DisplayName: "Other Web"}},
+		},
+	}
+	if !reflect.DeepEqual(accounts, want) {
+		t.Errorf("Discover() = %+v, want %+v", accounts, want)
+	}
+}
+
+func TestService_Discover_Account(t *testing.T) {
+	service := newTestService(t, fakeAdminAPI(t))
+
+	accounts, err := service.Discover(context.Background(), "200")
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].ID != "200" {
+		t.Errorf("Discover() = %+v, want account 200 only", accounts)
+	}
+
+	_, err = service.Discover(context.Background(), "999")
+	if err == nil || !strings.Contains(err.Error(), "アカウント 999 が見つかりません") {
+		t.Errorf("Discover() error = %v, want account not found", err)
+	}
+}
+
+func TestService_Discover_APIError(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": {"code": 403, "message": "permission denied"}}`, http.StatusForbidden)
+	})
+
+	_, err := service.Discover(context.Background(), "")
+	if err == nil || !strings.Contains(err.Error(), "アカウントの一覧の取得に失敗しました") {
+		t.Errorf("Discover() error = %v", err)
+	}
+}
+
+func TestConfigProperties(t *testing.T) {
+	account := Account{
+		ID: "100",
+		Properties: []Property{
+			{ID: "111", Streams: []DataStream{{ID: "1001", Type: StreamTypeWeb, DefaultURI: "www.example.com/"}}},
+			{ID: "222", Streams: []DataStream{{ID: "2001", Type: StreamTypeAndroid}}},
+			{ID: "333"},
+		},
+	}
+
+	want := []config.Property{
+		{ID: "111", Streams: []config.Stream{{ID: "1001", BaseURL: "https://www.example.com", Dimensions: defaultWebDimensions, Metrics: defaultMetrics}}},
+		{ID: "222", Streams: []config.Stream{{ID: "2001", Dimensions: defaultAppDimensions, Metrics: defaultMetrics}}},
+	}
+	if got := ConfigProperties(account); !reflect.DeepEqual(got, want) {
+		t.Errorf("ConfigProperties() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBaseURL(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"https://www.example.com/", "https://www.example.com"},
+		{"http://example.com", "http://example.com"},
+		{"example.com/blog/", "https://example.com/blog"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := baseURL(tt.input); got != tt.want {
+			t.Errorf("baseURL(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestService_ResolveProperties(t *testing.T) {
+	service := newTestService(t, fakeAdminAPI(t))
+
+	cfg := &config.Config{
+		Account:       "100",
+		PropertiesAll: true,
+		AllProperties: &config.AllProperties{
+			Exclude:  "App$",
+			Template: config.Stream{Dimensions: []string{"date"}, Metrics: []string{"sessions"}},
+		},
+	}
+	if err := service.ResolveProperties(context.Background(), cfg); err != nil {
+		t.Fatalf("ResolveProperties() error = %v", err)
+	}
+
+	// 除外したプロパティ 222 のデータストリームは取得しない（取得した場合は fakeAdminAPI がエラーにする）
+	want := []config.Property{
+		{ID: "111", Streams: []config.Stream{{ID: "1001", BaseURL: "https://www.example.com", Dimensions: []string{"date"}, Metrics: []string{"sessions"}}}},
+	}
+	if !reflect.DeepEqual(cfg.Properties, want) {
+		t.Errorf("Properties = %+v, want %+v", cfg.Properties, want)
+	}
+}
+
+func TestService_ResolveProperties_PropertyWide(t *testing.T) {
+	service := newTestService(t, fakeAdminAPI(t))
+
+	cfg := &config.Config{
+		Account:       "100",
+		PropertiesAll: true,
+		AllProperties: &config.AllProperties{
+			Include:  "App",
+			Template: config.Stream{Dimensions: []string{"date"}, Metrics: []string{"sessions"}, PropertyWide: true},
+		},
+	}
+	if err := service.ResolveProperties(context.Background(), cfg); err != nil {
+		t.Fatalf("ResolveProperties() error = %v", err)
+	}
+
+	if len(cfg.Properties) != 1 || cfg.Properties[0].ID != "222" || len(cfg.Properties[0].Streams) != 1 {
+		t.Errorf("Properties = %+v, want property 222 with a single stream", cfg.Properties)
+	}
+}
+
+func TestService_ResolveProperties_NoMatch(t *testing.T) {
+	service := newTestService(t, fakeAdminAPI(t))
+
+	// アカウント 200 のプロパティ 333 にはデータストリームがない
+	cfg := &config.Config{
+		Account:       "200",
+		PropertiesAll: true,
+		AllProperties: &config.AllProperties{Template: config.Stream{Dimensions: []string{"date"}, Metrics: []string{"sessions"}}},
+	}
+	err := service.ResolveProperties(context.Background(), cfg)
+	if err == nil || !strings.Contains(err.Error(), "条件に一致するプロパティがありません") {
+		t.Errorf("ResolveProperties() error = %v", err)
+	}
+}
+
+func TestService_ResolveProperties_NotAll(t *testing.T) {
+	service := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("properties: all でない場合にAdmin APIを呼び出しています: %s", r.URL.Path)
+	})
+
+	properties := []config.Property{{ID: "1"}}
+	cfg := &config.Config{Account: "100", Properties: properties}
+	if err := service.ResolveProperties(context.Background(), cfg); err != nil {
+		t.Fatalf("ResolveProperties() error = %v", err)
+	}
+	if !reflect.DeepEqual(cfg.Properties, properties) {
+		t.Errorf("Properties = %+v", cfg.Properties)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"strings"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// Aggregation はAPIが計算したメトリクスの集計値（合計・最小値・最大値）を表す構造体
+// 非加算のメトリクス（activeUsers など）も行の値の合計ではなく、期間全体で正しく集計された値になる
+type Aggregation struct {
+	PropertyID string
+	StreamID   string
+	Type       string            // total, minimum, maximum のいずれか
+	Metrics    map[string]string // メトリクス列名 -> 集計値
+}
+
+// metricAggregations は設定の集計の種類をGA4 APIのMetricAggregationに変換する
+func metricAggregations(aggregations []string) []string {
+	var result []string
+	for _, aggregation := range aggregations {
+		result = append(result, strings.ToUpper(aggregation))
+	}
+	return result
+}
+
+// buildAggregations はレスポンスの集計行をメトリクス列名ごとの値に変換する
+// 集計値は total, minimum, maximum の順に並べる
+func buildAggregations(response *GA4ReportResponse, propertyID, streamID string) []Aggregation {
+	rowsByType := map[string][]*analyticsdata.Row{
+		config.AggregationTotal:   response.Totals,
+		config.AggregationMinimum: response.Minimums,
+		config.AggregationMaximum: response.Maximums,
+	}
+
+	var aggregations []Aggregation
+	for _, aggregationType := range config.AggregationTypes {
+		for _, row := range rowsByType[aggregationType] {
+			metrics := make(map[string]string, len(response.MetricHeaders))
+			for i, header := range response.MetricHeaders {
+				if i < len(row.MetricValues) && row.MetricValues[i] != nil {
+					metrics[header.Name] = row.MetricValues[i].Value
+				}
+			}
+			aggregations = append(aggregations, Aggregation{
+				PropertyID: propertyID,
+				StreamID:   streamID,
+				Type:       aggregationType,
+				Metrics:    metrics,
+			})
+		}
+	}
+	return aggregations
+}
+
+// mergeComparisonAggregation は日付範囲ごとに分かれた集計行を比較期間の列を含む1行にまとめる
+func mergeComparisonAggregation(response *GA4ReportResponse, rows []*analyticsdata.Row) []*analyticsdata.Row {
+	if len(rows) == 0 {
+		return nil
+	}
+	return mergeComparison(&GA4ReportResponse{
+		DimensionHeaders: response.DimensionHeaders,
+		MetricHeaders:    response.MetricHeaders,
+		Rows:             rows,
+	}).Rows
+}
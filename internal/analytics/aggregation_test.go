@@ -0,0 +1,148 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+func TestBuildRunReportRequest_Aggregations(t *testing.T) {
+	request := &GA4ReportRequest{
+		StartDate:    "2024-01-01",
+		EndDate:      "2024-01-31",
+		Metrics:      []string{"activeUsers"},
+		Aggregations: []string{"total", "maximum"},
+	}
+
+	got := buildRunReportRequest(request, 0, 100).MetricAggregations
+	if want := []string{"TOTAL", "MAXIMUM"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MetricAggregations = %v, want %v", got, want)
+	}
+
+	request.Aggregations = nil
+	if got := buildRunReportRequest(request, 0, 100).MetricAggregations; got != nil {
+		t.Errorf("aggregations 未指定の場合 MetricAggregations = %v, want nil", got)
+	}
+}
+
+func TestBuildAggregations(t *testing.T) {
+	aggregationRow := func(values ...string) *analyticsdata.Row {
+		row := &analyticsdata.Row{DimensionValues: []*analyticsdata.DimensionValue{{Value: "RESERVED_TOTAL"}}}
+		for _, v := range values {
+			row.MetricValues = append(row.MetricValues, &analyticsdata.MetricValue{Value: v})
+		}
+		return row
+	}
+	response := &GA4ReportResponse{
+		DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: "pagePath"}},
+		MetricHeaders:    []*analyticsdata.MetricHeader{{Name: "activeUsers"}, {Name: "sessions"}},
+		Maximums:         []*analyticsdata.Row{aggregationRow("70", "90")},
+		Totals:           []*analyticsdata.Row{aggregationRow("100", "150")},
+	}
+
+	got := buildAggregations(response, "111", "1")
+	want := []Aggregation{
+		{PropertyID: "111", StreamID: "1", Type: "total", Metrics: map[string]string{"activeUsers": "100", "sessions": "150"}},
+		{PropertyID: "111", StreamID: "1", Type: "maximum", Metrics: map[string]string{"activeUsers": "70", "sessions": "90"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildAggregations() = %+v, want %+v", got, want)
+	}
+
+	if got := buildAggregations(&GA4ReportResponse{}, "111", "1"); got != nil {
+		t.Errorf("集計行がない場合 buildAggregations() = %+v, want nil", got)
+	}
+}
+
+func TestMergeComparison_Aggregations(t *testing.T) {
+	response := &GA4ReportResponse{
+		DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: "pagePath"}, {Name: "dateRange"}},
+		MetricHeaders:    []*analyticsdata.MetricHeader{{Name: "activeUsers", Type: "TYPE_INTEGER"}},
+		Rows: []*analyticsdata.Row{
+			comparisonRow("/a", "current", "80"),
+			comparisonRow("/a", "comparison", "40"),
+		},
+		Totals: []*analyticsdata.Row{
+			comparisonRow("RESERVED_TOTAL", "current", "120"),
+			comparisonRow("RESERVED_TOTAL", "comparison", "100"),
+		},
+	}
+
+	merged := mergeComparison(response)
+
+	if len(merged.Totals) != 1 {
+		t.Fatalf("Totals = %d 行, want 1", len(merged.Totals))
+	}
+	var got []string
+	for _, v := range merged.Totals[0].MetricValues {
+		got = append(got, v.Value)
+	}
+	if want := []string{"120", "100", "20", "20"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Totals = %v, want %v", got, want)
+	}
+	if merged.Minimums != nil || merged.Maximums != nil {
+		t.Errorf("指定していない集計行は nil である必要があります: %v, %v", merged.Minimums, merged.Maximums)
+	}
+}
+
+func TestFetchDataConcurrently_Aggregations(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var request analyticsdata.RunReportRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		response := singleRowReport("/", "10")
+		for _, aggregation := range request.MetricAggregations {
+			row := []*analyticsdata.Row{{
+				DimensionValues: []*analyticsdata.DimensionValue{{Value: "RESERVED_" + aggregation}},
+				MetricValues:    []*analyticsdata.MetricValue{{Value: "42"}},
+			}}
+			switch aggregation {
+			case "TOTAL":
+				response.Totals = row
+			case "MINIMUM":
+				response.Minimums = row
+			case "MAXIMUM":
+				response.Maximums = row
+			}
+		}
+		writeJSON(w, response)
+	}
+	service := &AnalyticsServiceImpl{client: newTestGA4Client(t, handler)}
+
+	requests := testRequests("111111111", "222222222")
+	requests[1].Aggregations = []string{"total", "minimum"}
+
+	data, err := service.fetchDataConcurrently(context.Background(), requests, createTestConfig())
+	if err != nil {
+		t.Fatalf("fetchDataConcurrently() error = %v", err)
+	}
+
+	want := []Aggregation{
+		{PropertyID: "222222222", StreamID: "1", Type: "total", Metrics: map[string]string{"sessions": "42"}},
+		{PropertyID: "222222222", StreamID: "1", Type: "minimum", Metrics: map[string]string{"sessions": "42"}},
+	}
+	if !reflect.DeepEqual(data.Aggregations, want) {
+		t.Errorf("Aggregations = %+v, want %+v", data.Aggregations, want)
+	}
+}
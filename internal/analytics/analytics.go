@@ -35,6 +35,9 @@ import (
 type AnalyticsService interface {
 	// GetReportData は指定された設定に基づいてレポートデータを取得する
 	GetReportData(ctx context.Context, config *config.Config) (*ReportData, error)
+
+	// GetRealtimeData はrealtime 設定に基づいてリアルタイムレポートのデータを取得する
+	GetRealtimeData(ctx context.Context, config *config.Config) (*ReportData, error)
 }
 
 // GA4Client はGoogle Analytics 4 APIクライアント
@@ -42,6 +45,10 @@ type GA4Client struct {
 	service     *analyticsdata.Service
 	config      *config.Config
 	retryConfig *RetryConfig
+	retryBudget *retryBudget    // 実行全体のリトライ時間の上限（nilの場合は無制限）
+	quota       *quotaTracker   // プロパティごとのクォータ残量（nilの場合は記録しない）
+	limiter     *requestLimiter // 同時実行数とレートの制限（nilの場合は制限しない）
+	cache       *ResponseCache  // レスポンスのキャッシュ（nilの場合は使用しない）
 }
 
 // ReportData はレポートデータを表す構造体
@@ -49,13 +56,76 @@ type ReportData struct {
 	Headers    []string
 	Rows       [][]string
 	Summary    ReportSummary
-	StreamURLs map[string]string // ストリームID -> ベースURL のマッピング
+	StreamURLs map[string]string         // ストリームID -> ベースURL のマッピング
+	Schema     *Schema                   // 列の分類（nilの場合は出力時に列名から推測する）
+	Quotas     map[string]*PropertyQuota // プロパティID -> 取得後のクォータの状態
+	Failures   []RequestFailure          // continue_on_error 指定時に失敗したリクエスト
+	Metadata   []*ResponseMetadata       // プロパティ・ストリームごとのデータの精度に関する情報（設定の順）
+
+	// Aggregations はAPIが計算した合計・最小値・最大値（設定の順）
+	Aggregations []Aggregation
+
+	// Tables は schema_mode: split の場合の列の構成ごとの表（Headers/Rows は全列をまとめた表）
+	Tables []*ReportData
+}
+
+// Schema は出力列がディメンションかメトリクスかを表す構造体
+// APIレスポンスのヘッダーから構築する
+type Schema struct {
+	Dimensions []string // ディメンション列（property_id, stream_id を含む）
+	Metrics    []string // メトリクス列
+
+	// MetricTypes はメトリクス列の型（APIのMetricHeader.Type）。型が不明な列は含まない
+	MetricTypes map[string]MetricType
+
+	// PivotColumns はピボット表で生成されたメトリクス列（通常のレポートでは空）
+	PivotColumns []PivotColumn
+
+	// CohortColumns はコホートレポートの継続率の表で生成されたメトリクス列（通常のレポートでは空）
+	CohortColumns []CohortColumn
+}
+
+// IsDimension は列がディメンションかどうかを判定する
+func (s *Schema) IsDimension(column string) bool {
+	return containsString(s.Dimensions, column)
+}
+
+// IsMetric は列がメトリクスかどうかを判定する
+func (s *Schema) IsMetric(column string) bool {
+	return containsString(s.Metrics, column)
+}
+
+// Has は列がスキーマに含まれているかを判定する
+func (s *Schema) Has(column string) bool {
+	return s.IsDimension(column) || s.IsMetric(column)
+}
+
+// PivotColumn は列がピボット表の生成列の場合にその情報を返す
+func (s *Schema) PivotColumn(column string) (PivotColumn, bool) {
+	for _, pc := range s.PivotColumns {
+		if pc.Name == column {
+			return pc, true
+		}
+	}
+	return PivotColumn{}, false
+}
+
+// CohortColumn は列がコホートレポートの生成列の場合にその情報を返す
+func (s *Schema) CohortColumn(column string) (CohortColumn, bool) {
+	for _, cc := range s.CohortColumns {
+		if cc.Name == column {
+			return cc, true
+		}
+	}
+	return CohortColumn{}, false
 }
 
 // ReportSummary はレポートサマリーを表す構造体
 type ReportSummary struct {
 	TotalRows  int
 	DateRange  string
+	StartDate  string // 解決済みの開始日（YYYY-MM-DD）
+	EndDate    string // 解決済みの終了日（YYYY-MM-DD）
 	Properties []string
 }
 
@@ -67,23 +137,70 @@ type GA4ReportRequest struct {
 	EndDate    string
 	Dimensions []string
 	Metrics    []string
+	PageSize   int64 // 1ページあたりの取得行数（0の場合はDefaultPageSize）
+	MaxRows    int64 // 取得する最大行数（0は無制限）
+
+	// CalculatedMetrics は式で定義するメトリクス（Metricsの後に出力される）
+	CalculatedMetrics []CalculatedMetric
+
+	// PropertyWide がtrueの場合はStreamIDでの絞り込みを行わない
+	PropertyWide bool
+
+	DimensionFilter *analyticsdata.FilterExpression
+	MetricFilter    *analyticsdata.FilterExpression
+
+	OrderBys []*analyticsdata.OrderBy
+	Limit    int64 // 上位N件の件数（0は無制限）
+
+	// 比較期間（指定時は集計期間と合わせて2つの日付範囲で取得し、差分列を追加する）
+	ComparisonStartDate string
+	ComparisonEndDate   string
+
+	ChunkBy string // 期間の分割単位（day, week, month）。空の場合は分割しない
+
+	Pivot *PivotSpec // ピボットレポートとして取得する場合の行と列（nilの場合は通常のレポート）
+
+	Cohort *CohortSpec // コホートレポートとして取得する場合のコホートと期間（nilの場合は通常のレポート）
+
+	Aggregations []string // APIで計算する集計値（total, minimum, maximum）
+}
+
+// CalculatedMetric は別名とメトリクス式で定義する計算指標
+type CalculatedMetric struct {
+	Name       string // 出力列名として使う別名
+	Expression string // メトリクス式（例: screenPageViews/sessions）
 }
 
-// MetricMapping はメトリクス名のマッピングを定義
-var MetricMapping = map[string]string{
-	"sessions":               "sessions",
-	"activeUsers":            "activeUsers",
-	"newUsers":               "newUsers",
-	"averageSessionDuration": "averageSessionDuration",
+// batchable はBatchRunReportsで他のリクエストとまとめて取得できるかを判定する
+// 期間を分割するリクエストとピボットレポートは個別に取得する
+func (r *GA4ReportRequest) batchable() bool {
+	return r.ChunkBy == "" && r.Pivot == nil
+}
+
+// rowLimit はmax_rowsとlimitのうち小さい方を返す（どちらも未指定の場合は0）
+func (r *GA4ReportRequest) rowLimit() int64 {
+	switch {
+	case r.MaxRows > 0 && r.Limit > 0:
+		return min(r.MaxRows, r.Limit)
+	case r.Limit > 0:
+		return r.Limit
+	default:
+		return r.MaxRows
+	}
 }
 
+// DefaultPageSize はページングの既定の1ページあたり取得行数
+const DefaultPageSize int64 = 10000
+
 // RetryConfig はリトライ設定を表す構造体
 type RetryConfig struct {
 	MaxRetries      int
 	BaseDelay       time.Duration
 	MaxDelay        time.Duration
 	BackoffFactor   float64
-	RetryableErrors []int // HTTPステータスコード
+	RetryableErrors []int         // HTTPステータスコード
+	Jitter          float64       // 待機時間をランダムに短くする割合（0〜1）
+	Budget          time.Duration // 1回の実行でリトライの待機に使える合計時間（0は無制限）
 }
 
 // DefaultRetryConfig はデフォルトのリトライ設定
@@ -99,6 +216,7 @@ var DefaultRetryConfig = &RetryConfig{
 		503, // Service Unavailable
 		504, // Gateway Timeout
 	},
+	Jitter: 0.2,
 }
 
 // GA4ReportResponse はGoogle Analytics Data APIレスポンスを表す構造体
@@ -107,6 +225,14 @@ type GA4ReportResponse struct {
 	MetricHeaders    []*analyticsdata.MetricHeader
 	Rows             []*analyticsdata.Row
 	RowCount         int64
+	PivotColumns     []PivotColumn     // ピボットレポートの生成列（MetricHeadersと同じ順）
+	CohortColumns    []CohortColumn    // コホートレポートの生成列（MetricHeadersと同じ順）
+	Metadata         *ResponseMetadata // サンプリング・しきい値などのデータの精度に関する情報
+
+	// APIが計算した集計行（aggregations を指定した場合のみ）
+	Totals   []*analyticsdata.Row
+	Minimums []*analyticsdata.Row
+	Maximums []*analyticsdata.Row
 }
 
 // DimensionHeader はディメンションヘッダーを表す構造体
@@ -159,10 +285,16 @@ func NewGA4Client(ctx context.Context, token *oauth2.Token, config *config.Confi
 		return nil, fmt.Errorf("Analytics Data APIサービスの作成に失敗しました: %w", err)
 	}
 
+	retryConfig := NewRetryConfig(config.Retry)
+
 	return &GA4Client{
 		service:     service,
 		config:      config,
-		retryConfig: DefaultRetryConfig,
+		retryConfig: retryConfig,
+		retryBudget: newRetryBudget(retryConfig.Budget),
+		quota:       newQuotaTracker(config.Quota),
+		limiter:     newRequestLimiter(config.Concurrency),
+		cache:       newResponseCache(config.Cache),
 	}, nil
 }
 
@@ -213,6 +345,11 @@ func (c *GA4Client) calculateBackoffDelay(attempt int) time.Duration {
 
 // classifyError はエラーを分類してGAErrorを作成する
 func (c *GA4Client) classifyError(err error, context string) error {
+	// クォータ残量による中止など、分類済みのエラーはそのまま返す
+	if gaErr, ok := err.(*errors.GAError); ok {
+		return gaErr
+	}
+
 	if apiErr, ok := err.(*googleapi.Error); ok {
 		switch apiErr.Code {
 		case 401:
@@ -260,12 +397,33 @@ func (a *AnalyticsServiceImpl) fetchDataConcurrently(ctx context.Context, reques
 		response   *GA4ReportResponse
 		propertyID string
 		streamID   string // ストリームIDを追加
+		index      int    // requests内の位置（出力の順序を揃えるため）
 		err        error
 	}
 
+	order := make(map[*GA4ReportRequest]int, len(requests))
+	for i, request := range requests {
+		order[request] = i
+	}
+
 	// プログレス表示の初期化
 	fmt.Printf("データ取得を開始します... (%d プロパティ)\n", len(requests))
 
+	// 同じプロパティのリクエストはBatchRunReportsで最大MaxBatchSize件ずつまとめて取得する
+	// chunk_by を指定したリクエスト（分割した期間ごとにまとめる）とピボットレポートは個別に扱う
+	var plain, single []*GA4ReportRequest
+	for _, request := range requests {
+		if request.batchable() {
+			plain = append(plain, request)
+		} else {
+			single = append(single, request)
+		}
+	}
+	batches := groupReportRequests(plain)
+	for _, request := range single {
+		batches = append(batches, []*GA4ReportRequest{request})
+	}
+
 	// 結果チャネル
 	resultChan := make(chan result, len(requests))
 	var wg sync.WaitGroup
@@ -274,29 +432,54 @@ func (a *AnalyticsServiceImpl) fetchDataConcurrently(ctx context.Context, reques
 	completed := 0
 	progressChan := make(chan string, len(requests))
 
-	// 各リクエストを並行実行
-	for i, request := range requests {
+	// バッチをワーカーに割り当てるチャネル
+	type job struct {
+		batch []*GA4ReportRequest
+		index int
+	}
+	jobs := make(chan job, len(batches))
+	for i, batch := range batches {
+		jobs <- job{batch: batch, index: i}
+	}
+	close(jobs)
+
+	// 最大 max_in_flight 個のワーカーでバッチを実行する
+	// プロパティごとの同時実行数とレートはAPI呼び出しごとにGA4Clientで制限する
+	workers := min(config.Concurrency.MaxInFlightOrDefault(), len(batches))
+	for range workers {
 		wg.Add(1)
-		go func(req *GA4ReportRequest, index int) {
+		go func() {
 			defer wg.Done()
-
-			fmt.Printf("[%d/%d] プロパティ %s のデータを取得中...\n", index+1, len(requests), req.PropertyID)
-
-			response, err := a.client.runReport(ctx, req)
-
-			if err != nil {
-				progressChan <- fmt.Sprintf("プロパティ %s: エラー", req.PropertyID)
-			} else {
-				progressChan <- fmt.Sprintf("プロパティ %s: %d レコード取得完了", req.PropertyID, response.RowCount)
+			for j := range jobs {
+				batch := j.batch
+				fmt.Printf("[%d/%d] プロパティ %s のデータを取得中... (%d リクエスト)\n", j.index+1, len(batches), batch[0].PropertyID, len(batch))
+
+				var results []reportResult
+				if !batch[0].batchable() {
+					response, err := a.client.runChunkedReport(ctx, batch[0])
+					results = []reportResult{{response: response, err: err}}
+				} else {
+					results = a.client.runBatch(ctx, batch)
+				}
+
+				for k, req := range batch {
+					response, err := results[k].response, results[k].err
+					if err != nil {
+						progressChan <- fmt.Sprintf("プロパティ %s, ストリーム %s: エラー", req.PropertyID, req.StreamID)
+					} else {
+						progressChan <- fmt.Sprintf("プロパティ %s, ストリーム %s: %d レコード取得完了", req.PropertyID, req.StreamID, len(response.Rows))
+					}
+
+					resultChan <- result{
+						response:   response,
+						propertyID: req.PropertyID,
+						streamID:   req.StreamID, // ストリームIDを追加
+						index:      order[req],
+						err:        err,
+					}
+				}
 			}
-
-			resultChan <- result{
-				response:   response,
-				propertyID: req.PropertyID,
-				streamID:   req.StreamID, // ストリームIDを追加
-				err:        err,
-			}
-		}(request, i)
+		}()
 	}
 
 	// プログレス表示用のgoroutine
@@ -314,24 +497,21 @@ func (a *AnalyticsServiceImpl) fetchDataConcurrently(ctx context.Context, reques
 		close(progressChan)
 	}()
 
-	// 結果を収集
-	var allRows [][]string
-	var headers []string
+	// 結果を収集（ストリームごとに列の構成が異なる場合があるため、行と列の構成を組で保持する）
+	collected := make([]*reportPart, len(requests))
+	collectedMetadata := make([]*ResponseMetadata, len(requests))
 	var properties []string
 	totalRows := 0
 	var errors []error
+	var failures []RequestFailure
 
 	for res := range resultChan {
 		if res.err != nil {
-			errors = append(errors, fmt.Errorf("プロパティ %s のデータ取得に失敗しました: %w", res.propertyID, res.err))
+			errors = append(errors, fmt.Errorf("プロパティ %s, ストリーム %s のデータ取得に失敗しました: %w", res.propertyID, res.streamID, res.err))
+			failures = append(failures, newRequestFailure(res.propertyID, res.streamID, res.err))
 			continue
 		}
 
-		// 初回のみヘッダーを設定
-		if len(headers) == 0 {
-			headers = a.buildHeaders(res.response)
-		}
-
 		// データ行を変換（ストリームIDも含める）
 		rows := a.convertResponseToRows(res.response, res.propertyID, res.streamID)
 
@@ -349,14 +529,52 @@ func (a *AnalyticsServiceImpl) fetchDataConcurrently(ctx context.Context, reques
 			}
 		}
 
-		allRows = append(allRows, rows...)
+		collected[res.index] = &reportPart{
+			schema:       buildSchema(res.response),
+			rows:         rows,
+			aggregations: buildAggregations(res.response, res.propertyID, res.streamID),
+		}
+		if res.response.Metadata != nil {
+			metadata := *res.response.Metadata
+			metadata.PropertyID = res.propertyID
+			metadata.StreamID = res.streamID
+			collectedMetadata[res.index] = &metadata
+		}
 		properties = append(properties, res.propertyID)
-		totalRows += int(res.response.RowCount)
+		totalRows += len(rows)
 	}
 
-	// エラーがある場合は最初のエラーを返す
+	// エラーがある場合は失敗した全リクエストを表示する
+	// continue_on_error 指定時は取得できたデータを返し、それ以外は最初のエラーを失敗の一覧とともに返す
 	if len(errors) > 0 {
-		return nil, errors[0]
+		sortFailures(failures)
+		printFailures(failures)
+		if !config.ContinueOnError || len(errors) == len(requests) {
+			return nil, &FetchError{Err: errors[0], Failures: failures}
+		}
+	}
+
+	// 設定の順に並べ、全ストリームの列をまとめた表にする
+	var parts []reportPart
+	var aggregations []Aggregation
+	for _, part := range collected {
+		if part != nil {
+			parts = append(parts, *part)
+			aggregations = append(aggregations, part.aggregations...)
+		}
+	}
+	var metadata []*ResponseMetadata
+	for _, m := range collectedMetadata {
+		if m != nil {
+			metadata = append(metadata, m)
+		}
+	}
+	headers, allRows, schema := mergeParts(parts, config.NullValue)
+
+	// split の場合は列の構成ごとの表も作成する
+	var tables []*ReportData
+	if config.SplitBySchema() {
+		tables = splitParts(parts)
 	}
 
 	// 完了通知
@@ -365,6 +583,16 @@ func (a *AnalyticsServiceImpl) fetchDataConcurrently(ctx context.Context, reques
 	fmt.Printf("   - 総レコード数: %d\n", totalRows)
 	fmt.Printf("   - 対象プロパティ数: %d\n", len(properties))
 	fmt.Printf("   - 期間: %s - %s\n", config.StartDate, config.EndDate)
+	if len(failures) > 0 {
+		fmt.Printf("   - 失敗したリクエスト数: %d / %d\n", len(failures), len(requests))
+	}
+	if len(tables) > 1 {
+		fmt.Printf("   - 列の構成ごとの表: %d\n", len(tables))
+	}
+
+	quotas := a.client.quota.snapshot()
+	printQuotaSummary(quotas)
+	printMetadataWarnings(metadata)
 
 	if len(properties) > 1 {
 		fmt.Printf("   - プロパティ一覧:\n")
@@ -374,6 +602,13 @@ func (a *AnalyticsServiceImpl) fetchDataConcurrently(ctx context.Context, reques
 	}
 	fmt.Println()
 
+	// strict 指定時はサンプリング・しきい値が適用されたデータを出力しない
+	if config.Strict {
+		if err := checkStrict(metadata); err != nil {
+			return nil, err
+		}
+	}
+
 	// StreamURLsマッピングを構築
 	streamURLs := make(map[string]string)
 	fmt.Printf("[DEBUG] StreamURLsマッピングを構築中...\n")
@@ -393,15 +628,34 @@ func (a *AnalyticsServiceImpl) fetchDataConcurrently(ctx context.Context, reques
 	// 最終的なマッピングを確認
 	fmt.Printf("[DEBUG] 最終StreamURLsマッピング: %v\n", streamURLs)
 
+	// 表ごとのデータにもサマリーとURLマッピングを設定する
+	summary := ReportSummary{
+		TotalRows:  totalRows,
+		DateRange:  fmt.Sprintf("%s - %s", config.StartDate, config.EndDate),
+		StartDate:  config.StartDate,
+		EndDate:    config.EndDate,
+		Properties: properties,
+	}
+	for _, table := range tables {
+		table.StreamURLs = streamURLs
+		table.Quotas = quotas
+		table.Metadata = metadata
+		table.Summary = summary
+		table.Summary.TotalRows = len(table.Rows)
+	}
+
 	return &ReportData{
 		Headers:    headers,
 		Rows:       allRows,
 		StreamURLs: streamURLs,
-		Summary: ReportSummary{
-			TotalRows:  totalRows,
-			DateRange:  fmt.Sprintf("%s - %s", config.StartDate, config.EndDate),
-			Properties: properties,
-		},
+		Schema:     schema,
+		Quotas:     quotas,
+		Failures:   failures,
+		Metadata:   metadata,
+		Tables:     tables,
+		Summary:    summary,
+
+		Aggregations: aggregations,
 	}, nil
 }
 
@@ -417,19 +671,68 @@ func (a *AnalyticsServiceImpl) buildReportRequests(config *config.Config) ([]*GA
 		for _, stream := range property.Streams {
 			fmt.Printf("[DEBUG] ストリーム %s: ベースURL = '%s'\n", stream.ID, stream.BaseURL)
 
-			// メトリクス名を検証・マッピング
-			mappedMetrics, err := a.mapMetrics(stream.Metrics)
-			if err != nil {
-				return nil, fmt.Errorf("プロパティ %s のメトリクスマッピングに失敗しました: %w", property.ID, err)
+			// 差分取得の場合は前回の取得以降のみを取得する
+			startDate := config.StartDate
+			if stream.FetchStartDate != "" {
+				startDate = stream.FetchStartDate
 			}
 
 			request := &GA4ReportRequest{
 				PropertyID: property.ID,
 				StreamID:   stream.ID, // ストリームIDを追加
-				StartDate:  config.StartDate,
+				StartDate:  startDate,
 				EndDate:    config.EndDate,
 				Dimensions: stream.Dimensions,
-				Metrics:    mappedMetrics,
+				Metrics:    stream.Metrics,
+				PageSize:   int64(stream.PageSize),
+				MaxRows:    int64(stream.MaxRows),
+
+				PropertyWide: stream.PropertyWide,
+
+				DimensionFilter: buildFilterExpression(stream.DimensionFilter),
+				MetricFilter:    buildFilterExpression(stream.MetricFilter),
+
+				OrderBys: buildOrderBys(stream),
+				Limit:    int64(stream.Limit),
+
+				ChunkBy: stream.ChunkBy,
+
+				Aggregations: stream.Aggregations,
+			}
+
+			if stream.Cohort != nil {
+				spec, err := newCohortSpec(stream.Cohort, config.StartDate, config.EndDate)
+				if err != nil {
+					return nil, fmt.Errorf("プロパティ %s のコホートの作成に失敗しました: %w", property.ID, err)
+				}
+				request.Cohort = spec
+				request.Dimensions = cohortDimensions(spec, stream.Dimensions)
+			}
+
+			if stream.Pivot != nil {
+				request.Pivot = &PivotSpec{
+					Rows:        stream.Pivot.Rows,
+					Columns:     stream.Pivot.Columns,
+					RowLimit:    int64(stream.Pivot.RowLimitOrDefault()),
+					ColumnLimit: int64(stream.Pivot.ColumnLimitOrDefault()),
+				}
+			}
+
+			for _, name := range stream.CalculatedMetricNames() {
+				request.CalculatedMetrics = append(request.CalculatedMetrics, CalculatedMetric{
+					Name:       name,
+					Expression: stream.CalculatedMetrics[name],
+				})
+			}
+
+			if stream.Compare != nil {
+				start, end, err := config.ComparisonDates(stream.Compare, time.Now())
+				if err != nil {
+					return nil, fmt.Errorf("プロパティ %s の比較期間の解決に失敗しました: %w", property.ID, err)
+				}
+				request.ComparisonStartDate = start
+				request.ComparisonEndDate = end
+				fmt.Printf("プロパティ %s: 比較期間 %s - %s のデータも取得します\n", property.ID, request.ComparisonStartDate, request.ComparisonEndDate)
 			}
 
 			fmt.Printf("[DEBUG] リクエスト作成: プロパティ=%s, ストリーム=%s\n", request.PropertyID, request.StreamID)
@@ -446,41 +749,156 @@ func (a *AnalyticsServiceImpl) buildReportRequests(config *config.Config) ([]*GA
 	return requests, nil
 }
 
-// mapMetrics はメトリクス名をGA4 API用にマッピングする
-func (a *AnalyticsServiceImpl) mapMetrics(metrics []string) ([]string, error) {
-	var mappedMetrics []string
+// buildOrderBys はストリーム設定の並び替え条件をGA4 APIのOrderByに変換する
+func buildOrderBys(stream config.Stream) []*analyticsdata.OrderBy {
+	var orderBys []*analyticsdata.OrderBy
+
+	for _, order := range stream.OrderBy {
+		orderBy := &analyticsdata.OrderBy{Desc: order.Desc}
+
+		isDimension := false
+		for _, dim := range stream.Dimensions {
+			if dim == order.Field {
+				isDimension = true
+				break
+			}
+		}
 
-	for _, metric := range metrics {
-		if mappedName, exists := MetricMapping[metric]; exists {
-			mappedMetrics = append(mappedMetrics, mappedName)
+		if isDimension {
+			orderBy.Dimension = &analyticsdata.DimensionOrderBy{
+				DimensionName: order.Field,
+				OrderType:     config.DimensionOrderTypes[order.Type],
+			}
 		} else {
-			return nil, fmt.Errorf("サポートされていないメトリクスです: %s", metric)
+			orderBy.Metric = &analyticsdata.MetricOrderBy{MetricName: order.Field}
 		}
+
+		orderBys = append(orderBys, orderBy)
 	}
 
-	return mappedMetrics, nil
+	return orderBys
 }
 
-// runReport はGA4 APIを呼び出してレポートを実行する（リトライ機能付き）
+// runReport はGA4 APIを呼び出してレポートを実行する（ページング・リトライ機能付き）
+// RowCountに達するまでlimit/offsetで全ページを取得し、取得行数を検証する
 func (c *GA4Client) runReport(ctx context.Context, request *GA4ReportRequest) (*GA4ReportResponse, error) {
-	var lastErr error
+	return c.completeReport(ctx, request, nil)
+}
 
-	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
-		// リトライの場合は待機
-		if attempt > 0 {
-			delay := c.calculateBackoffDelay(attempt - 1)
-			fmt.Printf("リトライ %d/%d: %v後に再試行します...\n", attempt, c.retryConfig.MaxRetries, delay)
-
-			select {
-			case <-ctx.Done():
-				return nil, ctx.Err()
-			case <-time.After(delay):
+// completeReport は取得済みの先頭ページfirstに続くページを取得し、取得行数の検証と比較期間の統合を行う
+// firstがnilの場合は先頭ページから取得する
+func (c *GA4Client) completeReport(ctx context.Context, request *GA4ReportRequest, first *GA4ReportResponse) (*GA4ReportResponse, error) {
+	maxRows := request.fetchLimit()
+
+	var result *GA4ReportResponse
+	var offset int64
+
+	for {
+		page := first
+		if offset > 0 || page == nil {
+			var err error
+			page, err = c.runReportPage(ctx, request, offset, request.pageLimit(offset))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if result == nil {
+			result = page
+		} else {
+			result.Rows = append(result.Rows, page.Rows...)
+			result.RowCount = page.RowCount
+			result.Metadata = mergePageMetadata(result.Metadata, page.Metadata)
+		}
+		offset += int64(len(page.Rows))
+
+		// 全行取得済み、上限到達、または空ページの場合は終了
+		if len(page.Rows) == 0 || offset >= page.RowCount || (maxRows > 0 && offset >= maxRows) {
+			break
+		}
+
+		fmt.Printf("プロパティ %s: %d / %d 行取得済み、次のページを取得します...\n", request.PropertyID, offset, page.RowCount)
+	}
+
+	// 取得行数をRowCountと照合
+	expected := result.RowCount
+	if maxRows > 0 && maxRows < expected {
+		expected = maxRows
+	}
+	if int64(len(result.Rows)) < expected {
+		return nil, errors.NewAPIError(
+			fmt.Sprintf("プロパティ %s のレポートが不完全です（取得 %d 行 / 全 %d 行）", request.PropertyID, len(result.Rows), expected),
+			nil,
+		)
+	}
+	// limit（上位N件）は意図した打ち切りなので警告しない
+	if request.Limit == 0 && maxRows > 0 && result.RowCount > maxRows {
+		fmt.Printf("⚠️  プロパティ %s: max_rows (%d) により %d 行中 %d 行のみ取得しました\n", request.PropertyID, request.MaxRows, result.RowCount, len(result.Rows))
+	}
+
+	// 比較期間の行を集計期間の行にまとめ、集計期間の値で並べ替えてから limit / max_rows を適用する
+	if request.hasComparison() {
+		result = mergeComparison(result)
+		sortComparisonRows(result, request.OrderBys)
+		if limit := request.rowLimit(); limit > 0 && int64(len(result.Rows)) > limit {
+			if request.Limit == 0 {
+				fmt.Printf("⚠️  プロパティ %s: max_rows (%d) により %d 行中 %d 行のみ出力します\n", request.PropertyID, request.MaxRows, len(result.Rows), limit)
 			}
+			result.Rows = result.Rows[:limit]
 		}
+	}
+
+	// コホートレポートの行を継続率の表にする
+	if request.Cohort != nil {
+		result = flattenCohortResponse(request.Cohort, result)
+	}
+
+	return result, nil
+}
+
+// fetchLimit はAPIから取得する行数の上限を返す（0は無制限）
+// 比較期間を指定した場合は2つの期間の行が混在して返るため全行を取得し、まとめた後にrowLimitを適用する
+func (r *GA4ReportRequest) fetchLimit() int64 {
+	if r.hasComparison() {
+		return 0
+	}
+	return r.rowLimit()
+}
 
-		response, err := c.executeReport(ctx, request)
+// pageLimit はoffsetから取得する1ページの行数を返す（page_sizeとmax_rows/limitを考慮する）
+func (r *GA4ReportRequest) pageLimit(offset int64) int64 {
+	limit := r.PageSize
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if maxRows := r.fetchLimit(); maxRows > 0 && maxRows-offset < limit {
+		limit = maxRows - offset
+	}
+	return limit
+}
+
+// runReportPage は1ページ分のレポートを取得する（リトライ機能付き）
+func (c *GA4Client) runReportPage(ctx context.Context, request *GA4ReportRequest, offset, limit int64) (*GA4ReportResponse, error) {
+	var response *GA4ReportResponse
+	operation := fmt.Sprintf("プロパティ %s のレポート取得", request.PropertyID)
+	err := c.withRetry(ctx, operation, func() error {
+		var err error
+		response, err = c.executeReport(ctx, request, offset, limit)
+		return err
+	})
+	return response, err
+}
+
+// withRetry はリトライ可能なエラーの間、指数バックオフでcallを再試行する
+// サーバーが待機時間を指定した場合（Retry-After、RetryInfo）はその時間だけ待機する
+// 最終的なエラーはoperation（処理内容の説明）を付けて分類して返す
+func (c *GA4Client) withRetry(ctx context.Context, operation string, call func() error) error {
+	var lastErr error
+
+	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
+		err := call()
 		if err == nil {
-			return response, nil
+			return nil
 		}
 
 		lastErr = err
@@ -494,15 +912,40 @@ func (c *GA4Client) runReport(ctx context.Context, request *GA4ReportRequest) (*
 		if attempt == c.retryConfig.MaxRetries {
 			break
 		}
+
+		// サーバーが待機時間の上限を超える時間を指定した場合はリトライしない
+		delay := c.retryDelay(attempt, err)
+		if delay > c.retryConfig.MaxDelay {
+			fmt.Printf("⚠️  サーバーが指定した待機時間（%v）が retry.max_delay（%v）を超えるため、リトライを中止します\n", delay, c.retryConfig.MaxDelay)
+			break
+		}
+
+		// 実行全体のリトライ時間の上限を超える場合はリトライしない
+		if !c.retryBudget.spend(delay) {
+			fmt.Printf("⚠️  リトライ時間の上限（%v）に達したため、リトライを中止します\n", c.retryBudget.limit)
+			break
+		}
+
+		// API制限の場合は他のワーカーも同じ時間だけ待機させる
+		if apiErr, ok := err.(*googleapi.Error); ok && apiErr.Code == 429 {
+			c.limiter.pause(delay)
+		}
+
+		fmt.Printf("リトライ %d/%d: %v後に再試行します...\n", attempt+1, c.retryConfig.MaxRetries, delay)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
 	}
 
 	// エラーを分類して返す
-	context := fmt.Sprintf("プロパティ %s のレポート取得", request.PropertyID)
-	return nil, c.classifyError(lastErr, context)
+	return c.classifyError(lastErr, operation)
 }
 
-// executeReport は実際のAPI呼び出しを実行する
-func (c *GA4Client) executeReport(ctx context.Context, request *GA4ReportRequest) (*GA4ReportResponse, error) {
+// buildRunReportRequest はGA4ReportRequestからAPIリクエストを構築する
+func buildRunReportRequest(request *GA4ReportRequest, offset, limit int64) *analyticsdata.RunReportRequest {
 	// ディメンションを構築
 	var dimensions []*analyticsdata.Dimension
 	for _, dim := range request.Dimensions {
@@ -518,21 +961,67 @@ func (c *GA4Client) executeReport(ctx context.Context, request *GA4ReportRequest
 			Name: metric,
 		})
 	}
+	for _, metric := range request.CalculatedMetrics {
+		metrics = append(metrics, &analyticsdata.Metric{
+			Name:       metric.Name,
+			Expression: metric.Expression,
+		})
+	}
 
 	// 日付範囲を構築
-	dateRanges := []*analyticsdata.DateRange{
-		{
-			StartDate: request.StartDate,
-			EndDate:   request.EndDate,
+	dateRanges := buildDateRanges(request)
+
+	// レポートリクエストを構築
+	return &analyticsdata.RunReportRequest{
+		Dimensions:      dimensions,
+		Metrics:         metrics,
+		DateRanges:      dateRanges,
+		CohortSpec:      buildCohortSpec(request.Cohort),
+		DimensionFilter: combineFilters(streamFilter(request), request.DimensionFilter),
+		MetricFilter:    request.MetricFilter,
+		OrderBys:        request.OrderBys,
+		Offset:          offset,
+		Limit:           limit,
+
+		MetricAggregations: metricAggregations(request.Aggregations),
+
+		ReturnPropertyQuota: true,
+	}
+}
+
+// streamFilter はリクエストをデータストリームに限定するstreamIdディメンションフィルタを返す
+// StreamIDが空、またはPropertyWideが指定されている場合はnilを返す
+func streamFilter(request *GA4ReportRequest) *analyticsdata.FilterExpression {
+	if request.StreamID == "" || request.PropertyWide {
+		return nil
+	}
+
+	return &analyticsdata.FilterExpression{
+		Filter: &analyticsdata.Filter{
+			FieldName: "streamId",
+			StringFilter: &analyticsdata.StringFilter{
+				MatchType: "EXACT",
+				Value:     request.StreamID,
+			},
 		},
 	}
+}
 
-	// レポートリクエストを構築
-	reportRequest := &analyticsdata.RunReportRequest{
-		Dimensions: dimensions,
-		Metrics:    metrics,
-		DateRanges: dateRanges,
+// executeReport は実際のAPI呼び出しを実行する
+func (c *GA4Client) executeReport(ctx context.Context, request *GA4ReportRequest, offset, limit int64) (*GA4ReportResponse, error) {
+	reportRequest := buildRunReportRequest(request, offset, limit)
+
+	// 同じリクエストのレスポンスがキャッシュにある場合はAPIを呼び出さない
+	if cached, ok := c.cachedResponse(request.PropertyID, reportRequest); ok {
+		return cached, nil
+	}
+
+	// クォータ残量と同時実行数・レート制限に応じて待機（または中止）する
+	release, err := c.beginCall(ctx, request.PropertyID)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
 
 	// APIを呼び出し
 	propertyPath := fmt.Sprintf("properties/%s", request.PropertyID)
@@ -540,13 +1029,24 @@ func (c *GA4Client) executeReport(ctx context.Context, request *GA4ReportRequest
 	if err != nil {
 		return nil, err
 	}
+	c.quota.record(request.PropertyID, response.PropertyQuota)
+	c.storeResponse(request.PropertyID, reportRequest, response)
+
+	return newReportResponse(response), nil
+}
 
+// newReportResponse はAPIのレスポンスをGA4ReportResponseに変換する
+func newReportResponse(response *analyticsdata.RunReportResponse) *GA4ReportResponse {
 	return &GA4ReportResponse{
 		DimensionHeaders: response.DimensionHeaders,
 		MetricHeaders:    response.MetricHeaders,
 		Rows:             response.Rows,
 		RowCount:         response.RowCount,
-	}, nil
+		Metadata:         newResponseMetadata(response.Metadata),
+		Totals:           response.Totals,
+		Minimums:         response.Minimums,
+		Maximums:         response.Maximums,
+	}
 }
 
 // buildHeaders はレスポンスからCSVヘッダーを構築する
@@ -572,6 +1072,34 @@ func (a *AnalyticsServiceImpl) buildHeaders(response *GA4ReportResponse) []strin
 	return headers
 }
 
+// buildSchema はレスポンスのヘッダーから列の分類を構築する
+// 列の並びはbuildHeadersと同じ
+func buildSchema(response *GA4ReportResponse) *Schema {
+	schema := &Schema{Dimensions: []string{"property_id", "stream_id"}}
+
+	for _, dimHeader := range response.DimensionHeaders {
+		schema.Dimensions = append(schema.Dimensions, dimHeader.Name)
+	}
+	for _, metricHeader := range response.MetricHeaders {
+		schema.Metrics = append(schema.Metrics, metricHeader.Name)
+	}
+	schema.MetricTypes = metricTypes(response.MetricHeaders)
+	schema.PivotColumns = response.PivotColumns
+	schema.CohortColumns = response.CohortColumns
+
+	return schema
+}
+
+// containsString はスライスに値が含まれているかを判定する
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // convertResponseToRows はAPIレスポンスをCSV行に変換する
 func (a *AnalyticsServiceImpl) convertResponseToRows(response *GA4ReportResponse, propertyID, streamID string) [][]string {
 	var rows [][]string
@@ -638,9 +1166,12 @@ func (a *AnalyticsServiceImpl) GetSessionMetrics(ctx context.Context, propertyID
 		Headers:    headers,
 		Rows:       rows,
 		StreamURLs: make(map[string]string), // 空のマッピング
+		Schema:     buildSchema(response),
 		Summary: ReportSummary{
 			TotalRows:  int(response.RowCount),
 			DateRange:  fmt.Sprintf("%s - %s", startDate, endDate),
+			StartDate:  startDate,
+			EndDate:    endDate,
 			Properties: []string{propertyID},
 		},
 	}, nil
@@ -676,9 +1207,12 @@ func (a *AnalyticsServiceImpl) GetUserMetrics(ctx context.Context, propertyID, s
 		Headers:    headers,
 		Rows:       rows,
 		StreamURLs: make(map[string]string), // 空のマッピング
+		Schema:     buildSchema(response),
 		Summary: ReportSummary{
 			TotalRows:  int(response.RowCount),
 			DateRange:  fmt.Sprintf("%s - %s", startDate, endDate),
+			StartDate:  startDate,
+			EndDate:    endDate,
 			Properties: []string{propertyID},
 		},
 	}, nil
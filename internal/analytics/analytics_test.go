@@ -16,13 +16,18 @@ package analytics
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/ymotongpoo/ga/internal/config"
 	"google.golang.org/api/analyticsdata/v1beta"
 	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
 )
 
 // モックGA4Client
@@ -136,21 +141,6 @@ func TestNewGA4Client(t *testing.T) {
 	}
 }
 
-func TestMetricMapping(t *testing.T) {
-	expectedMappings := map[string]string{
-		"sessions":               "sessions",
-		"activeUsers":            "activeUsers",
-		"newUsers":               "newUsers",
-		"averageSessionDuration": "averageSessionDuration",
-	}
-
-	for input, expected := range expectedMappings {
-		if MetricMapping[input] != expected {
-			t.Errorf("MetricMapping[%s] = %s, want %s", input, MetricMapping[input], expected)
-		}
-	}
-}
-
 func TestDefaultRetryConfig(t *testing.T) {
 	if DefaultRetryConfig.MaxRetries != 3 {
 		t.Errorf("DefaultRetryConfig.MaxRetries = %d, want 3", DefaultRetryConfig.MaxRetries)
@@ -348,53 +338,60 @@ func TestAnalyticsServiceImpl_buildReportRequests(t *testing.T) {
 	}
 }
 
-func TestAnalyticsServiceImpl_mapMetrics(t *testing.T) {
+func TestBuildReportRequests_FetchStartDate(t *testing.T) {
 	service := &AnalyticsServiceImpl{}
+	config := createTestConfig()
+	config.Properties[0].Streams[0].FetchStartDate = "2023-01-29"
 
-	tests := []struct {
-		name    string
-		metrics []string
-		want    []string
-		wantErr bool
-	}{
-		{
-			name:    "Valid metrics",
-			metrics: []string{"sessions", "activeUsers"},
-			want:    []string{"sessions", "activeUsers"},
-			wantErr: false,
-		},
-		{
-			name:    "Invalid metric",
-			metrics: []string{"sessions", "invalidMetric"},
-			want:    nil,
-			wantErr: true,
-		},
-		{
-			name:    "Empty metrics",
-			metrics: []string{},
-			want:    []string{},
-			wantErr: false,
-		},
+	requests, err := service.buildReportRequests(config)
+	if err != nil {
+		t.Fatalf("buildReportRequests() error = %v", err)
+	}
+	if requests[0].StartDate != "2023-01-29" || requests[0].EndDate != "2023-01-31" {
+		t.Errorf("期間 = %s - %s, want 2023-01-29 - 2023-01-31", requests[0].StartDate, requests[0].EndDate)
 	}
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := service.mapMetrics(tt.metrics)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("mapMetrics() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !tt.wantErr {
-				if len(got) != len(tt.want) {
-					t.Errorf("mapMetrics() length = %d, want %d", len(got), len(tt.want))
-				}
-				for i, metric := range got {
-					if metric != tt.want[i] {
-						t.Errorf("mapMetrics()[%d] = %s, want %s", i, metric, tt.want[i])
-					}
-				}
-			}
-		})
+func TestBuildReportRequests_AnyMetrics(t *testing.T) {
+	service := &AnalyticsServiceImpl{}
+	cfg := createTestConfig()
+	cfg.Properties[0].Streams[0].Metrics = []string{"screenPageViews", "engagementRate", "keyEvents", "totalRevenue", "customEvent:reading_time"}
+	cfg.Properties[0].Streams[0].CalculatedMetrics = map[string]string{
+		"pages_per_session":  "screenPageViews/sessions",
+		"engaged_percentage": "engagedSessions/sessions*100",
+	}
+
+	requests, err := service.buildReportRequests(cfg)
+	if err != nil {
+		t.Fatalf("buildReportRequests() error = %v", err)
+	}
+
+	request := requests[0]
+	if len(request.Metrics) != 5 || request.Metrics[4] != "customEvent:reading_time" {
+		t.Errorf("Metrics = %v", request.Metrics)
+	}
+
+	// 計算指標は別名の昇順
+	want := []CalculatedMetric{
+		{Name: "engaged_percentage", Expression: "engagedSessions/sessions*100"},
+		{Name: "pages_per_session", Expression: "screenPageViews/sessions"},
+	}
+	if len(request.CalculatedMetrics) != len(want) {
+		t.Fatalf("CalculatedMetrics = %v, want %v", request.CalculatedMetrics, want)
+	}
+	for i := range want {
+		if request.CalculatedMetrics[i] != want[i] {
+			t.Errorf("CalculatedMetrics[%d] = %v, want %v", i, request.CalculatedMetrics[i], want[i])
+		}
+	}
+
+	runRequest := buildRunReportRequest(request, 0, 100)
+	last := runRequest.Metrics[len(runRequest.Metrics)-1]
+	if last.Name != "pages_per_session" || last.Expression != "screenPageViews/sessions" {
+		t.Errorf("計算指標のMetric = %+v", last)
+	}
+	if runRequest.Metrics[0].Expression != "" {
+		t.Errorf("通常のメトリクスに式が設定されています: %+v", runRequest.Metrics[0])
 	}
 }
 
@@ -555,3 +552,248 @@ func TestRetryConfig_Structure(t *testing.T) {
 		t.Errorf("RetryableErrors length = %d, want 2", len(config.RetryableErrors))
 	}
 }
+
+// newTestGA4Client はテスト用HTTPサーバーに接続するGA4Clientを作成する
+func newTestGA4Client(t *testing.T, handler http.HandlerFunc) *GA4Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	service, err := analyticsdata.NewService(context.Background(),
+		option.WithEndpoint(server.URL+"/"),
+		option.WithHTTPClient(server.Client()),
+	)
+	if err != nil {
+		t.Fatalf("analyticsdata.NewService() error = %v", err)
+	}
+
+	return &GA4Client{
+		service: service,
+		config:  createTestConfig(),
+		retryConfig: &RetryConfig{
+			MaxRetries:      1,
+			BaseDelay:       time.Millisecond,
+			MaxDelay:        time.Millisecond,
+			BackoffFactor:   1.0,
+			RetryableErrors: []int{429, 500},
+		},
+	}
+}
+
+// pagedReportHandler はoffset/limitに応じてtotal行を返すテスト用ハンドラー
+func pagedReportHandler(t *testing.T, total int64, requests *[]*analyticsdata.RunReportRequest) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req analyticsdata.RunReportRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("リクエストのデコードに失敗: %v", err)
+		}
+		*requests = append(*requests, &req)
+
+		resp := &analyticsdata.RunReportResponse{
+			DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: "pagePath"}},
+			MetricHeaders:    []*analyticsdata.MetricHeader{{Name: "sessions", Type: "TYPE_INTEGER"}},
+			RowCount:         total,
+		}
+		for i := req.Offset; i < total && i < req.Offset+req.Limit; i++ {
+			resp.Rows = append(resp.Rows, &analyticsdata.Row{
+				DimensionValues: []*analyticsdata.DimensionValue{{Value: fmt.Sprintf("/page/%d", i)}},
+				MetricValues:    []*analyticsdata.MetricValue{{Value: "1"}},
+			})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func TestGA4Client_runReport_Pagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		pageSize   int64
+		maxRows    int64
+		wantRows   int
+		wantLimits []int64
+	}{
+		{
+			name:       "全ページを取得",
+			pageSize:   10,
+			wantRows:   25,
+			wantLimits: []int64{10, 10, 10},
+		},
+		{
+			name:       "max_rowsで打ち切り",
+			pageSize:   10,
+			maxRows:    15,
+			wantRows:   15,
+			wantLimits: []int64{10, 5},
+		},
+		{
+			name:       "既定のページサイズ",
+			wantRows:   25,
+			wantLimits: []int64{DefaultPageSize},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []*analyticsdata.RunReportRequest
+			client := newTestGA4Client(t, pagedReportHandler(t, 25, &requests))
+
+			response, err := client.runReport(context.Background(), &GA4ReportRequest{
+				PropertyID: "987654321",
+				StartDate:  "2023-01-01",
+				EndDate:    "2023-01-31",
+				Dimensions: []string{"pagePath"},
+				Metrics:    []string{"sessions"},
+				PageSize:   tt.pageSize,
+				MaxRows:    tt.maxRows,
+			})
+			if err != nil {
+				t.Fatalf("runReport() error = %v", err)
+			}
+
+			if len(response.Rows) != tt.wantRows {
+				t.Errorf("rows = %d, want %d", len(response.Rows), tt.wantRows)
+			}
+			if len(requests) != len(tt.wantLimits) {
+				t.Fatalf("API呼び出し回数 = %d, want %d", len(requests), len(tt.wantLimits))
+			}
+			var offset int64
+			for i, req := range requests {
+				if req.Limit != tt.wantLimits[i] {
+					t.Errorf("requests[%d].Limit = %d, want %d", i, req.Limit, tt.wantLimits[i])
+				}
+				if req.Offset != offset {
+					t.Errorf("requests[%d].Offset = %d, want %d", i, req.Offset, offset)
+				}
+				offset += req.Limit
+			}
+		})
+	}
+}
+
+func TestGA4Client_runReport_IncompleteResult(t *testing.T) {
+	// RowCountより少ない行しか返さないサーバー
+	client := newTestGA4Client(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&analyticsdata.RunReportResponse{
+			DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: "pagePath"}},
+			MetricHeaders:    []*analyticsdata.MetricHeader{{Name: "sessions"}},
+			RowCount:         100,
+		})
+	})
+
+	_, err := client.runReport(context.Background(), &GA4ReportRequest{
+		PropertyID: "987654321",
+		StartDate:  "2023-01-01",
+		EndDate:    "2023-01-31",
+		Dimensions: []string{"pagePath"},
+		Metrics:    []string{"sessions"},
+	})
+	if err == nil {
+		t.Error("不完全なレポートでエラーが発生しませんでした")
+	}
+}
+
+func TestBuildRunReportRequest_StreamFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    *GA4ReportRequest
+		wantFilter bool
+	}{
+		{
+			name:       "ストリームで絞り込む",
+			request:    &GA4ReportRequest{PropertyID: "987654321", StreamID: "1234567"},
+			wantFilter: true,
+		},
+		{
+			name:       "property_wide指定時は絞り込まない",
+			request:    &GA4ReportRequest{PropertyID: "987654321", StreamID: "1234567", PropertyWide: true},
+			wantFilter: false,
+		},
+		{
+			name:       "ストリームIDなし",
+			request:    &GA4ReportRequest{PropertyID: "987654321"},
+			wantFilter: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := buildRunReportRequest(tt.request, 0, DefaultPageSize)
+
+			if !tt.wantFilter {
+				if req.DimensionFilter != nil {
+					t.Errorf("DimensionFilter = %+v, want nil", req.DimensionFilter)
+				}
+				return
+			}
+
+			if req.DimensionFilter == nil || req.DimensionFilter.Filter == nil {
+				t.Fatal("DimensionFilter が設定されていません")
+			}
+			filter := req.DimensionFilter.Filter
+			if filter.FieldName != "streamId" {
+				t.Errorf("FieldName = %s, want streamId", filter.FieldName)
+			}
+			if filter.StringFilter == nil || filter.StringFilter.MatchType != "EXACT" || filter.StringFilter.Value != tt.request.StreamID {
+				t.Errorf("StringFilter = %+v, want EXACT %s", filter.StringFilter, tt.request.StreamID)
+			}
+		})
+	}
+}
+
+func TestBuildOrderBys(t *testing.T) {
+	stream := config.Stream{
+		Dimensions: []string{"landingPage", "date"},
+		Metrics:    []string{"sessions"},
+		OrderBy: []config.OrderBy{
+			{Field: "sessions", Desc: true},
+			{Field: "date", Type: "numeric"},
+		},
+	}
+
+	orderBys := buildOrderBys(stream)
+	if len(orderBys) != 2 {
+		t.Fatalf("buildOrderBys() length = %d, want 2", len(orderBys))
+	}
+
+	if orderBys[0].Metric == nil || orderBys[0].Metric.MetricName != "sessions" || !orderBys[0].Desc {
+		t.Errorf("orderBys[0] = %+v, want sessions desc", orderBys[0])
+	}
+	if orderBys[1].Dimension == nil || orderBys[1].Dimension.DimensionName != "date" || orderBys[1].Dimension.OrderType != "NUMERIC" || orderBys[1].Desc {
+		t.Errorf("orderBys[1] = %+v, want date NUMERIC asc", orderBys[1])
+	}
+}
+
+func TestGA4Client_runReport_TopN(t *testing.T) {
+	var requests []*analyticsdata.RunReportRequest
+	client := newTestGA4Client(t, pagedReportHandler(t, 25, &requests))
+
+	orderBys := []*analyticsdata.OrderBy{{Metric: &analyticsdata.MetricOrderBy{MetricName: "sessions"}, Desc: true}}
+	response, err := client.runReport(context.Background(), &GA4ReportRequest{
+		PropertyID: "987654321",
+		StartDate:  "2023-01-01",
+		EndDate:    "2023-01-31",
+		Dimensions: []string{"pagePath"},
+		Metrics:    []string{"sessions"},
+		PageSize:   10,
+		MaxRows:    20,
+		OrderBys:   orderBys,
+		Limit:      5,
+	})
+	if err != nil {
+		t.Fatalf("runReport() error = %v", err)
+	}
+
+	if len(response.Rows) != 5 {
+		t.Errorf("rows = %d, want 5", len(response.Rows))
+	}
+	if len(requests) != 1 {
+		t.Fatalf("API呼び出し回数 = %d, want 1", len(requests))
+	}
+	if requests[0].Limit != 5 {
+		t.Errorf("Limit = %d, want 5", requests[0].Limit)
+	}
+	if len(requests[0].OrderBys) != 1 || !requests[0].OrderBys[0].Desc {
+		t.Errorf("OrderBys = %+v, want sessions desc", requests[0].OrderBys)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// MaxBatchSize はBatchRunReportsの1回の呼び出しにまとめられるリクエストの最大数
+const MaxBatchSize = 5
+
+// reportResult はバッチ内の1リクエスト分の取得結果
+type reportResult struct {
+	response *GA4ReportResponse
+	err      error
+}
+
+// groupReportRequests は同じプロパティのリクエストを最大MaxBatchSize件ずつのバッチにまとめる
+// プロパティの順序とプロパティ内のリクエストの順序は維持する
+func groupReportRequests(requests []*GA4ReportRequest) [][]*GA4ReportRequest {
+	var order []string
+	byProperty := make(map[string][]*GA4ReportRequest)
+	for _, request := range requests {
+		if _, ok := byProperty[request.PropertyID]; !ok {
+			order = append(order, request.PropertyID)
+		}
+		byProperty[request.PropertyID] = append(byProperty[request.PropertyID], request)
+	}
+
+	var batches [][]*GA4ReportRequest
+	for _, propertyID := range order {
+		group := byProperty[propertyID]
+		for start := 0; start < len(group); start += MaxBatchSize {
+			end := min(start+MaxBatchSize, len(group))
+			batches = append(batches, group[start:end])
+		}
+	}
+	return batches
+}
+
+// runReports は同じプロパティの複数のリクエストをBatchRunReportsでまとめて取得する
+// 結果はrequestsと同じ順序で返し、エラーはリクエストごとに保持する
+func (c *GA4Client) runReports(ctx context.Context, requests []*GA4ReportRequest) []reportResult {
+	results := make([]reportResult, 0, len(requests))
+	for start := 0; start < len(requests); start += MaxBatchSize {
+		end := min(start+MaxBatchSize, len(requests))
+		results = append(results, c.runBatch(ctx, requests[start:end])...)
+	}
+	return results
+}
+
+// runBatch は最大MaxBatchSize件のリクエストを1回のBatchRunReportsで取得する（リトライ機能付き）
+// 先頭ページがキャッシュにあるリクエストはバッチに含めず、続きのページのみを取得する
+func (c *GA4Client) runBatch(ctx context.Context, batch []*GA4ReportRequest) []reportResult {
+	results := make([]reportResult, len(batch))
+
+	var pending []*GA4ReportRequest
+	var pendingIndex []int
+	for i, request := range batch {
+		if first, ok := c.cachedResponse(request.PropertyID, buildRunReportRequest(request, 0, request.pageLimit(0))); ok {
+			response, err := c.completeReport(ctx, request, first)
+			results[i] = reportResult{response: response, err: err}
+			continue
+		}
+		pending = append(pending, request)
+		pendingIndex = append(pendingIndex, i)
+	}
+
+	for k, result := range c.fetchBatch(ctx, pending) {
+		results[pendingIndex[k]] = result
+	}
+	return results
+}
+
+// fetchBatch はリクエストをまとめて1回のBatchRunReportsで取得する（リトライ機能付き）
+// 2ページ目以降は個別に取得する。バッチ呼び出し自体が失敗した場合は個別のRunReportに切り替え、
+// どのリクエストが失敗したかを特定できるようにする
+func (c *GA4Client) fetchBatch(ctx context.Context, batch []*GA4ReportRequest) []reportResult {
+	results := make([]reportResult, len(batch))
+	if len(batch) == 0 {
+		return results
+	}
+
+	// 1件のみの場合はバッチにする利点がないため個別に取得する
+	if len(batch) == 1 {
+		response, err := c.runReport(ctx, batch[0])
+		results[0] = reportResult{response: response, err: err}
+		return results
+	}
+
+	propertyID := batch[0].PropertyID
+	batchRequest := &analyticsdata.BatchRunReportsRequest{}
+	for _, request := range batch {
+		batchRequest.Requests = append(batchRequest.Requests, buildRunReportRequest(request, 0, request.pageLimit(0)))
+	}
+
+	var response *analyticsdata.BatchRunReportsResponse
+	operation := fmt.Sprintf("プロパティ %s のバッチレポート取得（%d 件）", propertyID, len(batch))
+	err := c.withRetry(ctx, operation, func() error {
+		release, err := c.beginCall(ctx, propertyID)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		propertyPath := fmt.Sprintf("properties/%s", propertyID)
+		response, err = c.service.Properties.BatchRunReports(propertyPath, batchRequest).Context(ctx).Do()
+		if err == nil {
+			for _, report := range response.Reports {
+				c.quota.record(propertyID, report.PropertyQuota)
+			}
+		}
+		if err == nil && len(response.Reports) != len(batch) {
+			err = fmt.Errorf("バッチレポートの件数が一致しません（リクエスト %d 件 / レスポンス %d 件）", len(batch), len(response.Reports))
+		}
+		return err
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			for i := range results {
+				results[i].err = ctx.Err()
+			}
+			return results
+		}
+		// クォータ残量による中止の場合は個別に取得しても同じ結果になる
+		if isQuotaExhausted(err) {
+			for i := range results {
+				results[i].err = err
+			}
+			return results
+		}
+
+		fmt.Printf("⚠️  プロパティ %s: バッチ取得に失敗したため個別に取得します: %v\n", propertyID, err)
+		for i, request := range batch {
+			response, err := c.runReport(ctx, request)
+			results[i] = reportResult{response: response, err: err}
+		}
+		return results
+	}
+
+	for i, request := range batch {
+		c.storeResponse(propertyID, batchRequest.Requests[i], response.Reports[i])
+		response, err := c.completeReport(ctx, request, newReportResponse(response.Reports[i]))
+		results[i] = reportResult{response: response, err: err}
+	}
+	return results
+}
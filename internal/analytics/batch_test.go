@@ -0,0 +1,189 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// reportHandler はrunReportとbatchRunReportsの両方に応答するテスト用ハンドラー
+// 各リクエストのレスポンスはrespondで作成し、呼び出されたメソッド名をcallsに記録する
+func reportHandler(t *testing.T, calls *[]string, respond func(req *analyticsdata.RunReportRequest) (*analyticsdata.RunReportResponse, int)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		method := r.URL.Path[strings.LastIndex(r.URL.Path, ":")+1:]
+		*calls = append(*calls, method)
+
+		if method == "batchRunReports" {
+			var batch analyticsdata.BatchRunReportsRequest
+			if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+				t.Errorf("リクエストのデコードに失敗: %v", err)
+			}
+			response := &analyticsdata.BatchRunReportsResponse{}
+			for _, req := range batch.Requests {
+				report, status := respond(req)
+				if status != http.StatusOK {
+					// バッチ内の1件でも不正な場合はバッチ全体が失敗する
+					http.Error(w, `{"error":{"code":400,"message":"invalid"}}`, status)
+					return
+				}
+				response.Reports = append(response.Reports, report)
+			}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+
+		var req analyticsdata.RunReportRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("リクエストのデコードに失敗: %v", err)
+		}
+		report, status := respond(&req)
+		if status != http.StatusOK {
+			http.Error(w, `{"error":{"code":400,"message":"invalid"}}`, status)
+			return
+		}
+		json.NewEncoder(w).Encode(report)
+	}
+}
+
+// singleRowReport はsessions=valueの1行のレスポンスを作成する
+func singleRowReport(path, value string) *analyticsdata.RunReportResponse {
+	return &analyticsdata.RunReportResponse{
+		DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: "pagePath"}},
+		MetricHeaders:    []*analyticsdata.MetricHeader{{Name: "sessions", Type: "TYPE_INTEGER"}},
+		Rows: []*analyticsdata.Row{{
+			DimensionValues: []*analyticsdata.DimensionValue{{Value: path}},
+			MetricValues:    []*analyticsdata.MetricValue{{Value: value}},
+		}},
+		RowCount: 1,
+	}
+}
+
+func TestGroupReportRequests(t *testing.T) {
+	var requests []*GA4ReportRequest
+	for i := 0; i < 7; i++ {
+		requests = append(requests, &GA4ReportRequest{PropertyID: "111"})
+		if i < 2 {
+			requests = append(requests, &GA4ReportRequest{PropertyID: "222"})
+		}
+	}
+
+	batches := groupReportRequests(requests)
+
+	var got []string
+	for _, batch := range batches {
+		for _, request := range batch[1:] {
+			if request.PropertyID != batch[0].PropertyID {
+				t.Errorf("異なるプロパティが同じバッチに含まれています: %s, %s", batch[0].PropertyID, request.PropertyID)
+			}
+		}
+		got = append(got, batch[0].PropertyID+":"+strings.Repeat("x", len(batch)))
+	}
+	want := []string{"111:xxxxx", "111:xx", "222:xx"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("バッチ = %v, want %v", got, want)
+	}
+}
+
+func TestGA4Client_runBatch(t *testing.T) {
+	var calls []string
+	client := newTestGA4Client(t, reportHandler(t, &calls, func(req *analyticsdata.RunReportRequest) (*analyticsdata.RunReportResponse, int) {
+		value := req.DimensionFilter.Filter.StringFilter.Value
+		return singleRowReport("/"+value, value), http.StatusOK
+	}))
+
+	batch := []*GA4ReportRequest{
+		{PropertyID: "123456789", StreamID: "1", StartDate: "2024-01-01", EndDate: "2024-01-31", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}},
+		{PropertyID: "123456789", StreamID: "2", StartDate: "2024-01-01", EndDate: "2024-01-31", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}},
+	}
+
+	results := client.runBatch(context.Background(), batch)
+
+	if want := []string{"batchRunReports"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("API呼び出し = %v, want %v", calls, want)
+	}
+	for i, result := range results {
+		if result.err != nil {
+			t.Fatalf("results[%d].err = %v", i, result.err)
+		}
+		if got := result.response.Rows[0].MetricValues[0].Value; got != batch[i].StreamID {
+			t.Errorf("results[%d] の値 = %s, want %s（レスポンスの対応がずれています）", i, got, batch[i].StreamID)
+		}
+	}
+}
+
+func TestGA4Client_runBatch_FallbackToSingleCalls(t *testing.T) {
+	var calls []string
+	client := newTestGA4Client(t, reportHandler(t, &calls, func(req *analyticsdata.RunReportRequest) (*analyticsdata.RunReportResponse, int) {
+		if req.DimensionFilter.Filter.StringFilter.Value == "2" {
+			return nil, http.StatusBadRequest
+		}
+		return singleRowReport("/", "1"), http.StatusOK
+	}))
+
+	batch := []*GA4ReportRequest{
+		{PropertyID: "123456789", StreamID: "1", StartDate: "2024-01-01", EndDate: "2024-01-31", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}},
+		{PropertyID: "123456789", StreamID: "2", StartDate: "2024-01-01", EndDate: "2024-01-31", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}},
+	}
+
+	results := client.runBatch(context.Background(), batch)
+
+	if want := []string{"batchRunReports", "runReport", "runReport"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("API呼び出し = %v, want %v", calls, want)
+	}
+	if results[0].err != nil {
+		t.Errorf("ストリーム1 は成功するはずです: %v", results[0].err)
+	}
+	if results[1].err == nil {
+		t.Error("ストリーム2 のエラーが返されていません")
+	}
+}
+
+func TestGA4Client_runBatch_Pagination(t *testing.T) {
+	var calls []string
+	var offsets []int64
+	client := newTestGA4Client(t, reportHandler(t, &calls, func(req *analyticsdata.RunReportRequest) (*analyticsdata.RunReportResponse, int) {
+		offsets = append(offsets, req.Offset)
+		response := singleRowReport("/", "1")
+		if req.DimensionFilter.Filter.StringFilter.Value == "2" {
+			// ストリーム2は2行あり、1ページ1行で取得する
+			response.RowCount = 2
+		}
+		return response, http.StatusOK
+	}))
+
+	batch := []*GA4ReportRequest{
+		{PropertyID: "123456789", StreamID: "1", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}, PageSize: 1},
+		{PropertyID: "123456789", StreamID: "2", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}, PageSize: 1},
+	}
+
+	results := client.runBatch(context.Background(), batch)
+
+	if want := []string{"batchRunReports", "runReport"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("API呼び出し = %v, want %v", calls, want)
+	}
+	if want := []int64{0, 0, 1}; !reflect.DeepEqual(offsets, want) {
+		t.Errorf("offset = %v, want %v", offsets, want)
+	}
+	if results[1].err != nil || len(results[1].response.Rows) != 2 {
+		t.Errorf("ストリーム2 の結果 = %+v, want 2行", results[1])
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// cacheKeyVersion はキャッシュキーの形式のバージョン
+// キャッシュの内容の形式を変更した場合は値を上げて古いエントリを使わないようにする
+const cacheKeyVersion = 1
+
+// ResponseCache はレポートのレスポンスをリクエストの内容をキーとしてディスクに保存するキャッシュ
+// キーはプロパティIDとAPIに送信するリクエスト（期間、ディメンション、メトリクス、フィルタ、
+// ストリームの絞り込み、並び順、offset/limitなど）から計算するため、条件が1つでも異なれば別のエントリになる
+type ResponseCache struct {
+	dir              string
+	ttl              time.Duration
+	keepClosedRanges bool
+	now              func() time.Time
+}
+
+// CacheStats はキャッシュの使用状況を表す構造体
+type CacheStats struct {
+	Entries int       // エントリ数
+	Bytes   int64     // 合計サイズ
+	Oldest  time.Time // 最も古いエントリの保存日時（エントリがない場合はゼロ値）
+	Newest  time.Time // 最も新しいエントリの保存日時（エントリがない場合はゼロ値）
+}
+
+// cacheEntry はキャッシュファイルの内容
+type cacheEntry struct {
+	PropertyID string                           `json:"property_id"`
+	FetchedAt  time.Time                        `json:"fetched_at"`
+	Closed     bool                             `json:"closed"` // 取得時点で期間のデータが確定していたか
+	Response   *analyticsdata.RunReportResponse `json:"response"`
+}
+
+// NewResponseCache は指定したディレクトリにレスポンスを保存するキャッシュを作成する
+// keepClosedRangesがtrueの場合は、確定済みの過去の期間のエントリをttlに関係なく使用する
+func NewResponseCache(dir string, ttl time.Duration, keepClosedRanges bool) *ResponseCache {
+	return &ResponseCache{dir: dir, ttl: ttl, keepClosedRanges: keepClosedRanges, now: time.Now}
+}
+
+// DefaultResponseCacheDir はレスポンスのキャッシュの既定の保存先を返す
+func DefaultResponseCacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %w", err)
+	}
+	return filepath.Join(cacheDir, "ga", "reports"), nil
+}
+
+// newResponseCache は設定に応じたキャッシュを作成する
+// キャッシュを使用しない場合、または保存先を決められない場合はnilを返す
+func newResponseCache(cfg *config.Cache) *ResponseCache {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	dir, err := DefaultResponseCacheDir()
+	if err != nil {
+		fmt.Printf("⚠️  レスポンスのキャッシュを使用できません: %v\n", err)
+		return nil
+	}
+	return NewResponseCache(dir, cfg.TTLOrDefault(), cfg != nil && cfg.KeepClosedRanges)
+}
+
+// Load はリクエストに対応するキャッシュ済みのレスポンスを返す
+// エントリが存在しない、有効期間を過ぎている、または読み込めない場合はfalseを返す
+func (c *ResponseCache) Load(propertyID string, request *analyticsdata.RunReportRequest) (*analyticsdata.RunReportResponse, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	key, err := responseCacheKey(propertyID, request)
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Response == nil {
+		return nil, false
+	}
+	if !c.fresh(&entry) {
+		return nil, false
+	}
+	return entry.Response, true
+}
+
+// Save はリクエストに対するレスポンスをキャッシュに保存する
+func (c *ResponseCache) Save(propertyID string, request *analyticsdata.RunReportRequest, response *analyticsdata.RunReportResponse) error {
+	if c == nil {
+		return nil
+	}
+
+	key, err := responseCacheKey(propertyID, request)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("キャッシュディレクトリの作成に失敗しました: %w", err)
+	}
+
+	now := c.now()
+	entry := cacheEntry{
+		PropertyID: propertyID,
+		FetchedAt:  now,
+		Closed:     closedRange(request, now),
+		Response:   response,
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("レスポンスのシリアライズに失敗しました: %w", err)
+	}
+
+	// 書き込み途中のファイルを読まないよう一時ファイルから置き換える
+	tmp := c.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("レスポンスのキャッシュの書き込みに失敗しました: %w", err)
+	}
+	if err := os.Rename(tmp, c.path(key)); err != nil {
+		return fmt.Errorf("レスポンスのキャッシュの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// Clear はキャッシュのエントリをすべて削除し、削除したエントリ数を返す
+func (c *ResponseCache) Clear() (int, error) {
+	files, err := c.files()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("キャッシュファイル '%s' の削除に失敗しました: %w", file, err)
+		}
+		if strings.HasSuffix(file, ".json") {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// Stats はキャッシュのエントリ数と合計サイズを返す
+func (c *ResponseCache) Stats() (*CacheStats, error) {
+	files, err := c.files()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &CacheStats{}
+	for _, file := range files {
+		if !strings.HasSuffix(file, ".json") {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		stats.Entries++
+		stats.Bytes += info.Size()
+		if stats.Oldest.IsZero() || info.ModTime().Before(stats.Oldest) {
+			stats.Oldest = info.ModTime()
+		}
+		if info.ModTime().After(stats.Newest) {
+			stats.Newest = info.ModTime()
+		}
+	}
+	return stats, nil
+}
+
+// Dir はキャッシュの保存先を返す
+func (c *ResponseCache) Dir() string {
+	return c.dir
+}
+
+// fresh はエントリを使用できるかを判定する
+func (c *ResponseCache) fresh(entry *cacheEntry) bool {
+	if c.keepClosedRanges && entry.Closed {
+		return true
+	}
+	return c.now().Sub(entry.FetchedAt) < c.ttl
+}
+
+// files はキャッシュディレクトリ内のエントリと書き込み途中の一時ファイルの一覧を返す
+// ディレクトリが存在しない場合は空の一覧を返す
+func (c *ResponseCache) files() ([]string, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("キャッシュディレクトリの読み込みに失敗しました: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".json.tmp")) {
+			continue
+		}
+		files = append(files, filepath.Join(c.dir, name))
+	}
+	return files, nil
+}
+
+// path はキーに対応するキャッシュファイルのパスを返す
+func (c *ResponseCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// responseCacheKey はプロパティIDとAPIリクエストの内容からキャッシュキーを計算する
+func responseCacheKey(propertyID string, request *analyticsdata.RunReportRequest) (string, error) {
+	data, err := json.Marshal(struct {
+		Version    int                             `json:"version"`
+		PropertyID string                          `json:"property_id"`
+		Request    *analyticsdata.RunReportRequest `json:"request"`
+	}{cacheKeyVersion, propertyID, request})
+	if err != nil {
+		return "", fmt.Errorf("キャッシュキーの計算に失敗しました: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// closedRange はリクエストのすべての期間が、now時点で確定済みの過去の期間かを判定する
+// 終了日から config.ClosedRangeDays 日以上経っている期間を確定済みとみなす
+// コホートレポートや日付を解析できない期間は確定済みとしない
+func closedRange(request *analyticsdata.RunReportRequest, now time.Time) bool {
+	if request.CohortSpec != nil || len(request.DateRanges) == 0 {
+		return false
+	}
+
+	cutoff := now.AddDate(0, 0, -config.ClosedRangeDays).Format(config.DateLayout)
+	for _, dateRange := range request.DateRanges {
+		if _, err := time.Parse(config.DateLayout, dateRange.EndDate); err != nil {
+			return false
+		}
+		if dateRange.EndDate > cutoff {
+			return false
+		}
+	}
+	return true
+}
+
+// cachedResponse はキャッシュ済みのレスポンスをGA4ReportResponseに変換して返す
+func (c *GA4Client) cachedResponse(propertyID string, request *analyticsdata.RunReportRequest) (*GA4ReportResponse, bool) {
+	response, ok := c.cache.Load(propertyID, request)
+	if !ok {
+		return nil, false
+	}
+	fmt.Printf("プロパティ %s: キャッシュ済みのレスポンスを使用します\n", propertyID)
+	return newReportResponse(response), true
+}
+
+// storeResponse はAPIのレスポンスをキャッシュに保存する
+// 保存に失敗してもレポートの取得は続けられるため、警告のみ表示する
+func (c *GA4Client) storeResponse(propertyID string, request *analyticsdata.RunReportRequest, response *analyticsdata.RunReportResponse) {
+	if err := c.cache.Save(propertyID, request, response); err != nil {
+		fmt.Printf("⚠️  プロパティ %s: %v\n", propertyID, err)
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// newTestResponseCache は一時ディレクトリに保存し、現在時刻をnowに固定したキャッシュを作成する
+func newTestResponseCache(t *testing.T, ttl time.Duration, keepClosedRanges bool, now *time.Time) *ResponseCache {
+	t.Helper()
+	cache := NewResponseCache(t.TempDir(), ttl, keepClosedRanges)
+	cache.now = func() time.Time { return *now }
+	return cache
+}
+
+// cacheTestRequest はキャッシュのテストに使うAPIリクエストを作成する
+func cacheTestRequest(endDate string) *analyticsdata.RunReportRequest {
+	return buildRunReportRequest(&GA4ReportRequest{
+		PropertyID: "123456789",
+		StreamID:   "1",
+		StartDate:  "2024-01-01",
+		EndDate:    endDate,
+		Dimensions: []string{"pagePath"},
+		Metrics:    []string{"sessions"},
+	}, 0, DefaultPageSize)
+}
+
+func TestResponseCache_SaveLoad(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	cache := newTestResponseCache(t, time.Hour, false, &now)
+
+	request := cacheTestRequest("2024-01-31")
+	if _, ok := cache.Load("123456789", request); ok {
+		t.Fatal("保存前に Load() = true")
+	}
+	if err := cache.Save("123456789", request, singleRowReport("/", "42")); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, ok := cache.Load("123456789", cacheTestRequest("2024-01-31"))
+	if !ok {
+		t.Fatal("同じリクエストで Load() = false")
+	}
+	if value := got.Rows[0].MetricValues[0].Value; value != "42" {
+		t.Errorf("キャッシュの値 = %s, want 42", value)
+	}
+
+	// 条件が1つでも異なるリクエストは別のエントリになる
+	otherStream := cacheTestRequest("2024-01-31")
+	otherStream.DimensionFilter = streamFilter(&GA4ReportRequest{StreamID: "2"})
+	otherMetric := cacheTestRequest("2024-01-31")
+	otherMetric.Metrics = append(otherMetric.Metrics, &analyticsdata.Metric{Name: "activeUsers"})
+	otherPage := cacheTestRequest("2024-01-31")
+	otherPage.Offset = DefaultPageSize
+
+	misses := map[string]struct {
+		propertyID string
+		request    *analyticsdata.RunReportRequest
+	}{
+		"プロパティ":  {"987654321", request},
+		"ストリーム":  {"123456789", otherStream},
+		"期間":     {"123456789", cacheTestRequest("2024-02-29")},
+		"メトリクス":  {"123456789", otherMetric},
+		"offset": {"123456789", otherPage},
+	}
+	for name, miss := range misses {
+		if _, ok := cache.Load(miss.propertyID, miss.request); ok {
+			t.Errorf("%s が異なるリクエストで Load() = true", name)
+		}
+	}
+}
+
+func TestResponseCache_Expiry(t *testing.T) {
+	fetchedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		endDate          string
+		keepClosedRanges bool
+		elapsed          time.Duration
+		want             bool
+	}{
+		{name: "有効期間内", endDate: "2024-02-29", elapsed: 30 * time.Minute, want: true},
+		{name: "有効期間切れ", endDate: "2024-02-29", elapsed: 2 * time.Hour, want: false},
+		{name: "確定済みの期間は期限切れにならない", endDate: "2024-01-31", keepClosedRanges: true, elapsed: 24 * 365 * time.Hour, want: true},
+		{name: "keep_closed_rangesなしでは確定済みでも期限切れ", endDate: "2024-01-31", elapsed: 2 * time.Hour, want: false},
+		{name: "直近の期間は確定済みとしない", endDate: "2024-02-28", keepClosedRanges: true, elapsed: 2 * time.Hour, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := fetchedAt
+			cache := newTestResponseCache(t, time.Hour, tt.keepClosedRanges, &now)
+			request := cacheTestRequest(tt.endDate)
+			if err := cache.Save("123456789", request, singleRowReport("/", "1")); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			now = fetchedAt.Add(tt.elapsed)
+			if _, ok := cache.Load("123456789", request); ok != tt.want {
+				t.Errorf("Load() = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestClosedRange(t *testing.T) {
+	now := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		request *analyticsdata.RunReportRequest
+		want    bool
+	}{
+		{name: "3日以上前に終了", request: cacheTestRequest("2024-03-07"), want: true},
+		{name: "終了から3日未満", request: cacheTestRequest("2024-03-08"), want: false},
+		{
+			name: "比較期間が直近",
+			request: &analyticsdata.RunReportRequest{DateRanges: []*analyticsdata.DateRange{
+				{StartDate: "2024-01-01", EndDate: "2024-01-31"},
+				{StartDate: "2024-03-01", EndDate: "2024-03-09"},
+			}},
+			want: false,
+		},
+		{
+			name:    "相対日付",
+			request: &analyticsdata.RunReportRequest{DateRanges: []*analyticsdata.DateRange{{StartDate: "7daysAgo", EndDate: "yesterday"}}},
+			want:    false,
+		},
+		{
+			name:    "コホートレポート",
+			request: &analyticsdata.RunReportRequest{CohortSpec: &analyticsdata.CohortSpec{}},
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := closedRange(tt.request, now); got != tt.want {
+				t.Errorf("closedRange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseCache_StatsClear(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	cache := newTestResponseCache(t, time.Hour, false, &now)
+
+	// 保存先がまだない場合は空
+	empty := NewResponseCache(filepath.Join(t.TempDir(), "missing"), time.Hour, false)
+	if stats, err := empty.Stats(); err != nil || stats.Entries != 0 {
+		t.Errorf("Stats() = %+v, %v, want 0 entries", stats, err)
+	}
+
+	for _, endDate := range []string{"2024-01-31", "2024-02-29"} {
+		if err := cache.Save("123456789", cacheTestRequest(endDate), singleRowReport("/", "1")); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	// キャッシュ以外のファイルは数えず、削除もしない
+	other := filepath.Join(cache.Dir(), "README")
+	if err := os.WriteFile(other, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := cache.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.Entries != 2 || stats.Bytes == 0 || stats.Oldest.IsZero() || stats.Newest.Before(stats.Oldest) {
+		t.Errorf("Stats() = %+v, want 2 entries", stats)
+	}
+
+	removed, err := cache.Clear()
+	if err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Clear() = %d, want 2", removed)
+	}
+	if _, ok := cache.Load("123456789", cacheTestRequest("2024-01-31")); ok {
+		t.Error("削除後に Load() = true")
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("キャッシュ以外のファイルが削除されました: %v", err)
+	}
+}
+
+func TestResponseCache_Nil(t *testing.T) {
+	var cache *ResponseCache
+	request := cacheTestRequest("2024-01-31")
+	if err := cache.Save("123456789", request, singleRowReport("/", "1")); err != nil {
+		t.Errorf("Save() error = %v", err)
+	}
+	if _, ok := cache.Load("123456789", request); ok {
+		t.Error("nilのキャッシュで Load() = true")
+	}
+}
+
+func TestGA4Client_Cache(t *testing.T) {
+	var calls []string
+	client := newTestGA4Client(t, reportHandler(t, &calls, func(req *analyticsdata.RunReportRequest) (*analyticsdata.RunReportResponse, int) {
+		value := req.DimensionFilter.Filter.StringFilter.Value
+		return singleRowReport("/"+value, value), http.StatusOK
+	}))
+	now := time.Now()
+	client.cache = newTestResponseCache(t, time.Hour, false, &now)
+
+	batch := []*GA4ReportRequest{
+		{PropertyID: "123456789", StreamID: "1", StartDate: "2024-01-01", EndDate: "2024-01-31", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}},
+		{PropertyID: "123456789", StreamID: "2", StartDate: "2024-01-01", EndDate: "2024-01-31", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}},
+	}
+
+	// 1回目はAPIから取得してキャッシュに保存する
+	client.runBatch(context.Background(), batch)
+	if want := []string{"batchRunReports"}; !reflect.DeepEqual(calls, want) {
+		t.Fatalf("1回目のAPI呼び出し = %v, want %v", calls, want)
+	}
+
+	// 2回目は同じリクエストをキャッシュから取得する（バッチでも個別でも）
+	calls = nil
+	results := client.runBatch(context.Background(), batch)
+	if _, err := client.runReport(context.Background(), batch[0]); err != nil {
+		t.Fatalf("runReport() error = %v", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("キャッシュ済みのリクエストでAPIが呼び出されました: %v", calls)
+	}
+	for i, result := range results {
+		if result.err != nil {
+			t.Fatalf("results[%d].err = %v", i, result.err)
+		}
+		if got := result.response.Rows[0].MetricValues[0].Value; got != batch[i].StreamID {
+			t.Errorf("results[%d] の値 = %s, want %s", i, got, batch[i].StreamID)
+		}
+	}
+
+	// 一部のみキャッシュにない場合は、ない分だけを取得する
+	calls = nil
+	batch[1].EndDate = "2024-02-29"
+	client.runBatch(context.Background(), batch)
+	if want := []string{"runReport"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("3回目のAPI呼び出し = %v, want %v", calls, want)
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// dateChunk は分割後の1つの期間を表す構造体
+type dateChunk struct {
+	StartDate string
+	EndDate   string
+}
+
+// splitDateRange は期間をchunkBy（day, week, month）ごとに分割する
+// 週は月曜日始まりとし、最初と最後の期間は集計期間に合わせて切り詰める
+func splitDateRange(startDate, endDate, chunkBy string) ([]dateChunk, error) {
+	start, err := time.Parse(config.DateLayout, startDate)
+	if err != nil {
+		return nil, fmt.Errorf("開始日の形式が不正です: %s", startDate)
+	}
+	end, err := time.Parse(config.DateLayout, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("終了日の形式が不正です: %s", endDate)
+	}
+
+	var chunks []dateChunk
+	for current := start; !current.After(end); {
+		var next time.Time
+		switch chunkBy {
+		case "day":
+			next = current.AddDate(0, 0, 1)
+		case "week":
+			next = current.AddDate(0, 0, 7-(int(current.Weekday())+6)%7)
+		case "month":
+			next = time.Date(current.Year(), current.Month()+1, 1, 0, 0, 0, 0, current.Location())
+		default:
+			return nil, fmt.Errorf("期間の分割単位が不正です: %s", chunkBy)
+		}
+
+		chunkEnd := next.AddDate(0, 0, -1)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+		chunks = append(chunks, dateChunk{
+			StartDate: current.Format(config.DateLayout),
+			EndDate:   chunkEnd.Format(config.DateLayout),
+		})
+		current = next
+	}
+
+	return chunks, nil
+}
+
+// runChunkedReport はchunk_byが指定されている場合に期間を分割してrunReportを実行し、結果を1つにまとめる
+// 各期間はBatchRunReportsでまとめて取得し、2ページ目以降やバッチの失敗時はrunReportのページング・リトライ処理を通す
+// ピボットレポートの場合はrunPivotReportで取得する
+func (c *GA4Client) runChunkedReport(ctx context.Context, request *GA4ReportRequest) (*GA4ReportResponse, error) {
+	if request.Pivot != nil {
+		return c.runPivotReport(ctx, request)
+	}
+	if request.ChunkBy == "" {
+		return c.runReport(ctx, request)
+	}
+
+	chunks, err := splitDateRange(request.StartDate, request.EndDate, request.ChunkBy)
+	if err != nil {
+		return nil, err
+	}
+
+	chunkRequests := make([]*GA4ReportRequest, 0, len(chunks))
+	for i, chunk := range chunks {
+		fmt.Printf("プロパティ %s: 期間 %s - %s を取得中 (%d/%d)\n", request.PropertyID, chunk.StartDate, chunk.EndDate, i+1, len(chunks))
+
+		chunkRequest := *request
+		chunkRequest.StartDate = chunk.StartDate
+		chunkRequest.EndDate = chunk.EndDate
+		chunkRequest.ChunkBy = ""
+		chunkRequests = append(chunkRequests, &chunkRequest)
+	}
+
+	responses := make([]*GA4ReportResponse, 0, len(chunks))
+	for _, result := range c.runReports(ctx, chunkRequests) {
+		if result.err != nil {
+			return nil, result.err
+		}
+		responses = append(responses, result.response)
+	}
+
+	merged, warnings := mergeChunks(responses)
+	for _, warning := range warnings {
+		fmt.Printf("⚠️  プロパティ %s, ストリーム %s: %s\n", request.PropertyID, request.StreamID, warning)
+	}
+
+	// max_rows は各期間の取得にも適用されるため、まとめた行数が上限を超えないようにする
+	if limit := request.rowLimit(); limit > 0 && int64(len(merged.Rows)) > limit {
+		fmt.Printf("⚠️  プロパティ %s: max_rows (%d) により %d 行中 %d 行のみ出力します\n", request.PropertyID, request.MaxRows, len(merged.Rows), limit)
+		merged.Rows = merged.Rows[:limit]
+		merged.RowCount = limit
+	}
+
+	return merged, nil
+}
+
+// mergeChunks は期間ごとのレスポンスを1つにまとめる
+// 複数の期間に同じディメンション値の行がある場合は、合算できるメトリクスは合計し、
+// 加重平均で再計算できるメトリクスは再計算する。それ以外のメトリクスは正しい値を
+// 求められないため空欄にし、警告メッセージを返す（設定の検証で chunk_by と同時に指定できないようにしている）
+func mergeChunks(responses []*GA4ReportResponse) (*GA4ReportResponse, []string) {
+	if len(responses) == 0 {
+		return &GA4ReportResponse{}, nil
+	}
+
+	metricHeaders := responses[0].MetricHeaders
+
+	type chunkRow struct {
+		dimensions []*analyticsdata.DimensionValue
+		parts      [][]*analyticsdata.MetricValue
+	}
+
+	var order []string
+	groups := make(map[string]*chunkRow)
+
+	for _, response := range responses {
+		for _, row := range response.Rows {
+			key := dimensionKey(row.DimensionValues)
+			group, exists := groups[key]
+			if !exists {
+				group = &chunkRow{dimensions: row.DimensionValues}
+				groups[key] = group
+				order = append(order, key)
+			}
+			group.parts = append(group.parts, row.MetricValues)
+		}
+	}
+
+	metricIndex := make(map[string]int, len(metricHeaders))
+	for i, header := range metricHeaders {
+		metricIndex[header.Name] = i
+	}
+
+	recomputed := make(map[string]bool)
+	dropped := make(map[string]bool)
+
+	rows := make([]*analyticsdata.Row, 0, len(order))
+	for _, key := range order {
+		group := groups[key]
+
+		// 1つの期間にしか現れない行はそのまま使う
+		if len(group.parts) == 1 {
+			rows = append(rows, &analyticsdata.Row{DimensionValues: group.dimensions, MetricValues: group.parts[0]})
+			continue
+		}
+
+		metricValues := make([]*analyticsdata.MetricValue, len(metricHeaders))
+		for i, header := range metricHeaders {
+			value := ""
+			weightIndex, hasWeight := metricIndex[config.WeightedMetrics[header.Name]]
+
+			switch {
+			case config.IsAdditiveMetric(header.Name):
+				value = sumChunkValues(group.parts, i)
+			case config.WeightedMetrics[header.Name] != "" && hasWeight:
+				value = weightedAverage(group.parts, i, weightIndex)
+				recomputed[header.Name] = true
+			default:
+				dropped[header.Name] = true
+			}
+
+			metricValues[i] = &analyticsdata.MetricValue{Value: value}
+		}
+
+		rows = append(rows, &analyticsdata.Row{DimensionValues: group.dimensions, MetricValues: metricValues})
+	}
+
+	var warnings []string
+	if len(recomputed) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%s は期間ごとの値から加重平均で再計算しました", strings.Join(sortedKeys(recomputed), ", ")))
+	}
+	if len(dropped) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%s は期間をまたいで合算できないため、複数の期間にまたがる行を空欄にしました（dimensions に date を含めるか、chunk_by を外してください）", strings.Join(sortedKeys(dropped), ", ")))
+	}
+
+	var metadata *ResponseMetadata
+	for _, response := range responses {
+		metadata = mergeResponseMetadata(metadata, response.Metadata)
+	}
+
+	return &GA4ReportResponse{
+		DimensionHeaders: responses[0].DimensionHeaders,
+		MetricHeaders:    metricHeaders,
+		Rows:             rows,
+		RowCount:         int64(len(rows)),
+		Metadata:         metadata,
+	}, warnings
+}
+
+// sumChunkValues は期間ごとのメトリクス値を合計する（数値でない値がある場合は空文字を返す）
+func sumChunkValues(parts [][]*analyticsdata.MetricValue, index int) string {
+	var sum float64
+	for _, values := range parts {
+		v, err := strconv.ParseFloat(values[index].Value, 64)
+		if err != nil {
+			return ""
+		}
+		sum += v
+	}
+	return formatNumber(roundTo(sum, 6))
+}
+
+// weightedAverage は期間ごとのメトリクス値を重みのメトリクスで加重平均する
+func weightedAverage(parts [][]*analyticsdata.MetricValue, index, weightIndex int) string {
+	var total, weights float64
+	for _, values := range parts {
+		v, err := strconv.ParseFloat(values[index].Value, 64)
+		if err != nil {
+			return ""
+		}
+		w, err := strconv.ParseFloat(values[weightIndex].Value, 64)
+		if err != nil {
+			return ""
+		}
+		total += v * w
+		weights += w
+	}
+	if weights == 0 {
+		return "0"
+	}
+	return formatNumber(roundTo(total/weights, 6))
+}
+
+// sortedKeys はマップのキーをソートして返す
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
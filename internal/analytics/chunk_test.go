@@ -0,0 +1,190 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+func TestSplitDateRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		start   string
+		end     string
+		chunkBy string
+		want    []dateChunk
+	}{
+		{
+			name: "日ごと", start: "2024-02-28", end: "2024-03-01", chunkBy: "day",
+			want: []dateChunk{{"2024-02-28", "2024-02-28"}, {"2024-02-29", "2024-02-29"}, {"2024-03-01", "2024-03-01"}},
+		},
+		{
+			// 2024-05-01 は水曜日
+			name: "週ごと（月曜日始まり）", start: "2024-05-01", end: "2024-05-15", chunkBy: "week",
+			want: []dateChunk{{"2024-05-01", "2024-05-05"}, {"2024-05-06", "2024-05-12"}, {"2024-05-13", "2024-05-15"}},
+		},
+		{
+			name: "月ごと", start: "2024-01-15", end: "2024-03-10", chunkBy: "month",
+			want: []dateChunk{{"2024-01-15", "2024-01-31"}, {"2024-02-01", "2024-02-29"}, {"2024-03-01", "2024-03-10"}},
+		},
+		{
+			name: "1日のみ", start: "2024-01-01", end: "2024-01-01", chunkBy: "month",
+			want: []dateChunk{{"2024-01-01", "2024-01-01"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitDateRange(tt.start, tt.end, tt.chunkBy)
+			if err != nil {
+				t.Fatalf("splitDateRange() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitDateRange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := splitDateRange("2024-01-01", "2024-01-31", "year"); err == nil {
+		t.Error("不正な分割単位でエラーが発生しませんでした")
+	}
+}
+
+// chunkResponse はpagePath × (sessions, activeUsers, averageSessionDuration) のレスポンスを作成する
+func chunkResponse(rows ...[]string) *GA4ReportResponse {
+	response := &GA4ReportResponse{
+		DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: "pagePath"}},
+		MetricHeaders: []*analyticsdata.MetricHeader{
+			{Name: "sessions"}, {Name: "activeUsers"}, {Name: "averageSessionDuration"},
+		},
+	}
+	for _, r := range rows {
+		row := &analyticsdata.Row{DimensionValues: []*analyticsdata.DimensionValue{{Value: r[0]}}}
+		for _, v := range r[1:] {
+			row.MetricValues = append(row.MetricValues, &analyticsdata.MetricValue{Value: v})
+		}
+		response.Rows = append(response.Rows, row)
+	}
+	return response
+}
+
+func TestMergeChunks(t *testing.T) {
+	merged, warnings := mergeChunks([]*GA4ReportResponse{
+		chunkResponse([]string{"/a", "10", "8", "30"}, []string{"/b", "5", "5", "12"}),
+		chunkResponse([]string{"/a", "30", "20", "10"}),
+		chunkResponse([]string{"/c", "1", "1", "3"}),
+	})
+
+	want := [][]string{
+		{"/a", "40", "", "15"}, // sessionsは合計、averageSessionDurationはsessionsで加重平均
+		{"/b", "5", "5", "12"}, // 1つの期間にしかない行はそのまま
+		{"/c", "1", "1", "3"},
+	}
+
+	if merged.RowCount != int64(len(want)) {
+		t.Errorf("RowCount = %d, want %d", merged.RowCount, len(want))
+	}
+	for i, row := range merged.Rows {
+		got := []string{row.DimensionValues[0].Value}
+		for _, v := range row.MetricValues {
+			got = append(got, v.Value)
+		}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("行 %d = %v, want %v", i, got, want[i])
+		}
+	}
+
+	if len(warnings) != 2 {
+		t.Fatalf("警告の件数 = %d, want 2: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0], "averageSessionDuration") || !strings.Contains(warnings[1], "activeUsers") {
+		t.Errorf("警告 = %v", warnings)
+	}
+}
+
+func TestMergeChunks_NoOverlap(t *testing.T) {
+	_, warnings := mergeChunks([]*GA4ReportResponse{
+		chunkResponse([]string{"2024-01-01", "10", "8", "30"}),
+		chunkResponse([]string{"2024-01-02", "30", "20", "10"}),
+	})
+	if len(warnings) != 0 {
+		t.Errorf("期間が重ならない場合に警告が出ました: %v", warnings)
+	}
+}
+
+func TestGA4Client_runChunkedReport(t *testing.T) {
+	var calls []string
+	var dateRanges []string
+	client := newTestGA4Client(t, reportHandler(t, &calls, func(req *analyticsdata.RunReportRequest) (*analyticsdata.RunReportResponse, int) {
+		dateRanges = append(dateRanges, req.DateRanges[0].StartDate+"/"+req.DateRanges[0].EndDate)
+		return singleRowReport("/", "7"), http.StatusOK
+	}))
+
+	response, err := client.runChunkedReport(context.Background(), &GA4ReportRequest{
+		PropertyID: "123456789",
+		StartDate:  "2024-01-01",
+		EndDate:    "2024-03-31",
+		Dimensions: []string{"pagePath"},
+		Metrics:    []string{"sessions"},
+		ChunkBy:    "month",
+	})
+	if err != nil {
+		t.Fatalf("runChunkedReport() error = %v", err)
+	}
+
+	wantRanges := []string{"2024-01-01/2024-01-31", "2024-02-01/2024-02-29", "2024-03-01/2024-03-31"}
+	if !reflect.DeepEqual(dateRanges, wantRanges) {
+		t.Errorf("リクエストされた期間 = %v, want %v", dateRanges, wantRanges)
+	}
+	// 3か月分は1回のバッチ呼び出しで取得する
+	if want := []string{"batchRunReports"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("API呼び出し = %v, want %v", calls, want)
+	}
+	if len(response.Rows) != 1 || response.Rows[0].MetricValues[0].Value != "21" {
+		t.Errorf("まとめた結果 = %+v, want sessions=21 の1行", response.Rows)
+	}
+}
+
+func TestGA4Client_runChunkedReport_MaxRows(t *testing.T) {
+	var calls []string
+	client := newTestGA4Client(t, reportHandler(t, &calls, func(req *analyticsdata.RunReportRequest) (*analyticsdata.RunReportResponse, int) {
+		// 期間ごとに異なる行を返す
+		return singleRowReport("/"+req.DateRanges[0].StartDate, "7"), http.StatusOK
+	}))
+
+	response, err := client.runChunkedReport(context.Background(), &GA4ReportRequest{
+		PropertyID: "123456789",
+		StartDate:  "2024-01-01",
+		EndDate:    "2024-03-31",
+		Dimensions: []string{"pagePath"},
+		Metrics:    []string{"sessions"},
+		ChunkBy:    "month",
+		MaxRows:    2,
+	})
+	if err != nil {
+		t.Fatalf("runChunkedReport() error = %v", err)
+	}
+
+	// 期間ごとの上限（2行 × 3か月）ではなく、まとめた結果に max_rows を適用する
+	if len(response.Rows) != 2 || response.RowCount != 2 {
+		t.Errorf("まとめた行数 = %d (RowCount %d), want 2", len(response.Rows), response.RowCount)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// CohortSpec はコホートレポートのコホートと集計する期間の設定
+type CohortSpec struct {
+	Cohorts         []config.CohortRange // 名前を解決済みのコホート
+	Granularity     string               // GA4 APIの期間の単位（DAILY, WEEKLY, MONTHLY）
+	PeriodDimension string               // 期間のディメンション（cohortNthDay, cohortNthWeek, cohortNthMonth）
+	PeriodUnit      string               // 出力列名に使う期間の単位（day, week, month）
+	Periods         int                  // 獲得期間を0として集計する期間の数
+	Accumulate      bool                 // 各期間までの累計値を取得する
+}
+
+// CohortColumn はコホートレポートの継続率の表で生成された1列（期間 × メトリクス）を表す構造体
+type CohortColumn struct {
+	Name   string // 出力列名（例: cohortActiveUsers (week 1)）
+	Period int    // 獲得期間を0とする期間の番号
+	Metric string // メトリクス名
+}
+
+// cohortColumnName はメトリクスと期間から出力列名を生成する
+func cohortColumnName(metric, unit string, period int) string {
+	return fmt.Sprintf("%s (%s %d)", metric, unit, period)
+}
+
+// newCohortSpec は設定からコホートレポートの設定を作成する
+// コホートが指定されていない場合は集計期間をgranularityの単位で分割し、各期間の開始日をコホート名とする
+func newCohortSpec(cohort *config.Cohort, startDate, endDate string) (*CohortSpec, error) {
+	spec := &CohortSpec{
+		Granularity:     cohort.APIGranularity(),
+		PeriodDimension: cohort.PeriodDimension(),
+		PeriodUnit:      cohort.PeriodUnit(),
+		Periods:         cohort.Periods,
+		Accumulate:      cohort.Accumulate,
+	}
+
+	if len(cohort.Cohorts) == 0 {
+		chunks, err := splitDateRange(startDate, endDate, cohort.PeriodUnit())
+		if err != nil {
+			return nil, err
+		}
+		for _, chunk := range chunks {
+			spec.Cohorts = append(spec.Cohorts, config.CohortRange{Name: chunk.StartDate, StartDate: chunk.StartDate, EndDate: chunk.EndDate})
+		}
+		return spec, nil
+	}
+
+	for _, r := range cohort.Cohorts {
+		if r.Name == "" {
+			r.Name = r.StartDate
+		}
+		spec.Cohorts = append(spec.Cohorts, r)
+	}
+	return spec, nil
+}
+
+// cohortDimensions はコホート名と期間のディメンションを先頭に置いたディメンションの一覧を返す
+func cohortDimensions(spec *CohortSpec, dimensions []string) []string {
+	result := []string{config.CohortDimension, spec.PeriodDimension}
+	for _, dim := range dimensions {
+		if !containsString(result, dim) {
+			result = append(result, dim)
+		}
+	}
+	return result
+}
+
+// buildCohortSpec はコホートレポートの設定をGA4 APIのCohortSpecに変換する
+// コホートはfirstSessionDate（初回訪問日）で定義する
+func buildCohortSpec(spec *CohortSpec) *analyticsdata.CohortSpec {
+	if spec == nil {
+		return nil
+	}
+
+	var cohorts []*analyticsdata.Cohort
+	for _, r := range spec.Cohorts {
+		cohorts = append(cohorts, &analyticsdata.Cohort{
+			Name:      r.Name,
+			Dimension: "firstSessionDate",
+			DateRange: &analyticsdata.DateRange{StartDate: r.StartDate, EndDate: r.EndDate},
+		})
+	}
+
+	return &analyticsdata.CohortSpec{
+		Cohorts: cohorts,
+		CohortsRange: &analyticsdata.CohortsRange{
+			Granularity:     spec.Granularity,
+			StartOffset:     0,
+			EndOffset:       int64(spec.Periods - 1),
+			ForceSendFields: []string{"StartOffset", "EndOffset"},
+		},
+		CohortReportSettings: &analyticsdata.CohortReportSettings{Accumulate: spec.Accumulate},
+	}
+}
+
+// flattenCohortResponse はコホートレポートのレスポンスを、コホート（と他のディメンション）ごとの行と
+// 「期間 × メトリクス」ごとの生成列からなる継続率の表に変換する
+// 行はコホートの指定順に並べ、値のない期間（まだ経過していない期間など）は空にする
+func flattenCohortResponse(spec *CohortSpec, response *GA4ReportResponse) *GA4ReportResponse {
+	periodIndex := -1
+	result := &GA4ReportResponse{Metadata: response.Metadata}
+	for i, header := range response.DimensionHeaders {
+		if header.Name == spec.PeriodDimension && periodIndex < 0 {
+			periodIndex = i
+			continue
+		}
+		result.DimensionHeaders = append(result.DimensionHeaders, header)
+	}
+
+	// 列は期間の順に、期間ごとに全メトリクスを並べる
+	for period := 0; period < spec.Periods; period++ {
+		for _, metric := range response.MetricHeaders {
+			name := cohortColumnName(metric.Name, spec.PeriodUnit, period)
+			result.MetricHeaders = append(result.MetricHeaders, &analyticsdata.MetricHeader{Name: name, Type: metric.Type})
+			result.CohortColumns = append(result.CohortColumns, CohortColumn{Name: name, Period: period, Metric: metric.Name})
+		}
+	}
+
+	metricCount := len(response.MetricHeaders)
+	rowIndex := make(map[string]int)
+	for _, row := range response.Rows {
+		period := -1
+		var dimensions []*analyticsdata.DimensionValue
+		for i, value := range row.DimensionValues {
+			if i == periodIndex {
+				// 期間はゼロ埋めの文字列（例: 0001）で返される
+				if n, err := strconv.Atoi(value.Value); err == nil {
+					period = n
+				}
+				continue
+			}
+			dimensions = append(dimensions, value)
+		}
+		if period < 0 || period >= spec.Periods {
+			continue
+		}
+
+		key := dimensionKey(dimensions)
+		idx, ok := rowIndex[key]
+		if !ok {
+			merged := &analyticsdata.Row{DimensionValues: dimensions}
+			for range result.MetricHeaders {
+				merged.MetricValues = append(merged.MetricValues, &analyticsdata.MetricValue{})
+			}
+			idx = len(result.Rows)
+			rowIndex[key] = idx
+			result.Rows = append(result.Rows, merged)
+		}
+
+		for m := 0; m < metricCount && m < len(row.MetricValues); m++ {
+			result.Rows[idx].MetricValues[period*metricCount+m].Value = row.MetricValues[m].Value
+		}
+	}
+
+	// コホートの指定順に並べる（同じコホート内はAPIレスポンスの順を保つ）
+	cohortIndex := -1
+	for i, header := range result.DimensionHeaders {
+		if header.Name == config.CohortDimension {
+			cohortIndex = i
+			break
+		}
+	}
+	if cohortIndex >= 0 {
+		order := make(map[string]int, len(spec.Cohorts))
+		for i, r := range spec.Cohorts {
+			order[r.Name] = i
+		}
+		sort.SliceStable(result.Rows, func(i, j int) bool {
+			return order[result.Rows[i].DimensionValues[cohortIndex].Value] < order[result.Rows[j].DimensionValues[cohortIndex].Value]
+		})
+	}
+
+	result.RowCount = int64(len(result.Rows))
+	return result
+}
@@ -0,0 +1,228 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+func TestNewCohortSpec(t *testing.T) {
+	// コホート未指定の場合は集計期間を週（月曜日始まり）ごとに分割する
+	spec, err := newCohortSpec(&config.Cohort{Granularity: "weekly", Periods: 4}, "2024-01-03", "2024-01-16")
+	if err != nil {
+		t.Fatalf("newCohortSpec() error = %v", err)
+	}
+	want := []config.CohortRange{
+		{Name: "2024-01-03", StartDate: "2024-01-03", EndDate: "2024-01-07"},
+		{Name: "2024-01-08", StartDate: "2024-01-08", EndDate: "2024-01-14"},
+		{Name: "2024-01-15", StartDate: "2024-01-15", EndDate: "2024-01-16"},
+	}
+	if !reflect.DeepEqual(spec.Cohorts, want) {
+		t.Errorf("Cohorts = %+v, want %+v", spec.Cohorts, want)
+	}
+	if spec.Granularity != "WEEKLY" || spec.PeriodDimension != "cohortNthWeek" || spec.PeriodUnit != "week" || spec.Periods != 4 {
+		t.Errorf("newCohortSpec() = %+v", spec)
+	}
+
+	// 名前のないコホートは開始日を名前にする
+	spec, err = newCohortSpec(&config.Cohort{
+		Granularity: "monthly",
+		Periods:     3,
+		Cohorts: []config.CohortRange{
+			{Name: "campaign", StartDate: "2024-01-01", EndDate: "2024-01-31"},
+			{StartDate: "2024-02-01", EndDate: "2024-02-29"},
+		},
+	}, "2024-01-01", "2024-03-31")
+	if err != nil {
+		t.Fatalf("newCohortSpec() error = %v", err)
+	}
+	if spec.Cohorts[0].Name != "campaign" || spec.Cohorts[1].Name != "2024-02-01" {
+		t.Errorf("Cohorts = %+v", spec.Cohorts)
+	}
+}
+
+func TestCohortDimensions(t *testing.T) {
+	spec := &CohortSpec{PeriodDimension: "cohortNthWeek"}
+
+	tests := []struct {
+		dimensions []string
+		want       []string
+	}{
+		{dimensions: nil, want: []string{"cohort", "cohortNthWeek"}},
+		{dimensions: []string{"country"}, want: []string{"cohort", "cohortNthWeek", "country"}},
+		{dimensions: []string{"country", "cohortNthWeek", "cohort"}, want: []string{"cohort", "cohortNthWeek", "country"}},
+	}
+	for _, tt := range tests {
+		if got := cohortDimensions(spec, tt.dimensions); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("cohortDimensions(%v) = %v, want %v", tt.dimensions, got, tt.want)
+		}
+	}
+}
+
+func TestBuildRunReportRequest_Cohort(t *testing.T) {
+	request := &GA4ReportRequest{
+		StartDate:  "2024-01-01",
+		EndDate:    "2024-01-31",
+		Dimensions: []string{"cohort", "cohortNthWeek"},
+		Metrics:    []string{"cohortActiveUsers"},
+		Cohort: &CohortSpec{
+			Cohorts:         []config.CohortRange{{Name: "2024-01-01", StartDate: "2024-01-01", EndDate: "2024-01-07"}},
+			Granularity:     "WEEKLY",
+			PeriodDimension: "cohortNthWeek",
+			Periods:         6,
+		},
+	}
+
+	got := buildRunReportRequest(request, 0, 100)
+	if got.DateRanges != nil {
+		t.Errorf("コホートレポートでは DateRanges を指定しない必要があります: %+v", got.DateRanges)
+	}
+	spec := got.CohortSpec
+	if spec == nil || len(spec.Cohorts) != 1 {
+		t.Fatalf("CohortSpec = %+v", spec)
+	}
+	if c := spec.Cohorts[0]; c.Name != "2024-01-01" || c.Dimension != "firstSessionDate" || c.DateRange.StartDate != "2024-01-01" || c.DateRange.EndDate != "2024-01-07" {
+		t.Errorf("Cohorts[0] = %+v", c)
+	}
+	if r := spec.CohortsRange; r.Granularity != "WEEKLY" || r.StartOffset != 0 || r.EndOffset != 5 {
+		t.Errorf("CohortsRange = %+v, want WEEKLY 0-5", r)
+	}
+}
+
+// cohortRow はコホートレポートのテスト用の行を作成する
+func cohortRow(cohort, period string, metrics ...string) *analyticsdata.Row {
+	row := &analyticsdata.Row{
+		DimensionValues: []*analyticsdata.DimensionValue{{Value: cohort}, {Value: period}},
+	}
+	for _, m := range metrics {
+		row.MetricValues = append(row.MetricValues, &analyticsdata.MetricValue{Value: m})
+	}
+	return row
+}
+
+// cohortTestResponse はコホートレポートのテスト用のレスポンスを作成する（行はAPIの並びを模して期間順）
+func cohortTestResponse() *analyticsdata.RunReportResponse {
+	return &analyticsdata.RunReportResponse{
+		DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: "cohort"}, {Name: "cohortNthWeek"}},
+		MetricHeaders:    []*analyticsdata.MetricHeader{{Name: "cohortActiveUsers", Type: "TYPE_INTEGER"}},
+		Rows: []*analyticsdata.Row{
+			cohortRow("2024-01-08", "0000", "80"),
+			cohortRow("2024-01-01", "0000", "100"),
+			cohortRow("2024-01-01", "0001", "40"),
+			cohortRow("2024-01-01", "0002", "25"),
+			cohortRow("2024-01-08", "0001", "30"),
+		},
+		RowCount: 5,
+	}
+}
+
+func TestFlattenCohortResponse(t *testing.T) {
+	spec := &CohortSpec{
+		Cohorts: []config.CohortRange{
+			{Name: "2024-01-01", StartDate: "2024-01-01", EndDate: "2024-01-07"},
+			{Name: "2024-01-08", StartDate: "2024-01-08", EndDate: "2024-01-14"},
+		},
+		PeriodDimension: "cohortNthWeek",
+		PeriodUnit:      "week",
+		Periods:         3,
+	}
+
+	result := flattenCohortResponse(spec, newReportResponse(cohortTestResponse()))
+
+	schema := buildSchema(result)
+	wantColumns := []string{"property_id", "stream_id", "cohort", "cohortActiveUsers (week 0)", "cohortActiveUsers (week 1)", "cohortActiveUsers (week 2)"}
+	if got := schema.Columns(); !reflect.DeepEqual(got, wantColumns) {
+		t.Errorf("列 = %v, want %v", got, wantColumns)
+	}
+	if cc, ok := schema.CohortColumn("cohortActiveUsers (week 1)"); !ok || cc.Period != 1 || cc.Metric != "cohortActiveUsers" {
+		t.Errorf("CohortColumn(week 1) = %+v, %v", cc, ok)
+	}
+	if got := schema.MetricType("cohortActiveUsers (week 2)"); got != MetricTypeInteger {
+		t.Errorf("MetricType(week 2) = %q, want %q", got, MetricTypeInteger)
+	}
+
+	// コホートの指定順に並び、経過していない期間は空になる
+	rows := (&AnalyticsServiceImpl{}).convertResponseToRows(result, "111", "1")
+	want := [][]string{
+		{"111", "1", "2024-01-01", "100", "40", "25"},
+		{"111", "1", "2024-01-08", "80", "30", ""},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+}
+
+func TestFetchDataConcurrently_Cohort(t *testing.T) {
+	var received analyticsdata.RunReportRequest
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, cohortTestResponse())
+	}
+	service := &AnalyticsServiceImpl{client: newTestGA4Client(t, handler)}
+
+	cfg := createTestConfig()
+	cfg.StartDate = "2024-01-01"
+	cfg.EndDate = "2024-01-14"
+	cfg.Properties = []config.Property{{
+		ID: "111111111",
+		Streams: []config.Stream{{
+			ID:      "1",
+			Metrics: []string{"cohortActiveUsers"},
+			Cohort:  &config.Cohort{Granularity: "weekly", Periods: 2},
+		}},
+	}}
+
+	requests, err := service.buildReportRequests(cfg)
+	if err != nil {
+		t.Fatalf("buildReportRequests() error = %v", err)
+	}
+	data, err := service.fetchDataConcurrently(context.Background(), requests, cfg)
+	if err != nil {
+		t.Fatalf("fetchDataConcurrently() error = %v", err)
+	}
+
+	if received.CohortSpec == nil || len(received.CohortSpec.Cohorts) != 2 || len(received.DateRanges) != 0 {
+		t.Errorf("リクエスト: CohortSpec = %+v, DateRanges = %+v", received.CohortSpec, received.DateRanges)
+	}
+	var dimensions []string
+	for _, dim := range received.Dimensions {
+		dimensions = append(dimensions, dim.Name)
+	}
+	if want := []string{"cohort", "cohortNthWeek"}; !reflect.DeepEqual(dimensions, want) {
+		t.Errorf("リクエストのディメンション = %v, want %v", dimensions, want)
+	}
+
+	wantHeaders := []string{"property_id", "stream_id", "cohort", "cohortActiveUsers (week 0)", "cohortActiveUsers (week 1)"}
+	if !reflect.DeepEqual(data.Headers, wantHeaders) {
+		t.Errorf("Headers = %v, want %v", data.Headers, wantHeaders)
+	}
+	wantRows := [][]string{
+		{"111111111", "1", "2024-01-01", "100", "40"},
+		{"111111111", "1", "2024-01-08", "80", "30"},
+	}
+	if !reflect.DeepEqual(data.Rows, wantRows) {
+		t.Errorf("Rows = %v, want %v", data.Rows, wantRows)
+	}
+}
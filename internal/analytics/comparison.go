@@ -0,0 +1,301 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// 比較期間を指定した場合に追加される列の接尾辞
+const (
+	ComparisonSuffix = "_comparison" // 比較期間の値
+	ChangeSuffix     = "_change"     // 差分（集計期間 - 比較期間）
+	ChangePctSuffix  = "_change_pct" // 変化率（%）
+)
+
+// 複数の日付範囲を指定した場合にdateRangeディメンションに入る範囲名
+const (
+	currentDateRangeName    = "current"
+	comparisonDateRangeName = "comparison"
+)
+
+// dateRangeDimension は複数の日付範囲を指定した場合にAPIが自動で追加するディメンション名
+const dateRangeDimension = "dateRange"
+
+// hasComparison は比較期間が指定されているかを判定する
+func (r *GA4ReportRequest) hasComparison() bool {
+	return r.ComparisonStartDate != "" && r.ComparisonEndDate != ""
+}
+
+// buildDateRanges はリクエストの日付範囲を構築する
+// 比較期間が指定されている場合は集計期間と比較期間の2つを名前付きで指定する
+// コホートレポートではコホートごとの期間を使用するためnilを返す
+func buildDateRanges(request *GA4ReportRequest) []*analyticsdata.DateRange {
+	if request.Cohort != nil {
+		return nil
+	}
+	if !request.hasComparison() {
+		return []*analyticsdata.DateRange{
+			{
+				StartDate: request.StartDate,
+				EndDate:   request.EndDate,
+			},
+		}
+	}
+
+	return []*analyticsdata.DateRange{
+		{
+			StartDate: request.StartDate,
+			EndDate:   request.EndDate,
+			Name:      currentDateRangeName,
+		},
+		{
+			StartDate: request.ComparisonStartDate,
+			EndDate:   request.ComparisonEndDate,
+			Name:      comparisonDateRangeName,
+		},
+	}
+}
+
+// mergeComparison は日付範囲ごとに分かれた行を同じディメンション値ごとに1行にまとめ、
+// 各メトリクスに比較期間の値・差分・変化率の列を追加したレスポンスを返す
+// 行の順序はAPIレスポンスで最初に現れた順序を保つ
+func mergeComparison(response *GA4ReportResponse) *GA4ReportResponse {
+	dateRangeIndex := -1
+	var dimensionHeaders []*analyticsdata.DimensionHeader
+	for i, header := range response.DimensionHeaders {
+		if header.Name == dateRangeDimension && dateRangeIndex < 0 {
+			dateRangeIndex = i
+			continue
+		}
+		dimensionHeaders = append(dimensionHeaders, header)
+	}
+
+	var metricHeaders []*analyticsdata.MetricHeader
+	for _, header := range response.MetricHeaders {
+		metricHeaders = append(metricHeaders,
+			header,
+			&analyticsdata.MetricHeader{Name: header.Name + ComparisonSuffix, Type: header.Type},
+			&analyticsdata.MetricHeader{Name: header.Name + ChangeSuffix, Type: header.Type},
+			&analyticsdata.MetricHeader{Name: header.Name + ChangePctSuffix, Type: "TYPE_FLOAT"},
+		)
+	}
+
+	type mergedRow struct {
+		dimensions []*analyticsdata.DimensionValue
+		current    []*analyticsdata.MetricValue
+		comparison []*analyticsdata.MetricValue
+	}
+
+	var order []string
+	groups := make(map[string]*mergedRow)
+
+	for _, row := range response.Rows {
+		rangeName := currentDateRangeName
+		var dimensions []*analyticsdata.DimensionValue
+		for i, value := range row.DimensionValues {
+			if i == dateRangeIndex {
+				rangeName = value.Value
+				continue
+			}
+			dimensions = append(dimensions, value)
+		}
+
+		key := dimensionKey(dimensions)
+		group, exists := groups[key]
+		if !exists {
+			group = &mergedRow{dimensions: dimensions}
+			groups[key] = group
+			order = append(order, key)
+		}
+
+		if rangeName == comparisonDateRangeName {
+			group.comparison = row.MetricValues
+		} else {
+			group.current = row.MetricValues
+		}
+	}
+
+	rows := make([]*analyticsdata.Row, 0, len(order))
+	for _, key := range order {
+		group := groups[key]
+
+		var metricValues []*analyticsdata.MetricValue
+		for i := range response.MetricHeaders {
+			current := metricValueAt(group.current, i)
+			comparison := metricValueAt(group.comparison, i)
+			change, changePct := calculateChange(current, comparison)
+
+			metricValues = append(metricValues,
+				&analyticsdata.MetricValue{Value: current},
+				&analyticsdata.MetricValue{Value: comparison},
+				&analyticsdata.MetricValue{Value: change},
+				&analyticsdata.MetricValue{Value: changePct},
+			)
+		}
+
+		rows = append(rows, &analyticsdata.Row{
+			DimensionValues: group.dimensions,
+			MetricValues:    metricValues,
+		})
+	}
+
+	return &GA4ReportResponse{
+		DimensionHeaders: dimensionHeaders,
+		MetricHeaders:    metricHeaders,
+		Rows:             rows,
+		RowCount:         int64(len(rows)),
+		Metadata:         response.Metadata,
+		Totals:           mergeComparisonAggregation(response, response.Totals),
+		Minimums:         mergeComparisonAggregation(response, response.Minimums),
+		Maximums:         mergeComparisonAggregation(response, response.Maximums),
+	}
+}
+
+// sortComparisonRows はまとめた行を集計期間の値でorder_byの順に並べ替える
+// APIは2つの期間の行をまとめて並べるため、比較期間の値で上位になった行が先頭に来ることがある
+func sortComparisonRows(response *GA4ReportResponse, orderBys []*analyticsdata.OrderBy) {
+	if len(orderBys) == 0 {
+		return
+	}
+
+	type sortKey struct {
+		index     int
+		dimension bool
+		orderType string
+		desc      bool
+	}
+	var keys []sortKey
+	for _, orderBy := range orderBys {
+		switch {
+		case orderBy.Dimension != nil:
+			for i, header := range response.DimensionHeaders {
+				if header.Name == orderBy.Dimension.DimensionName {
+					keys = append(keys, sortKey{index: i, dimension: true, orderType: orderBy.Dimension.OrderType, desc: orderBy.Desc})
+					break
+				}
+			}
+		case orderBy.Metric != nil:
+			for i, header := range response.MetricHeaders {
+				if header.Name == orderBy.Metric.MetricName {
+					keys = append(keys, sortKey{index: i, orderType: "NUMERIC", desc: orderBy.Desc})
+					break
+				}
+			}
+		}
+	}
+
+	valueAt := func(row *analyticsdata.Row, key sortKey) string {
+		if key.dimension {
+			if key.index < len(row.DimensionValues) && row.DimensionValues[key.index] != nil {
+				return row.DimensionValues[key.index].Value
+			}
+			return ""
+		}
+		return metricValueAt(row.MetricValues, key.index)
+	}
+
+	sort.SliceStable(response.Rows, func(i, j int) bool {
+		for _, key := range keys {
+			cmp := compareOrderValues(valueAt(response.Rows[i], key), valueAt(response.Rows[j], key), key.orderType)
+			if cmp == 0 {
+				continue
+			}
+			if key.desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+}
+
+// compareOrderValues はGA4 APIのOrderTypeに従って2つの値を比較する
+// 数値として解釈できない値は文字列として比較する
+func compareOrderValues(a, b, orderType string) int {
+	switch orderType {
+	case "NUMERIC":
+		x, errA := strconv.ParseFloat(a, 64)
+		y, errB := strconv.ParseFloat(b, 64)
+		if errA == nil && errB == nil {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	case "CASE_INSENSITIVE_ALPHANUMERIC":
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+	return strings.Compare(a, b)
+}
+
+// dimensionKey はディメンション値の組み合わせから行をまとめるためのキーを作成する
+func dimensionKey(values []*analyticsdata.DimensionValue) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = value.Value
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// metricValueAt はメトリクス値を返す
+// GA4は全メトリクスが0の行を返さないため、行が存在しない期間の値は0として扱う
+func metricValueAt(values []*analyticsdata.MetricValue, index int) string {
+	if values == nil {
+		return "0"
+	}
+	if index >= len(values) || values[index] == nil {
+		return ""
+	}
+	return values[index].Value
+}
+
+// calculateChange は集計期間と比較期間の値から差分と変化率（%）を計算する
+// 数値として解釈できない場合、または比較期間の値が0の場合の変化率は空文字を返す
+func calculateChange(current, comparison string) (string, string) {
+	cur, err := strconv.ParseFloat(current, 64)
+	if err != nil {
+		return "", ""
+	}
+	cmp, err := strconv.ParseFloat(comparison, 64)
+	if err != nil {
+		return "", ""
+	}
+
+	change := formatNumber(roundTo(cur-cmp, 6))
+	if cmp == 0 {
+		return change, ""
+	}
+	return change, formatNumber(roundTo((cur-cmp)/math.Abs(cmp)*100, 2))
+}
+
+// roundTo は値を指定した小数点以下の桁数に丸める
+func roundTo(value float64, digits int) float64 {
+	scale := math.Pow(10, float64(digits))
+	return math.Round(value*scale) / scale
+}
+
+// formatNumber は数値を指数表記を使わない最短の文字列に変換する
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
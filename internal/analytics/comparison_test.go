@@ -0,0 +1,329 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+func TestBuildDateRanges(t *testing.T) {
+	request := &GA4ReportRequest{StartDate: "2024-02-01", EndDate: "2024-02-29"}
+
+	ranges := buildDateRanges(request)
+	if len(ranges) != 1 || ranges[0].Name != "" {
+		t.Fatalf("比較期間なしの日付範囲 = %+v, want 1件（名前なし）", ranges)
+	}
+
+	request.ComparisonStartDate = "2024-01-01"
+	request.ComparisonEndDate = "2024-01-31"
+
+	ranges = buildDateRanges(request)
+	if len(ranges) != 2 {
+		t.Fatalf("比較期間ありの日付範囲の件数 = %d, want 2", len(ranges))
+	}
+	if ranges[0].Name != "current" || ranges[0].StartDate != "2024-02-01" {
+		t.Errorf("集計期間 = %+v", ranges[0])
+	}
+	if ranges[1].Name != "comparison" || ranges[1].StartDate != "2024-01-01" || ranges[1].EndDate != "2024-01-31" {
+		t.Errorf("比較期間 = %+v", ranges[1])
+	}
+}
+
+// comparisonRow は比較期間付きレスポンスの行を作成する
+func comparisonRow(pagePath, dateRange string, metrics ...string) *analyticsdata.Row {
+	row := &analyticsdata.Row{
+		DimensionValues: []*analyticsdata.DimensionValue{{Value: pagePath}, {Value: dateRange}},
+	}
+	for _, m := range metrics {
+		row.MetricValues = append(row.MetricValues, &analyticsdata.MetricValue{Value: m})
+	}
+	return row
+}
+
+func TestMergeComparison(t *testing.T) {
+	response := &GA4ReportResponse{
+		DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: "pagePath"}, {Name: "dateRange"}},
+		MetricHeaders: []*analyticsdata.MetricHeader{
+			{Name: "sessions", Type: "TYPE_INTEGER"},
+			{Name: "averageSessionDuration", Type: "TYPE_SECONDS"},
+		},
+		Rows: []*analyticsdata.Row{
+			comparisonRow("/a", "current", "150", "30.5"),
+			comparisonRow("/b", "comparison", "40", "10"),
+			comparisonRow("/a", "comparison", "100", "20"),
+			comparisonRow("/c", "current", "5", "1"),
+		},
+		RowCount: 4,
+	}
+
+	merged := mergeComparison(response)
+
+	var dimHeaders, metricHeaders []string
+	for _, h := range merged.DimensionHeaders {
+		dimHeaders = append(dimHeaders, h.Name)
+	}
+	for _, h := range merged.MetricHeaders {
+		metricHeaders = append(metricHeaders, h.Name)
+	}
+	if !reflect.DeepEqual(dimHeaders, []string{"pagePath"}) {
+		t.Errorf("ディメンションヘッダー = %v", dimHeaders)
+	}
+	wantMetricHeaders := []string{
+		"sessions", "sessions_comparison", "sessions_change", "sessions_change_pct",
+		"averageSessionDuration", "averageSessionDuration_comparison", "averageSessionDuration_change", "averageSessionDuration_change_pct",
+	}
+	if !reflect.DeepEqual(metricHeaders, wantMetricHeaders) {
+		t.Errorf("メトリクスヘッダー = %v, want %v", metricHeaders, wantMetricHeaders)
+	}
+
+	want := map[string][]string{
+		"/a": {"150", "100", "50", "50", "30.5", "20", "10.5", "52.5"},
+		"/b": {"0", "40", "-40", "-100", "0", "10", "-10", "-100"},
+		"/c": {"5", "0", "5", "", "1", "0", "1", ""},
+	}
+
+	if merged.RowCount != 3 || len(merged.Rows) != 3 {
+		t.Fatalf("行数 = %d (RowCount %d), want 3", len(merged.Rows), merged.RowCount)
+	}
+
+	// 最初に現れた順序を保つ
+	for i, path := range []string{"/a", "/b", "/c"} {
+		row := merged.Rows[i]
+		if row.DimensionValues[0].Value != path {
+			t.Errorf("行 %d のpagePath = %s, want %s", i, row.DimensionValues[0].Value, path)
+			continue
+		}
+		var got []string
+		for _, v := range row.MetricValues {
+			got = append(got, v.Value)
+		}
+		if !reflect.DeepEqual(got, want[path]) {
+			t.Errorf("%s のメトリクス = %v, want %v", path, got, want[path])
+		}
+	}
+}
+
+func TestCalculateChange(t *testing.T) {
+	tests := []struct {
+		current, comparison string
+		wantChange, wantPct string
+	}{
+		{"120", "100", "20", "20"},
+		{"0.3", "0.1", "0.2", "200"},
+		{"1", "3", "-2", "-66.67"},
+		{"10", "0", "10", ""},
+		{"", "10", "", ""},
+		{"abc", "10", "", ""},
+	}
+
+	for _, tt := range tests {
+		change, pct := calculateChange(tt.current, tt.comparison)
+		if change != tt.wantChange || pct != tt.wantPct {
+			t.Errorf("calculateChange(%q, %q) = (%q, %q), want (%q, %q)",
+				tt.current, tt.comparison, change, pct, tt.wantChange, tt.wantPct)
+		}
+	}
+}
+
+func TestGA4Client_runReport_Comparison(t *testing.T) {
+	var received *analyticsdata.RunReportRequest
+	client := newTestGA4Client(t, func(w http.ResponseWriter, r *http.Request) {
+		received = &analyticsdata.RunReportRequest{}
+		json.NewDecoder(r.Body).Decode(received)
+
+		json.NewEncoder(w).Encode(&analyticsdata.RunReportResponse{
+			DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: "pagePath"}, {Name: "dateRange"}},
+			MetricHeaders:    []*analyticsdata.MetricHeader{{Name: "sessions", Type: "TYPE_INTEGER"}},
+			Rows: []*analyticsdata.Row{
+				comparisonRow("/a", "current", "12"),
+				comparisonRow("/a", "comparison", "10"),
+			},
+			RowCount: 2,
+		})
+	})
+
+	response, err := client.runReport(context.Background(), &GA4ReportRequest{
+		PropertyID:          "123456789",
+		StartDate:           "2024-02-01",
+		EndDate:             "2024-02-29",
+		ComparisonStartDate: "2024-01-01",
+		ComparisonEndDate:   "2024-01-31",
+		Dimensions:          []string{"pagePath"},
+		Metrics:             []string{"sessions"},
+	})
+	if err != nil {
+		t.Fatalf("runReport() error = %v", err)
+	}
+
+	if len(received.DateRanges) != 2 {
+		t.Errorf("送信された日付範囲の件数 = %d, want 2", len(received.DateRanges))
+	}
+	if len(response.Rows) != 1 || len(response.Rows[0].MetricValues) != 4 {
+		t.Fatalf("まとめた行 = %+v, want 1行4列", response.Rows)
+	}
+	if got := response.Rows[0].MetricValues[3].Value; got != "20" {
+		t.Errorf("sessions_change_pct = %s, want 20", got)
+	}
+}
+
+func TestGA4Client_runReport_ComparisonLimit(t *testing.T) {
+	var received *analyticsdata.RunReportRequest
+	client := newTestGA4Client(t, func(w http.ResponseWriter, r *http.Request) {
+		received = &analyticsdata.RunReportRequest{}
+		json.NewDecoder(r.Body).Decode(received)
+
+		json.NewEncoder(w).Encode(&analyticsdata.RunReportResponse{
+			DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: "pagePath"}, {Name: "dateRange"}},
+			MetricHeaders:    []*analyticsdata.MetricHeader{{Name: "sessions", Type: "TYPE_INTEGER"}},
+			Rows: []*analyticsdata.Row{
+				comparisonRow("/a", "current", "12"),
+				comparisonRow("/a", "comparison", "10"),
+				comparisonRow("/b", "current", "8"),
+				comparisonRow("/b", "comparison", "6"),
+				comparisonRow("/c", "comparison", "5"),
+			},
+			RowCount: 5,
+		})
+	})
+
+	response, err := client.runReport(context.Background(), &GA4ReportRequest{
+		PropertyID:          "123456789",
+		StartDate:           "2024-02-01",
+		EndDate:             "2024-02-29",
+		ComparisonStartDate: "2024-01-01",
+		ComparisonEndDate:   "2024-01-31",
+		Dimensions:          []string{"pagePath"},
+		Metrics:             []string{"sessions"},
+		Limit:               2,
+	})
+	if err != nil {
+		t.Fatalf("runReport() error = %v", err)
+	}
+
+	// 2つの期間の行が混在するため、APIからは件数を絞らずに取得する
+	if received.Limit != DefaultPageSize {
+		t.Errorf("送信された limit = %d, want %d", received.Limit, DefaultPageSize)
+	}
+
+	// limit はまとめた後の行に適用する
+	var paths []string
+	for _, row := range response.Rows {
+		paths = append(paths, row.DimensionValues[0].Value)
+		if got := row.MetricValues[1].Value; got == "0" {
+			t.Errorf("%s の比較期間の値が0になっています", row.DimensionValues[0].Value)
+		}
+	}
+	if want := []string{"/a", "/b"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("まとめた行 = %v, want %v", paths, want)
+	}
+}
+
+func TestGA4Client_runReport_ComparisonOrderByLimit(t *testing.T) {
+	client := newTestGA4Client(t, func(w http.ResponseWriter, r *http.Request) {
+		// APIは2つの期間の行をまとめて並べるため、比較期間の値が大きい /old が先頭に来る
+		json.NewEncoder(w).Encode(&analyticsdata.RunReportResponse{
+			DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: "pagePath"}, {Name: "dateRange"}},
+			MetricHeaders:    []*analyticsdata.MetricHeader{{Name: "sessions", Type: "TYPE_INTEGER"}},
+			Rows: []*analyticsdata.Row{
+				comparisonRow("/old", "comparison", "100"),
+				comparisonRow("/a", "current", "50"),
+				comparisonRow("/b", "current", "40"),
+				comparisonRow("/a", "comparison", "30"),
+				comparisonRow("/old", "current", "1"),
+			},
+			RowCount: 5,
+		})
+	})
+
+	response, err := client.runReport(context.Background(), &GA4ReportRequest{
+		PropertyID:          "123456789",
+		StartDate:           "2024-02-01",
+		EndDate:             "2024-02-29",
+		ComparisonStartDate: "2024-01-01",
+		ComparisonEndDate:   "2024-01-31",
+		Dimensions:          []string{"pagePath"},
+		Metrics:             []string{"sessions"},
+		OrderBys:            []*analyticsdata.OrderBy{{Metric: &analyticsdata.MetricOrderBy{MetricName: "sessions"}, Desc: true}},
+		Limit:               2,
+	})
+	if err != nil {
+		t.Fatalf("runReport() error = %v", err)
+	}
+
+	// 上位N件は集計期間の値で決める
+	var paths []string
+	for _, row := range response.Rows {
+		paths = append(paths, row.DimensionValues[0].Value)
+	}
+	if want := []string{"/a", "/b"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("まとめた行 = %v, want %v", paths, want)
+	}
+}
+
+func TestSortComparisonRows_Dimension(t *testing.T) {
+	response := &GA4ReportResponse{
+		DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: "pagePath"}},
+		Rows: []*analyticsdata.Row{
+			{DimensionValues: []*analyticsdata.DimensionValue{{Value: "/b"}}},
+			{DimensionValues: []*analyticsdata.DimensionValue{{Value: "/A"}}},
+			{DimensionValues: []*analyticsdata.DimensionValue{{Value: "/c"}}},
+		},
+	}
+
+	sortComparisonRows(response, []*analyticsdata.OrderBy{{
+		Dimension: &analyticsdata.DimensionOrderBy{DimensionName: "pagePath", OrderType: "CASE_INSENSITIVE_ALPHANUMERIC"},
+	}})
+
+	var paths []string
+	for _, row := range response.Rows {
+		paths = append(paths, row.DimensionValues[0].Value)
+	}
+	if want := []string{"/A", "/b", "/c"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("並べ替えた行 = %v, want %v", paths, want)
+	}
+}
+
+func TestBuildReportRequests_Comparison(t *testing.T) {
+	service := &AnalyticsServiceImpl{}
+	cfg := &config.Config{
+		StartDate: "2024-03-01",
+		EndDate:   "2024-03-31",
+		Account:   "123456789",
+		Properties: []config.Property{{
+			ID: "987654321",
+			Streams: []config.Stream{{
+				ID:         "1234567",
+				Dimensions: []string{"pagePath"},
+				Metrics:    []string{"sessions"},
+				Compare:    &config.Comparison{Type: "previous_period"},
+			}},
+		}},
+	}
+
+	requests, err := service.buildReportRequests(cfg)
+	if err != nil {
+		t.Fatalf("buildReportRequests() error = %v", err)
+	}
+	if requests[0].ComparisonStartDate != "2024-01-30" || requests[0].ComparisonEndDate != "2024-02-29" {
+		t.Errorf("比較期間 = %s - %s, want 2024-01-30 - 2024-02-29", requests[0].ComparisonStartDate, requests[0].ComparisonEndDate)
+	}
+}
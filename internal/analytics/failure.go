@@ -0,0 +1,113 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	stderrors "errors"
+	"fmt"
+	"sort"
+
+	"github.com/ymotongpoo/ga/internal/errors"
+	"google.golang.org/api/googleapi"
+)
+
+// RequestFailure は取得に失敗したリクエストを表す構造体
+// continue_on_error 指定時は失敗したリクエストの一覧としてReportDataに含める
+type RequestFailure struct {
+	PropertyID string `json:"property_id"`
+	StreamID   string `json:"stream_id,omitempty"`
+	Type       string `json:"type"`                  // GAErrorの種類（例: AUTH_ERROR）。GAError以外の場合は UNKNOWN_ERROR
+	Code       string `json:"code,omitempty"`        // GAErrorのエラーコード（例: API001）
+	HTTPStatus int    `json:"http_status,omitempty"` // APIが返したHTTPステータスコード
+	Message    string `json:"message"`
+	Cause      string `json:"cause,omitempty"`
+}
+
+// FetchError はデータ取得が失敗した場合に、失敗したリクエストの一覧を保持するエラー
+// continue_on_error を指定していない場合や全てのリクエストが失敗した場合に返す
+type FetchError struct {
+	Err      error            // 最初に検出したリクエストのエラー
+	Failures []RequestFailure // 失敗した全てのリクエスト（プロパティID、ストリームIDの順）
+}
+
+func (e *FetchError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// newRequestFailure はリクエストのエラーからRequestFailureを作成する
+func newRequestFailure(propertyID, streamID string, err error) RequestFailure {
+	failure := RequestFailure{
+		PropertyID: propertyID,
+		StreamID:   streamID,
+		Type:       "UNKNOWN_ERROR",
+		Message:    err.Error(),
+	}
+
+	var gaErr *errors.GAError
+	if stderrors.As(err, &gaErr) {
+		failure.Type = gaErr.Type.String()
+		failure.Code = gaErr.Code
+		failure.Message = gaErr.Message
+		if gaErr.Cause != nil {
+			failure.Cause = gaErr.Cause.Error()
+		}
+	}
+
+	var apiErr *googleapi.Error
+	if stderrors.As(err, &apiErr) {
+		failure.HTTPStatus = apiErr.Code
+	}
+
+	return failure
+}
+
+// String は失敗したリクエストを表示用の文字列にする
+func (f RequestFailure) String() string {
+	code := f.Type
+	if f.Code != "" {
+		code = fmt.Sprintf("%s:%s", f.Type, f.Code)
+	}
+	if f.HTTPStatus != 0 {
+		code = fmt.Sprintf("%s, HTTP %d", code, f.HTTPStatus)
+	}
+
+	message := fmt.Sprintf("プロパティ %s, ストリーム %s: [%s] %s", f.PropertyID, f.StreamID, code, f.Message)
+	if f.Cause != "" {
+		message += fmt.Sprintf("（原因: %s）", f.Cause)
+	}
+	return message
+}
+
+// sortFailures は失敗したリクエストをプロパティID、ストリームIDの順に並べる
+func sortFailures(failures []RequestFailure) {
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].PropertyID != failures[j].PropertyID {
+			return failures[i].PropertyID < failures[j].PropertyID
+		}
+		return failures[i].StreamID < failures[j].StreamID
+	})
+}
+
+// printFailures は失敗したリクエストの一覧を表示する
+func printFailures(failures []RequestFailure) {
+	fmt.Printf("⚠️  %d 件のリクエストが失敗しました:\n", len(failures))
+	for _, failure := range failures {
+		fmt.Printf("   - %s\n", failure)
+	}
+}
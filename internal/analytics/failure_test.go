@@ -0,0 +1,126 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ymotongpoo/ga/internal/errors"
+)
+
+// failingPropertyHandler は指定したプロパティへのリクエストに403を返し、それ以外には1行のレポートを返す
+func failingPropertyHandler(failing string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "properties/"+failing+":") {
+			http.Error(w, `{"error":{"code":403,"message":"permission denied"}}`, http.StatusForbidden)
+			return
+		}
+		writeJSON(w, singleRowReport("/", "1"))
+	}
+}
+
+func testRequests(propertyIDs ...string) []*GA4ReportRequest {
+	var requests []*GA4ReportRequest
+	for _, propertyID := range propertyIDs {
+		requests = append(requests, &GA4ReportRequest{
+			PropertyID: propertyID, StreamID: "1", StartDate: "2024-01-01", EndDate: "2024-01-31",
+			Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"},
+		})
+	}
+	return requests
+}
+
+func TestFetchDataConcurrently_ContinueOnError(t *testing.T) {
+	service := &AnalyticsServiceImpl{client: newTestGA4Client(t, failingPropertyHandler("222222222"))}
+	cfg := createTestConfig()
+	cfg.ContinueOnError = true
+
+	data, err := service.fetchDataConcurrently(context.Background(), testRequests("111111111", "222222222", "333333333"), cfg)
+	if err != nil {
+		t.Fatalf("fetchDataConcurrently() error = %v", err)
+	}
+
+	if data.Summary.TotalRows != 2 {
+		t.Errorf("TotalRows = %d, want 2（成功したリクエストの行のみ）", data.Summary.TotalRows)
+	}
+	if len(data.Failures) != 1 {
+		t.Fatalf("Failures = %+v, want 1件", data.Failures)
+	}
+
+	failure := data.Failures[0]
+	if failure.PropertyID != "222222222" || failure.StreamID != "1" {
+		t.Errorf("失敗したリクエスト = %s/%s, want 222222222/1", failure.PropertyID, failure.StreamID)
+	}
+	if failure.Type != "AUTH_ERROR" || failure.Code != "AUTH001" || failure.HTTPStatus != http.StatusForbidden {
+		t.Errorf("failure = %+v, want AUTH_ERROR/AUTH001/403", failure)
+	}
+	if !strings.Contains(failure.Cause, "permission denied") {
+		t.Errorf("failure.Cause = %q, want containing %q", failure.Cause, "permission denied")
+	}
+}
+
+func TestFetchDataConcurrently_StopsOnErrorByDefault(t *testing.T) {
+	service := &AnalyticsServiceImpl{client: newTestGA4Client(t, failingPropertyHandler("222222222"))}
+
+	_, err := service.fetchDataConcurrently(context.Background(), testRequests("111111111", "222222222"), createTestConfig())
+	if err == nil || !strings.Contains(err.Error(), "プロパティ 222222222") {
+		t.Errorf("fetchDataConcurrently() error = %v, want failure of property 222222222", err)
+	}
+
+	// 失敗したリクエストの一覧をエラーから取り出せる
+	var fetchErr *FetchError
+	if !stderrors.As(err, &fetchErr) {
+		t.Fatalf("error = %T, want *FetchError", err)
+	}
+	if len(fetchErr.Failures) != 1 || fetchErr.Failures[0].PropertyID != "222222222" {
+		t.Errorf("Failures = %+v, want 222222222 のみ", fetchErr.Failures)
+	}
+}
+
+func TestFetchDataConcurrently_ContinueOnErrorAllFailed(t *testing.T) {
+	service := &AnalyticsServiceImpl{client: newTestGA4Client(t, failingPropertyHandler("111111111"))}
+	cfg := createTestConfig()
+	cfg.ContinueOnError = true
+
+	_, err := service.fetchDataConcurrently(context.Background(), testRequests("111111111"), cfg)
+	if err == nil {
+		t.Fatal("全てのリクエストが失敗した場合はエラーを返す必要があります")
+	}
+
+	var fetchErr *FetchError
+	if !stderrors.As(err, &fetchErr) || len(fetchErr.Failures) != 1 {
+		t.Errorf("error = %v, want *FetchError with 1 failure", err)
+	}
+}
+
+func TestNewRequestFailure(t *testing.T) {
+	failure := newRequestFailure("123", "456", fmt.Errorf("取得に失敗しました"))
+	if failure.Type != "UNKNOWN_ERROR" || failure.Message != "取得に失敗しました" {
+		t.Errorf("GAError以外のエラー: %+v", failure)
+	}
+
+	failure = newRequestFailure("123", "456", errors.NewAPIError("APIエラー", nil))
+	if failure.Type != "API_ERROR" || failure.Code != "API001" || failure.Cause != "" || failure.HTTPStatus != 0 {
+		t.Errorf("原因のないGAError: %+v", failure)
+	}
+	if got, want := failure.String(), "プロパティ 123, ストリーム 456: [API_ERROR:API001] APIエラー"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
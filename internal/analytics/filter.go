@@ -0,0 +1,114 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"github.com/ymotongpoo/ga/internal/config"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// buildFilterExpression は設定ファイルのフィルタ式をGA4 APIのFilterExpressionに変換する
+// 設定はValidateConfigで検証済みであることを前提とする
+func buildFilterExpression(expr *config.FilterExpression) *analyticsdata.FilterExpression {
+	if expr == nil {
+		return nil
+	}
+
+	switch {
+	case expr.And != nil:
+		return &analyticsdata.FilterExpression{
+			AndGroup: &analyticsdata.FilterExpressionList{Expressions: buildFilterExpressions(expr.And)},
+		}
+	case expr.Or != nil:
+		return &analyticsdata.FilterExpression{
+			OrGroup: &analyticsdata.FilterExpressionList{Expressions: buildFilterExpressions(expr.Or)},
+		}
+	case expr.Not != nil:
+		return &analyticsdata.FilterExpression{
+			NotExpression: buildFilterExpression(expr.Not),
+		}
+	}
+
+	filter := &analyticsdata.Filter{FieldName: expr.Field}
+
+	switch {
+	case expr.String != nil:
+		matchType := config.StringMatchTypes[expr.String.MatchType]
+		if matchType == "" {
+			matchType = "EXACT"
+		}
+		filter.StringFilter = &analyticsdata.StringFilter{
+			MatchType:     matchType,
+			Value:         expr.String.Value,
+			CaseSensitive: expr.String.CaseSensitive,
+		}
+	case expr.InList != nil:
+		filter.InListFilter = &analyticsdata.InListFilter{
+			Values:        expr.InList.Values,
+			CaseSensitive: expr.InList.CaseSensitive,
+		}
+	case expr.Numeric != nil:
+		filter.NumericFilter = &analyticsdata.NumericFilter{
+			Operation: config.NumericOperations[expr.Numeric.Operation],
+			Value:     numericValue(*expr.Numeric.Value),
+		}
+	case expr.Between != nil:
+		filter.BetweenFilter = &analyticsdata.BetweenFilter{
+			FromValue: numericValue(*expr.Between.From),
+			ToValue:   numericValue(*expr.Between.To),
+		}
+	}
+
+	return &analyticsdata.FilterExpression{Filter: filter}
+}
+
+// buildFilterExpressions はフィルタ式のリストを変換する
+func buildFilterExpressions(exprs []config.FilterExpression) []*analyticsdata.FilterExpression {
+	result := make([]*analyticsdata.FilterExpression, 0, len(exprs))
+	for i := range exprs {
+		result = append(result, buildFilterExpression(&exprs[i]))
+	}
+	return result
+}
+
+// numericValue は数値をNumericValueに変換する
+// 0の場合もAPIに送信されるようにForceSendFieldsを指定する
+func numericValue(v float64) *analyticsdata.NumericValue {
+	return &analyticsdata.NumericValue{
+		DoubleValue:     v,
+		ForceSendFields: []string{"DoubleValue"},
+	}
+}
+
+// combineFilters は複数のフィルタ式をANDで結合する（nilは無視する）
+func combineFilters(filters ...*analyticsdata.FilterExpression) *analyticsdata.FilterExpression {
+	var expressions []*analyticsdata.FilterExpression
+	for _, f := range filters {
+		if f != nil {
+			expressions = append(expressions, f)
+		}
+	}
+
+	switch len(expressions) {
+	case 0:
+		return nil
+	case 1:
+		return expressions[0]
+	default:
+		return &analyticsdata.FilterExpression{
+			AndGroup: &analyticsdata.FilterExpressionList{Expressions: expressions},
+		}
+	}
+}
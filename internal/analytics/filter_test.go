@@ -0,0 +1,117 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+func TestBuildFilterExpression(t *testing.T) {
+	zero := 0.0
+	hundred := 100.0
+
+	expr := &config.FilterExpression{
+		And: []config.FilterExpression{
+			{Field: "pagePath", String: &config.StringFilter{MatchType: "begins_with", Value: "/blog"}},
+			{Or: []config.FilterExpression{
+				{Field: "country", InList: &config.InListFilter{Values: []string{"Japan"}}},
+				{Not: &config.FilterExpression{Field: "deviceCategory", String: &config.StringFilter{Value: "tablet"}}},
+			}},
+			{Field: "sessions", Numeric: &config.NumericFilter{Operation: "greater_than", Value: &zero}},
+			{Field: "sessions", Between: &config.BetweenFilter{From: &zero, To: &hundred}},
+		},
+	}
+
+	got := buildFilterExpression(expr)
+	if got.AndGroup == nil || len(got.AndGroup.Expressions) != 4 {
+		t.Fatalf("AndGroup = %+v, want 4 expressions", got.AndGroup)
+	}
+
+	str := got.AndGroup.Expressions[0].Filter
+	if str.FieldName != "pagePath" || str.StringFilter.MatchType != "BEGINS_WITH" || str.StringFilter.Value != "/blog" {
+		t.Errorf("string filter = %+v", str.StringFilter)
+	}
+
+	or := got.AndGroup.Expressions[1].OrGroup
+	if or == nil || len(or.Expressions) != 2 {
+		t.Fatalf("OrGroup = %+v, want 2 expressions", or)
+	}
+	if or.Expressions[0].Filter.InListFilter == nil || or.Expressions[0].Filter.InListFilter.Values[0] != "Japan" {
+		t.Errorf("in_list filter = %+v", or.Expressions[0].Filter)
+	}
+	not := or.Expressions[1].NotExpression
+	if not == nil || not.Filter.StringFilter.MatchType != "EXACT" {
+		t.Errorf("not expression = %+v, want EXACT string filter", not)
+	}
+
+	numeric := got.AndGroup.Expressions[2].Filter.NumericFilter
+	if numeric == nil || numeric.Operation != "GREATER_THAN" {
+		t.Fatalf("numeric filter = %+v", numeric)
+	}
+	// 0 の値もJSONに含まれることを確認
+	body, err := json.Marshal(numeric.Value)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(body) != `{"doubleValue":0}` {
+		t.Errorf("numeric value JSON = %s, want {\"doubleValue\":0}", body)
+	}
+
+	between := got.AndGroup.Expressions[3].Filter.BetweenFilter
+	if between == nil || between.FromValue.DoubleValue != 0 || between.ToValue.DoubleValue != 100 {
+		t.Errorf("between filter = %+v", between)
+	}
+}
+
+func TestCombineFilters(t *testing.T) {
+	a := &analyticsdata.FilterExpression{Filter: &analyticsdata.Filter{FieldName: "a"}}
+	b := &analyticsdata.FilterExpression{Filter: &analyticsdata.Filter{FieldName: "b"}}
+
+	if got := combineFilters(nil, nil); got != nil {
+		t.Errorf("combineFilters(nil, nil) = %+v, want nil", got)
+	}
+	if got := combineFilters(nil, a); got != a {
+		t.Errorf("combineFilters(nil, a) = %+v, want a", got)
+	}
+	got := combineFilters(a, b)
+	if got.AndGroup == nil || len(got.AndGroup.Expressions) != 2 {
+		t.Errorf("combineFilters(a, b) = %+v, want AND group", got)
+	}
+}
+
+func TestBuildRunReportRequest_UserFilterWithStreamFilter(t *testing.T) {
+	request := &GA4ReportRequest{
+		PropertyID:      "987654321",
+		StreamID:        "1234567",
+		DimensionFilter: &analyticsdata.FilterExpression{Filter: &analyticsdata.Filter{FieldName: "pagePath"}},
+		MetricFilter:    &analyticsdata.FilterExpression{Filter: &analyticsdata.Filter{FieldName: "sessions"}},
+	}
+
+	req := buildRunReportRequest(request, 0, DefaultPageSize)
+
+	if req.DimensionFilter.AndGroup == nil || len(req.DimensionFilter.AndGroup.Expressions) != 2 {
+		t.Fatalf("DimensionFilter = %+v, want streamId AND user filter", req.DimensionFilter)
+	}
+	if req.DimensionFilter.AndGroup.Expressions[0].Filter.FieldName != "streamId" {
+		t.Errorf("first filter = %s, want streamId", req.DimensionFilter.AndGroup.Expressions[0].Filter.FieldName)
+	}
+	if req.MetricFilter != request.MetricFilter {
+		t.Errorf("MetricFilter = %+v, want %+v", req.MetricFilter, request.MetricFilter)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/config"
+)
+
+// requestLimiter はAPI呼び出しの同時実行数（全体・プロパティごと）と1秒あたりの呼び出し数を制限する
+// 1秒あたりの呼び出し数はトークンバケットで制限し、429を受けた場合は全ての呼び出しを一時停止する
+// nilの場合は制限しない
+type requestLimiter struct {
+	global      chan struct{} // 全体の同時実行数のセマフォ
+	perProperty int           // プロパティごとの同時実行数
+
+	mu          sync.Mutex
+	properties  map[string]chan struct{} // プロパティID -> 同時実行数のセマフォ
+	rate        float64                  // 1秒あたりに補充するトークン数
+	burst       float64                  // バケットの容量
+	tokens      float64                  // 現在のトークン数
+	last        time.Time                // 最後にトークンを補充した時刻
+	pausedUntil time.Time                // 429によるバックオフの終了時刻
+}
+
+// newRequestLimiter は設定からrequestLimiterを作成する
+func newRequestLimiter(cfg *config.Concurrency) *requestLimiter {
+	burst := float64(cfg.BurstOrDefault())
+	return &requestLimiter{
+		global:      make(chan struct{}, cfg.MaxInFlightOrDefault()),
+		perProperty: cfg.MaxInFlightPerPropertyOrDefault(),
+		properties:  make(map[string]chan struct{}),
+		rate:        cfg.RequestsPerSecondOrDefault(),
+		burst:       burst,
+		tokens:      burst,
+		last:        time.Now(),
+	}
+}
+
+// acquire はAPI呼び出しの実行枠を確保する
+// 同時実行数に空きができ、バックオフが終わり、トークンが得られるまで待機する
+// 呼び出し後は返されたrelease関数で実行枠を解放する
+func (l *requestLimiter) acquire(ctx context.Context, propertyID string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	property := l.propertySemaphore(propertyID)
+	select {
+	case property <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	select {
+	case l.global <- struct{}{}:
+	case <-ctx.Done():
+		<-property
+		return nil, ctx.Err()
+	}
+	release := func() {
+		<-l.global
+		<-property
+	}
+
+	for {
+		delay := l.reserve(time.Now())
+		if delay <= 0 {
+			return release, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// propertySemaphore はプロパティの同時実行数のセマフォを返す
+func (l *requestLimiter) propertySemaphore(propertyID string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sem, ok := l.properties[propertyID]
+	if !ok {
+		sem = make(chan struct{}, l.perProperty)
+		l.properties[propertyID] = sem
+	}
+	return sem
+}
+
+// reserve はトークンを1つ取得する。取得できない場合は次に試すまでの待機時間を返す
+func (l *requestLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// pause は全てのAPI呼び出しをdelayの間停止する
+// 429を受けたワーカーのバックオフを他のワーカーにも適用するために使用する
+func (l *requestLimiter) pause(delay time.Duration) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until := time.Now().Add(delay)
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+		fmt.Printf("⚠️  API制限（429）のため、全てのAPI呼び出しを %v 停止します\n", delay)
+	}
+}
+
+// beginCall はAPI呼び出しの前にクォータ残量を確認し、実行枠を確保する
+// 呼び出し後は返されたrelease関数で実行枠を解放する
+func (c *GA4Client) beginCall(ctx context.Context, propertyID string) (func(), error) {
+	if err := c.quota.wait(ctx, propertyID); err != nil {
+		return nil, err
+	}
+	return c.limiter.acquire(ctx, propertyID)
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+func TestRequestLimiter_Acquire_PerProperty(t *testing.T) {
+	limiter := newRequestLimiter(&config.Concurrency{MaxInFlight: 2, MaxInFlightPerProperty: 1, RequestsPerSecond: 1000, Burst: 10})
+
+	release, err := limiter.acquire(context.Background(), "111")
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	// 同じプロパティは空きができるまで待機する
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.acquire(ctx, "111"); err != context.DeadlineExceeded {
+		t.Errorf("同じプロパティの acquire() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// 別のプロパティは実行できる
+	releaseOther, err := limiter.acquire(context.Background(), "222")
+	if err != nil {
+		t.Fatalf("別プロパティの acquire() error = %v", err)
+	}
+
+	// 全体の上限（2）に達しているため3つ目のプロパティは待機する
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel2()
+	if _, err := limiter.acquire(ctx2, "333"); err != context.DeadlineExceeded {
+		t.Errorf("全体の上限を超える acquire() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	release()
+	releaseOther()
+	if _, err := limiter.acquire(context.Background(), "111"); err != nil {
+		t.Errorf("解放後の acquire() error = %v", err)
+	}
+}
+
+func TestRequestLimiter_Reserve(t *testing.T) {
+	limiter := newRequestLimiter(&config.Concurrency{RequestsPerSecond: 10, Burst: 2})
+	now := limiter.last
+
+	// バーストの分は待機なしで取得できる
+	for i := 0; i < 2; i++ {
+		if delay := limiter.reserve(now); delay != 0 {
+			t.Fatalf("%d 回目の reserve() = %v, want 0", i+1, delay)
+		}
+	}
+
+	// トークンがなくなると補充されるまで待機する（10 rps なので100ms）
+	if delay := limiter.reserve(now); delay != 100*time.Millisecond {
+		t.Errorf("トークン切れの reserve() = %v, want 100ms", delay)
+	}
+	if delay := limiter.reserve(now.Add(100 * time.Millisecond)); delay != 0 {
+		t.Errorf("補充後の reserve() = %v, want 0", delay)
+	}
+
+	// 一時停止中は停止の終了まで待機する
+	limiter.pause(time.Hour)
+	if delay := limiter.reserve(time.Now()); delay < 59*time.Minute {
+		t.Errorf("一時停止中の reserve() = %v, want about 1h", delay)
+	}
+}
+
+func TestGA4Client_withRetry_SharesBackoffOn429(t *testing.T) {
+	var attempts int32
+	client := newTestGA4Client(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			http.Error(w, `{"error":{"code":429,"message":"quota exceeded"}}`, http.StatusTooManyRequests)
+			return
+		}
+		writeJSON(w, singleRowReport("/", "1"))
+	})
+	client.limiter = newRequestLimiter(&config.Concurrency{RequestsPerSecond: 1000})
+	client.retryConfig.BaseDelay = 50 * time.Millisecond
+	client.retryConfig.MaxDelay = 50 * time.Millisecond
+
+	request := &GA4ReportRequest{PropertyID: "123456789", StartDate: "2024-01-01", EndDate: "2024-01-31", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}}
+	started := time.Now()
+	if _, err := client.runReport(context.Background(), request); err != nil {
+		t.Fatalf("runReport() error = %v", err)
+	}
+
+	// 429を受けたワーカーのバックオフが全体の一時停止として記録される
+	client.limiter.mu.Lock()
+	pausedUntil := client.limiter.pausedUntil
+	client.limiter.mu.Unlock()
+	if pausedUntil.Sub(started) < 50*time.Millisecond {
+		t.Errorf("pausedUntil = %v, want at least 50ms after start", pausedUntil.Sub(started))
+	}
+
+	// 他のワーカーも一時停止が終わるまで待機する
+	if delay := client.limiter.reserve(started); delay <= 0 {
+		t.Errorf("一時停止中の reserve() = %v, want > 0", delay)
+	}
+}
+
+func TestFetchDataConcurrently_BoundedWorkers(t *testing.T) {
+	var inFlight, maxInFlight int32
+	var mu sync.Mutex
+	client := newTestGA4Client(t, func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		mu.Lock()
+		if current > maxInFlight {
+			maxInFlight = current
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+		writeJSON(w, singleRowReport("/", "1"))
+	})
+	service := &AnalyticsServiceImpl{client: client}
+
+	cfg := createTestConfig()
+	cfg.Concurrency = &config.Concurrency{MaxInFlight: 2}
+
+	// プロパティが異なるためバッチにまとめられず、6つのバッチになる
+	var requests []*GA4ReportRequest
+	for i := 0; i < 6; i++ {
+		requests = append(requests, &GA4ReportRequest{
+			PropertyID: strconv.Itoa(100000000 + i), StartDate: "2024-01-01", EndDate: "2024-01-31",
+			Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"},
+		})
+	}
+
+	data, err := service.fetchDataConcurrently(context.Background(), requests, cfg)
+	if err != nil {
+		t.Fatalf("fetchDataConcurrently() error = %v", err)
+	}
+	if data.Summary.TotalRows != 6 {
+		t.Errorf("TotalRows = %d, want 6", data.Summary.TotalRows)
+	}
+	if maxInFlight > 2 {
+		t.Errorf("同時実行数の最大 = %d, want <= 2", maxInFlight)
+	}
+}
+
+// writeJSON はテスト用サーバーからレポートのレスポンスを返す
+func writeJSON(w http.ResponseWriter, response *analyticsdata.RunReportResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	body, _ := response.MarshalJSON()
+	w.Write(body)
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"time"
+
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// MetricType はAPIのメトリクスヘッダーに含まれるメトリクスの型を表す
+type MetricType string
+
+const (
+	MetricTypeInteger      MetricType = "TYPE_INTEGER"
+	MetricTypeFloat        MetricType = "TYPE_FLOAT"
+	MetricTypeSeconds      MetricType = "TYPE_SECONDS"
+	MetricTypeMilliseconds MetricType = "TYPE_MILLISECONDS"
+	MetricTypeMinutes      MetricType = "TYPE_MINUTES"
+	MetricTypeHours        MetricType = "TYPE_HOURS"
+	MetricTypeStandard     MetricType = "TYPE_STANDARD" // カスタム指標（標準）
+	MetricTypeCurrency     MetricType = "TYPE_CURRENCY"
+	MetricTypeFeet         MetricType = "TYPE_FEET"
+	MetricTypeMiles        MetricType = "TYPE_MILES"
+	MetricTypeMeters       MetricType = "TYPE_METERS"
+	MetricTypeKilometers   MetricType = "TYPE_KILOMETERS"
+)
+
+// IsNumeric は型が分かっている（数値として出力できる）かどうかを返す
+// 型が不明な列（テストデータや古いキャッシュなど）は文字列のまま扱う
+func (t MetricType) IsNumeric() bool {
+	return t != "" && t != "METRIC_TYPE_UNSPECIFIED"
+}
+
+// IsInteger は整数の型かどうかを返す
+func (t MetricType) IsInteger() bool {
+	return t == MetricTypeInteger
+}
+
+// DurationUnit は時間の型の場合に値1あたりの時間を返す。時間の型でない場合は0を返す
+func (t MetricType) DurationUnit() time.Duration {
+	switch t {
+	case MetricTypeMilliseconds:
+		return time.Millisecond
+	case MetricTypeSeconds:
+		return time.Second
+	case MetricTypeMinutes:
+		return time.Minute
+	case MetricTypeHours:
+		return time.Hour
+	default:
+		return 0
+	}
+}
+
+// MetricType は列のメトリクスの型を返す。型が不明な場合は空文字を返す
+func (s *Schema) MetricType(column string) MetricType {
+	if s == nil {
+		return ""
+	}
+	return s.MetricTypes[column]
+}
+
+// metricTypes はAPIのメトリクスヘッダーから列名 -> 型のマッピングを作成する
+func metricTypes(headers []*analyticsdata.MetricHeader) map[string]MetricType {
+	types := make(map[string]MetricType, len(headers))
+	for _, header := range headers {
+		if header.Type != "" {
+			types[header.Name] = MetricType(header.Type)
+		}
+	}
+	return types
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+func TestMetricType(t *testing.T) {
+	tests := []struct {
+		metricType MetricType
+		numeric    bool
+		integer    bool
+		unit       time.Duration
+	}{
+		{metricType: MetricTypeInteger, numeric: true, integer: true},
+		{metricType: MetricTypeFloat, numeric: true},
+		{metricType: MetricTypeCurrency, numeric: true},
+		{metricType: MetricTypeSeconds, numeric: true, unit: time.Second},
+		{metricType: MetricTypeMilliseconds, numeric: true, unit: time.Millisecond},
+		{metricType: MetricTypeMinutes, numeric: true, unit: time.Minute},
+		{metricType: MetricTypeHours, numeric: true, unit: time.Hour},
+		{metricType: "METRIC_TYPE_UNSPECIFIED"},
+		{metricType: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.metricType), func(t *testing.T) {
+			if got := tt.metricType.IsNumeric(); got != tt.numeric {
+				t.Errorf("IsNumeric() = %v, want %v", got, tt.numeric)
+			}
+			if got := tt.metricType.IsInteger(); got != tt.integer {
+				t.Errorf("IsInteger() = %v, want %v", got, tt.integer)
+			}
+			if got := tt.metricType.DurationUnit(); got != tt.unit {
+				t.Errorf("DurationUnit() = %v, want %v", got, tt.unit)
+			}
+		})
+	}
+}
+
+func TestBuildSchema_MetricTypes(t *testing.T) {
+	schema := buildSchema(&GA4ReportResponse{
+		DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: "pagePath"}},
+		MetricHeaders: []*analyticsdata.MetricHeader{
+			{Name: "sessions", Type: "TYPE_INTEGER"},
+			{Name: "averageSessionDuration", Type: "TYPE_SECONDS"},
+			{Name: "unknown"},
+		},
+	})
+
+	if got := schema.MetricType("sessions"); got != MetricTypeInteger {
+		t.Errorf("MetricType(sessions) = %q, want %q", got, MetricTypeInteger)
+	}
+	if got := schema.MetricType("averageSessionDuration"); got != MetricTypeSeconds {
+		t.Errorf("MetricType(averageSessionDuration) = %q, want %q", got, MetricTypeSeconds)
+	}
+	if got := schema.MetricType("unknown"); got != "" {
+		t.Errorf("型のないメトリクスの MetricType() = %q, want empty", got)
+	}
+	if got := schema.MetricType("pagePath"); got != "" {
+		t.Errorf("ディメンションの MetricType() = %q, want empty", got)
+	}
+
+	var nilSchema *Schema
+	if got := nilSchema.MetricType("sessions"); got != "" {
+		t.Errorf("nilのスキーマの MetricType() = %q, want empty", got)
+	}
+}
+
+func TestUnionSchema_MetricTypes(t *testing.T) {
+	union := unionSchema([]*Schema{
+		{Metrics: []string{"sessions"}, MetricTypes: map[string]MetricType{"sessions": MetricTypeInteger}},
+		{Metrics: []string{"sessions", "purchaseRevenue"}, MetricTypes: map[string]MetricType{"purchaseRevenue": MetricTypeCurrency}},
+	})
+
+	if got := union.MetricType("sessions"); got != MetricTypeInteger {
+		t.Errorf("MetricType(sessions) = %q, want %q", got, MetricTypeInteger)
+	}
+	if got := union.MetricType("purchaseRevenue"); got != MetricTypeCurrency {
+		t.Errorf("MetricType(purchaseRevenue) = %q, want %q", got, MetricTypeCurrency)
+	}
+}
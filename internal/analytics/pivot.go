@@ -0,0 +1,197 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// PivotSpec はピボットレポートの行と列の設定
+type PivotSpec struct {
+	Rows        []string // 行にするディメンション
+	Columns     []string // 列にするディメンション
+	RowLimit    int64
+	ColumnLimit int64
+}
+
+// PivotColumn はピボット表で生成された1列（列ディメンションの値の組み合わせ × メトリクス）を表す構造体
+type PivotColumn struct {
+	Name   string   // 出力列名（例: sessions (desktop)）
+	Values []string // 列ディメンションの値
+	Metric string   // メトリクス名
+}
+
+// Label は列ディメンションの値の組み合わせを表示用に連結して返す
+func (p PivotColumn) Label() string {
+	return strings.Join(p.Values, ", ")
+}
+
+// pivotColumnName はメトリクスと列ディメンションの値から出力列名を生成する
+func pivotColumnName(metric string, values []string) string {
+	return fmt.Sprintf("%s (%s)", metric, strings.Join(values, ", "))
+}
+
+// runPivotReport はGA4 APIのピボットレポートを実行し、行ディメンション × 生成列の表に変換する（リトライ機能付き）
+func (c *GA4Client) runPivotReport(ctx context.Context, request *GA4ReportRequest) (*GA4ReportResponse, error) {
+	var response *analyticsdata.RunPivotReportResponse
+	operation := fmt.Sprintf("プロパティ %s のピボットレポート取得", request.PropertyID)
+	err := c.withRetry(ctx, operation, func() error {
+		release, err := c.beginCall(ctx, request.PropertyID)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		propertyPath := fmt.Sprintf("properties/%s", request.PropertyID)
+		response, err = c.service.Properties.RunPivotReport(propertyPath, buildRunPivotReportRequest(request)).Context(ctx).Do()
+		if err == nil {
+			c.quota.record(request.PropertyID, response.PropertyQuota)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(response.PivotHeaders) == 2 {
+		warnPivotTruncated(request, "row_limit", request.Pivot.RowLimit, response.PivotHeaders[0].RowCount)
+		warnPivotTruncated(request, "column_limit", request.Pivot.ColumnLimit, response.PivotHeaders[1].RowCount)
+	}
+
+	return flattenPivotResponse(request.Pivot, response)
+}
+
+// warnPivotTruncated はピボットの値の組み合わせが上限により打ち切られた場合に警告する
+func warnPivotTruncated(request *GA4ReportRequest, name string, limit, total int64) {
+	if total > limit {
+		fmt.Printf("⚠️  プロパティ %s, ストリーム %s: %s (%d) により %d 件中 %d 件のみ取得しました\n", request.PropertyID, request.StreamID, name, limit, total, limit)
+	}
+}
+
+// buildRunPivotReportRequest はGA4ReportRequestからピボットレポートのAPIリクエストを構築する
+func buildRunPivotReportRequest(request *GA4ReportRequest) *analyticsdata.RunPivotReportRequest {
+	report := buildRunReportRequest(request, 0, 0)
+
+	return &analyticsdata.RunPivotReportRequest{
+		Dimensions:      report.Dimensions,
+		Metrics:         report.Metrics,
+		DateRanges:      report.DateRanges,
+		DimensionFilter: report.DimensionFilter,
+		MetricFilter:    report.MetricFilter,
+		Pivots: []*analyticsdata.Pivot{
+			{FieldNames: request.Pivot.Rows, Limit: request.Pivot.RowLimit},
+			{FieldNames: request.Pivot.Columns, Limit: request.Pivot.ColumnLimit},
+		},
+		ReturnPropertyQuota: report.ReturnPropertyQuota,
+	}
+}
+
+// flattenPivotResponse はピボットレポートのレスポンスを、行ディメンションと
+// 「列の値の組み合わせ × メトリクス」ごとの生成列からなる表に変換する
+// 値のない組み合わせは0とする
+func flattenPivotResponse(pivot *PivotSpec, response *analyticsdata.RunPivotReportResponse) (*GA4ReportResponse, error) {
+	if len(response.PivotHeaders) != 2 {
+		return nil, fmt.Errorf("ピボットヘッダーの数が不正です: %d", len(response.PivotHeaders))
+	}
+
+	dimIndex := make(map[string]int)
+	for i, header := range response.DimensionHeaders {
+		dimIndex[header.Name] = i
+	}
+	valuesOf := func(row *analyticsdata.Row, fields []string) []string {
+		values := make([]string, len(fields))
+		for i, field := range fields {
+			if idx, ok := dimIndex[field]; ok && idx < len(row.DimensionValues) {
+				values[i] = row.DimensionValues[idx].Value
+			}
+		}
+		return values
+	}
+
+	result := &GA4ReportResponse{Metadata: newResponseMetadata(response.Metadata)}
+	for _, field := range pivot.Rows {
+		result.DimensionHeaders = append(result.DimensionHeaders, &analyticsdata.DimensionHeader{Name: field})
+	}
+
+	// 列はピボットヘッダーの順に、列の値の組み合わせごとに全メトリクスを並べる
+	columnIndex := make(map[string]int)
+	for i, header := range response.PivotHeaders[1].PivotDimensionHeaders {
+		values := pivotHeaderValues(header)
+		columnIndex[strings.Join(values, "\x00")] = i
+		for _, metric := range response.MetricHeaders {
+			result.MetricHeaders = append(result.MetricHeaders, &analyticsdata.MetricHeader{
+				Name: pivotColumnName(metric.Name, values),
+				Type: metric.Type,
+			})
+			result.PivotColumns = append(result.PivotColumns, PivotColumn{
+				Name:   pivotColumnName(metric.Name, values),
+				Values: values,
+				Metric: metric.Name,
+			})
+		}
+	}
+
+	// 行はピボットヘッダーの順に並べる
+	rowIndex := make(map[string]int)
+	newRow := func(values []string) {
+		row := &analyticsdata.Row{}
+		for _, value := range values {
+			row.DimensionValues = append(row.DimensionValues, &analyticsdata.DimensionValue{Value: value})
+		}
+		for range result.MetricHeaders {
+			row.MetricValues = append(row.MetricValues, &analyticsdata.MetricValue{Value: "0"})
+		}
+		rowIndex[strings.Join(values, "\x00")] = len(result.Rows)
+		result.Rows = append(result.Rows, row)
+	}
+	for _, header := range response.PivotHeaders[0].PivotDimensionHeaders {
+		newRow(pivotHeaderValues(header))
+	}
+
+	metricCount := len(response.MetricHeaders)
+	for _, row := range response.Rows {
+		col, ok := columnIndex[strings.Join(valuesOf(row, pivot.Columns), "\x00")]
+		if !ok {
+			continue
+		}
+
+		rowValues := valuesOf(row, pivot.Rows)
+		key := strings.Join(rowValues, "\x00")
+		if _, ok := rowIndex[key]; !ok {
+			newRow(rowValues)
+		}
+		idx := rowIndex[key]
+
+		for m := 0; m < metricCount && m < len(row.MetricValues); m++ {
+			result.Rows[idx].MetricValues[col*metricCount+m].Value = row.MetricValues[m].Value
+		}
+	}
+
+	result.RowCount = int64(len(result.Rows))
+	return result, nil
+}
+
+// pivotHeaderValues はピボットヘッダーのディメンション値を取り出す
+func pivotHeaderValues(header *analyticsdata.PivotDimensionHeader) []string {
+	values := make([]string, len(header.DimensionValues))
+	for i, value := range header.DimensionValues {
+		values[i] = value.Value
+	}
+	return values
+}
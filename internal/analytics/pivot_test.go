@@ -0,0 +1,140 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// pivotTestResponse はpagePath × deviceCategory × sessions のピボットレポートのレスポンスを作成する
+func pivotTestResponse() *analyticsdata.RunPivotReportResponse {
+	header := func(values ...string) *analyticsdata.PivotDimensionHeader {
+		h := &analyticsdata.PivotDimensionHeader{}
+		for _, v := range values {
+			h.DimensionValues = append(h.DimensionValues, &analyticsdata.DimensionValue{Value: v})
+		}
+		return h
+	}
+	row := func(path, device, sessions string) *analyticsdata.Row {
+		return &analyticsdata.Row{
+			DimensionValues: []*analyticsdata.DimensionValue{{Value: path}, {Value: device}},
+			MetricValues:    []*analyticsdata.MetricValue{{Value: sessions}},
+		}
+	}
+
+	return &analyticsdata.RunPivotReportResponse{
+		DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: "pagePath"}, {Name: "deviceCategory"}},
+		MetricHeaders:    []*analyticsdata.MetricHeader{{Name: "sessions", Type: "TYPE_INTEGER"}},
+		PivotHeaders: []*analyticsdata.PivotHeader{
+			{PivotDimensionHeaders: []*analyticsdata.PivotDimensionHeader{header("/"), header("/blog")}, RowCount: 2},
+			{PivotDimensionHeaders: []*analyticsdata.PivotDimensionHeader{header("desktop"), header("mobile")}, RowCount: 2},
+		},
+		Rows: []*analyticsdata.Row{
+			row("/", "desktop", "10"),
+			row("/", "mobile", "20"),
+			row("/blog", "mobile", "5"),
+		},
+	}
+}
+
+func TestFlattenPivotResponse(t *testing.T) {
+	pivot := &PivotSpec{Rows: []string{"pagePath"}, Columns: []string{"deviceCategory"}}
+
+	result, err := flattenPivotResponse(pivot, pivotTestResponse())
+	if err != nil {
+		t.Fatalf("flattenPivotResponse() error = %v", err)
+	}
+
+	var headers []string
+	for _, h := range result.DimensionHeaders {
+		headers = append(headers, h.Name)
+	}
+	for _, h := range result.MetricHeaders {
+		headers = append(headers, h.Name)
+	}
+	if want := []string{"pagePath", "sessions (desktop)", "sessions (mobile)"}; !reflect.DeepEqual(headers, want) {
+		t.Errorf("headers = %v, want %v", headers, want)
+	}
+
+	var rows [][]string
+	for _, row := range result.Rows {
+		var values []string
+		for _, v := range row.DimensionValues {
+			values = append(values, v.Value)
+		}
+		for _, v := range row.MetricValues {
+			values = append(values, v.Value)
+		}
+		rows = append(rows, values)
+	}
+	// 値のない組み合わせ（/blog × desktop）は0になる
+	want := [][]string{{"/", "10", "20"}, {"/blog", "0", "5"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+
+	if len(result.PivotColumns) != 2 || result.PivotColumns[1].Metric != "sessions" || result.PivotColumns[1].Label() != "mobile" {
+		t.Errorf("PivotColumns = %+v", result.PivotColumns)
+	}
+}
+
+func TestGA4Client_runPivotReport(t *testing.T) {
+	var req analyticsdata.RunPivotReportRequest
+	var path string
+	client := newTestGA4Client(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(pivotTestResponse())
+	})
+
+	response, err := client.runChunkedReport(context.Background(), &GA4ReportRequest{
+		PropertyID: "123456789",
+		StreamID:   "1234567",
+		StartDate:  "2024-01-01",
+		EndDate:    "2024-01-31",
+		Dimensions: []string{"pagePath", "deviceCategory"},
+		Metrics:    []string{"sessions"},
+		Pivot:      &PivotSpec{Rows: []string{"pagePath"}, Columns: []string{"deviceCategory"}, RowLimit: 100, ColumnLimit: 5},
+	})
+	if err != nil {
+		t.Fatalf("runChunkedReport() error = %v", err)
+	}
+
+	if !strings.HasSuffix(path, ":runPivotReport") {
+		t.Errorf("リクエストパス = %s, want runPivotReport", path)
+	}
+	if len(req.Pivots) != 2 || req.Pivots[0].Limit != 100 || req.Pivots[1].Limit != 5 ||
+		!reflect.DeepEqual(req.Pivots[1].FieldNames, []string{"deviceCategory"}) {
+		t.Errorf("Pivots = %+v", req.Pivots)
+	}
+	if req.DimensionFilter == nil || req.DimensionFilter.Filter.FieldName != "streamId" {
+		t.Errorf("ストリームのフィルタが設定されていません: %+v", req.DimensionFilter)
+	}
+	if len(response.Rows) != 2 || len(response.MetricHeaders) != 2 {
+		t.Errorf("response = %d 行, %d 列", len(response.Rows), len(response.MetricHeaders))
+	}
+
+	schema := buildSchema(response)
+	if pc, ok := schema.PivotColumn("sessions (desktop)"); !ok || pc.Label() != "desktop" {
+		t.Errorf("schema.PivotColumn() = %+v, %v", pc, ok)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"github.com/ymotongpoo/ga/internal/errors"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// QuotaStatus は1種類のクォータの消費量と残量を表す構造体
+type QuotaStatus struct {
+	Consumed  int64 `json:"consumed"`
+	Remaining int64 `json:"remaining"`
+}
+
+// PropertyQuota はプロパティのクォータの状態を表す構造体
+// 直近のAPIレスポンス（ReturnPropertyQuota指定時）に含まれていた値を保持する
+type PropertyQuota struct {
+	TokensPerDay                          QuotaStatus `json:"tokens_per_day"`
+	TokensPerHour                         QuotaStatus `json:"tokens_per_hour"`
+	TokensPerProjectPerHour               QuotaStatus `json:"tokens_per_project_per_hour"`
+	ConcurrentRequests                    QuotaStatus `json:"concurrent_requests"`
+	ServerErrorsPerProjectPerHour         QuotaStatus `json:"server_errors_per_project_per_hour"`
+	PotentiallyThresholdedRequestsPerHour QuotaStatus `json:"potentially_thresholded_requests_per_hour"`
+}
+
+// quotaSlowdownDelay はクォータ残量が少ない場合にAPI呼び出しの前に待機する時間の既定値
+const quotaSlowdownDelay = 2 * time.Second
+
+// quotaExhaustedKey はクォータ残量による中止を表すGAErrorのコンテキストキー
+const quotaExhaustedKey = "quota_exhausted"
+
+// isQuotaExhausted はエラーがクォータ残量による中止かどうかを判定する
+func isQuotaExhausted(err error) bool {
+	gaErr, ok := err.(*errors.GAError)
+	return ok && gaErr.Context[quotaExhaustedKey] == true
+}
+
+// newPropertyQuota はAPIレスポンスのクォータ情報をPropertyQuotaに変換する
+func newPropertyQuota(quota *analyticsdata.PropertyQuota) *PropertyQuota {
+	status := func(s *analyticsdata.QuotaStatus) QuotaStatus {
+		if s == nil {
+			return QuotaStatus{}
+		}
+		return QuotaStatus{Consumed: s.Consumed, Remaining: s.Remaining}
+	}
+
+	return &PropertyQuota{
+		TokensPerDay:                          status(quota.TokensPerDay),
+		TokensPerHour:                         status(quota.TokensPerHour),
+		TokensPerProjectPerHour:               status(quota.TokensPerProjectPerHour),
+		ConcurrentRequests:                    status(quota.ConcurrentRequests),
+		ServerErrorsPerProjectPerHour:         status(quota.ServerErrorsPerProjectPerHour),
+		PotentiallyThresholdedRequestsPerHour: status(quota.PotentiallyThresholdedRequestsPerHour),
+	}
+}
+
+// ratio は残量の割合（0〜1）を返す。上限が分からない場合はfalseを返す
+func (s QuotaStatus) ratio() (float64, bool) {
+	total := s.Consumed + s.Remaining
+	if total <= 0 {
+		return 0, false
+	}
+	return float64(s.Remaining) / float64(total), true
+}
+
+// RemainingRatio はトークンのクォータ（1日あたり・1時間あたり・プロジェクトの1時間あたり）の
+// 残量の割合（0〜1）のうち最も小さいものを返す。いずれも不明な場合は1を返す
+func (q *PropertyQuota) RemainingRatio() float64 {
+	lowest := 1.0
+	for _, status := range []QuotaStatus{q.TokensPerDay, q.TokensPerHour, q.TokensPerProjectPerHour} {
+		if ratio, ok := status.ratio(); ok {
+			lowest = math.Min(lowest, ratio)
+		}
+	}
+	return lowest
+}
+
+// String はクォータの状態をサマリー表示用の文字列にする（残量/上限）
+func (q *PropertyQuota) String() string {
+	format := func(s QuotaStatus) string {
+		return fmt.Sprintf("%d/%d", s.Remaining, s.Consumed+s.Remaining)
+	}
+	return fmt.Sprintf("トークン/日 %s, トークン/時 %s, 同時リクエスト %s, サーバーエラー/時 %s",
+		format(q.TokensPerDay), format(q.TokensPerHour), format(q.ConcurrentRequests), format(q.ServerErrorsPerProjectPerHour))
+}
+
+// quotaTracker はプロパティごとの最新のクォータを記録し、残量が少ない場合にAPI呼び出しを抑制する
+// 複数のgoroutineから使用されるためmuで保護する。nilの場合は何もしない
+type quotaTracker struct {
+	mu            sync.Mutex
+	slowdownBelow float64       // 待機を始める残量の割合（0〜1）
+	stopBelow     float64       // 取得を中止する残量の割合（0〜1）
+	delay         time.Duration // 待機する時間
+	quotas        map[string]*PropertyQuota
+	warned        map[string]bool // 待機の警告を表示したプロパティ
+}
+
+// newQuotaTracker は設定からquotaTrackerを作成する
+// quota.disabled が指定されている場合はクォータの記録のみ行う
+func newQuotaTracker(cfg *config.Quota) *quotaTracker {
+	tracker := &quotaTracker{
+		delay:  quotaSlowdownDelay,
+		quotas: make(map[string]*PropertyQuota),
+		warned: make(map[string]bool),
+	}
+	if cfg == nil || !cfg.Disabled {
+		tracker.slowdownBelow = cfg.SlowdownBelowOrDefault() / 100
+		tracker.stopBelow = cfg.StopBelowOrDefault() / 100
+	}
+	return tracker
+}
+
+// record はAPIレスポンスに含まれていたプロパティのクォータを記録する
+func (t *quotaTracker) record(propertyID string, quota *analyticsdata.PropertyQuota) {
+	if t == nil || quota == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.quotas[propertyID] = newPropertyQuota(quota)
+}
+
+// wait はプロパティのクォータ残量を確認し、少ない場合はAPI呼び出しの前に待機する
+// 残量が中止の閾値を下回っている場合はAPIエラーを返す
+func (t *quotaTracker) wait(ctx context.Context, propertyID string) error {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	quota, ok := t.quotas[propertyID]
+	var ratio float64
+	if ok {
+		ratio = quota.RemainingRatio()
+	}
+	warn := ok && ratio < t.slowdownBelow && !t.warned[propertyID]
+	if warn {
+		t.warned[propertyID] = true
+	}
+	t.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	if ratio < t.stopBelow {
+		return errors.NewAPIError(
+			fmt.Sprintf("プロパティ %s のクォータ残量が少ないため取得を中止しました（残り %.1f%%）", propertyID, ratio*100), nil,
+		).WithContext("property_id", propertyID).WithContext(quotaExhaustedKey, true)
+	}
+
+	if ratio < t.slowdownBelow {
+		if warn {
+			fmt.Printf("⚠️  プロパティ %s: クォータ残量が %.1f%% のため、API呼び出しの間隔を %v 空けます\n", propertyID, ratio*100, t.delay)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(t.delay):
+		}
+	}
+
+	return nil
+}
+
+// snapshot は記録済みのクォータのコピーを返す
+func (t *quotaTracker) snapshot() map[string]*PropertyQuota {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	quotas := make(map[string]*PropertyQuota, len(t.quotas))
+	for propertyID, quota := range t.quotas {
+		copied := *quota
+		quotas[propertyID] = &copied
+	}
+	return quotas
+}
+
+// printQuotaSummary はプロパティごとのクォータ残量をサマリーとして表示する
+func printQuotaSummary(quotas map[string]*PropertyQuota) {
+	propertyIDs := make([]string, 0, len(quotas))
+	for propertyID := range quotas {
+		propertyIDs = append(propertyIDs, propertyID)
+	}
+	sort.Strings(propertyIDs)
+
+	for _, propertyID := range propertyIDs {
+		fmt.Printf("   - クォータ残量 (プロパティ %s): %s\n", propertyID, quotas[propertyID])
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"github.com/ymotongpoo/ga/internal/errors"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// testPropertyQuota は1時間あたりのトークン残量を指定したクォータ情報を返す
+func testPropertyQuota(remainingPerHour int64) *analyticsdata.PropertyQuota {
+	return &analyticsdata.PropertyQuota{
+		TokensPerDay:                  &analyticsdata.QuotaStatus{Consumed: 1000, Remaining: 199000},
+		TokensPerHour:                 &analyticsdata.QuotaStatus{Consumed: 40000 - remainingPerHour, Remaining: remainingPerHour},
+		ConcurrentRequests:            &analyticsdata.QuotaStatus{Consumed: 0, Remaining: 10},
+		ServerErrorsPerProjectPerHour: &analyticsdata.QuotaStatus{Consumed: 0, Remaining: 10},
+	}
+}
+
+func TestPropertyQuota_RemainingRatio(t *testing.T) {
+	tests := []struct {
+		name  string
+		quota *PropertyQuota
+		want  float64
+	}{
+		{name: "不明", quota: &PropertyQuota{}, want: 1},
+		{name: "1時間あたりが最も少ない", quota: newPropertyQuota(testPropertyQuota(4000)), want: 0.1},
+		{
+			name: "プロジェクトの1時間あたりが最も少ない",
+			quota: &PropertyQuota{
+				TokensPerHour:           QuotaStatus{Consumed: 10, Remaining: 90},
+				TokensPerProjectPerHour: QuotaStatus{Consumed: 75, Remaining: 25},
+			},
+			want: 0.25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quota.RemainingRatio(); got != tt.want {
+				t.Errorf("RemainingRatio() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuotaTracker_Wait(t *testing.T) {
+	ctx := context.Background()
+
+	var nilTracker *quotaTracker
+	if err := nilTracker.wait(ctx, "123"); err != nil {
+		t.Errorf("nilのtrackerでwait() error = %v", err)
+	}
+
+	tracker := newQuotaTracker(nil)
+	tracker.delay = time.Millisecond
+
+	// 記録がない場合は待機しない
+	if err := tracker.wait(ctx, "123"); err != nil {
+		t.Errorf("記録なしでwait() error = %v", err)
+	}
+
+	// 残量10%（待機の閾値20%未満、中止の閾値5%以上）は待機のみ
+	tracker.record("123", testPropertyQuota(4000))
+	if err := tracker.wait(ctx, "123"); err != nil {
+		t.Errorf("残量10%%でwait() error = %v", err)
+	}
+
+	// 残量2.5%は中止
+	tracker.record("123", testPropertyQuota(1000))
+	err := tracker.wait(ctx, "123")
+	gaErr, ok := err.(*errors.GAError)
+	if !ok || gaErr.Type != errors.APIError {
+		t.Fatalf("残量2.5%%でwait() error = %v, want APIError", err)
+	}
+	if !strings.Contains(gaErr.Message, "クォータ残量が少ないため取得を中止しました") {
+		t.Errorf("エラーメッセージ = %q", gaErr.Message)
+	}
+
+	// 他のプロパティには影響しない
+	if err := tracker.wait(ctx, "456"); err != nil {
+		t.Errorf("別プロパティでwait() error = %v", err)
+	}
+
+	// disabled の場合は記録のみ行う
+	disabled := newQuotaTracker(&config.Quota{Disabled: true})
+	disabled.record("123", testPropertyQuota(0))
+	if err := disabled.wait(ctx, "123"); err != nil {
+		t.Errorf("disabledでwait() error = %v", err)
+	}
+}
+
+func TestGA4Client_runReport_CollectsPropertyQuota(t *testing.T) {
+	var calls []string
+	client := newTestGA4Client(t, reportHandler(t, &calls, func(req *analyticsdata.RunReportRequest) (*analyticsdata.RunReportResponse, int) {
+		if !req.ReturnPropertyQuota {
+			t.Error("ReturnPropertyQuota が指定されていません")
+		}
+		report := singleRowReport("/", "1")
+		report.PropertyQuota = testPropertyQuota(39000)
+		return report, http.StatusOK
+	}))
+	client.quota = newQuotaTracker(nil)
+
+	request := &GA4ReportRequest{PropertyID: "123456789", StreamID: "1", StartDate: "2024-01-01", EndDate: "2024-01-31", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}}
+	if _, err := client.runReport(context.Background(), request); err != nil {
+		t.Fatalf("runReport() error = %v", err)
+	}
+
+	quotas := client.quota.snapshot()
+	want := &PropertyQuota{
+		TokensPerDay:                  QuotaStatus{Consumed: 1000, Remaining: 199000},
+		TokensPerHour:                 QuotaStatus{Consumed: 1000, Remaining: 39000},
+		ConcurrentRequests:            QuotaStatus{Consumed: 0, Remaining: 10},
+		ServerErrorsPerProjectPerHour: QuotaStatus{Consumed: 0, Remaining: 10},
+	}
+	if got := quotas["123456789"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("記録されたクォータ = %+v, want %+v", got, want)
+	}
+}
+
+func TestGA4Client_runBatch_StopsWhenQuotaExhausted(t *testing.T) {
+	var calls []string
+	client := newTestGA4Client(t, reportHandler(t, &calls, func(req *analyticsdata.RunReportRequest) (*analyticsdata.RunReportResponse, int) {
+		report := singleRowReport("/", "1")
+		report.PropertyQuota = testPropertyQuota(1000)
+		return report, http.StatusOK
+	}))
+	client.quota = newQuotaTracker(nil)
+
+	batch := []*GA4ReportRequest{
+		{PropertyID: "123456789", StreamID: "1", StartDate: "2024-01-01", EndDate: "2024-01-31", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}},
+		{PropertyID: "123456789", StreamID: "2", StartDate: "2024-01-01", EndDate: "2024-01-31", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}},
+	}
+
+	// 1回目は残量が分からないため取得し、レスポンスのクォータを記録する
+	for i, result := range client.runBatch(context.Background(), batch) {
+		if result.err != nil {
+			t.Fatalf("1回目 results[%d].err = %v", i, result.err)
+		}
+	}
+
+	// 2回目は残量2.5%のためAPIを呼び出さずに中止する
+	results := client.runBatch(context.Background(), batch)
+	for i, result := range results {
+		if gaErr, ok := result.err.(*errors.GAError); !ok || gaErr.Type != errors.APIError {
+			t.Errorf("2回目 results[%d].err = %v, want APIError", i, result.err)
+		}
+	}
+	if want := []string{"batchRunReports"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("API呼び出し = %v, want %v", calls, want)
+	}
+}
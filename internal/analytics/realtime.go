@@ -0,0 +1,222 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// GA4RealtimeRequest はリアルタイムレポートのリクエストを表す構造体
+type GA4RealtimeRequest struct {
+	PropertyID   string
+	StreamIDs    []string // 絞り込むデータストリーム（空の場合はプロパティ全体）
+	Dimensions   []string
+	Metrics      []string
+	MinuteRanges []config.MinuteRange
+	Limit        int64
+}
+
+// GetRealtimeData はrealtime 設定に基づいて各プロパティのリアルタイムレポートを取得する
+func (a *AnalyticsServiceImpl) GetRealtimeData(ctx context.Context, config *config.Config) (*ReportData, error) {
+	if a.client == nil {
+		return nil, fmt.Errorf("GA4クライアントが初期化されていません")
+	}
+	if config.Realtime == nil {
+		return nil, fmt.Errorf("設定ファイルに realtime が指定されていません")
+	}
+
+	requests := buildRealtimeRequests(config)
+
+	data := &ReportData{
+		StreamURLs: make(map[string]string),
+		Summary: ReportSummary{
+			DateRange: describeMinuteRanges(config.Realtime.MinuteRanges),
+		},
+	}
+
+	for _, request := range requests {
+		response, err := a.client.runRealtimeReport(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("プロパティ %s のリアルタイムデータ取得に失敗しました: %w", request.PropertyID, err)
+		}
+
+		// 初回のみヘッダーを設定
+		if len(data.Headers) == 0 {
+			data.Headers, data.Schema = buildRealtimeHeaders(response)
+		}
+
+		for _, row := range response.Rows {
+			values := []string{request.PropertyID}
+			for _, dimValue := range row.DimensionValues {
+				values = append(values, dimValue.Value)
+			}
+			for _, metricValue := range row.MetricValues {
+				values = append(values, metricValue.Value)
+			}
+			data.Rows = append(data.Rows, values)
+		}
+
+		data.Summary.Properties = append(data.Summary.Properties, request.PropertyID)
+		data.Summary.TotalRows += len(response.Rows)
+	}
+
+	data.Quotas = a.client.quota.snapshot()
+
+	return data, nil
+}
+
+// buildRealtimeRequests は設定からプロパティごとのリアルタイムレポートリクエストを構築する
+func buildRealtimeRequests(config *config.Config) []*GA4RealtimeRequest {
+	rt := config.Realtime
+
+	var requests []*GA4RealtimeRequest
+	for _, property := range config.Properties {
+		request := &GA4RealtimeRequest{
+			PropertyID:   property.ID,
+			Dimensions:   rt.Dimensions,
+			Metrics:      rt.Metrics,
+			MinuteRanges: rt.MinuteRanges,
+			Limit:        int64(rt.Limit),
+		}
+		if !rt.PropertyWide {
+			for _, stream := range property.Streams {
+				request.StreamIDs = append(request.StreamIDs, stream.ID)
+			}
+		}
+		requests = append(requests, request)
+	}
+
+	return requests
+}
+
+// runRealtimeReport はGA4 APIのリアルタイムレポートを実行する（リトライ機能付き）
+func (c *GA4Client) runRealtimeReport(ctx context.Context, request *GA4RealtimeRequest) (*GA4ReportResponse, error) {
+	var response *analyticsdata.RunRealtimeReportResponse
+	operation := fmt.Sprintf("プロパティ %s のリアルタイムレポート取得", request.PropertyID)
+	err := c.withRetry(ctx, operation, func() error {
+		release, err := c.beginCall(ctx, request.PropertyID)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		propertyPath := fmt.Sprintf("properties/%s", request.PropertyID)
+		response, err = c.service.Properties.RunRealtimeReport(propertyPath, buildRunRealtimeReportRequest(request)).Context(ctx).Do()
+		if err == nil {
+			c.quota.record(request.PropertyID, response.PropertyQuota)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &GA4ReportResponse{
+		DimensionHeaders: response.DimensionHeaders,
+		MetricHeaders:    response.MetricHeaders,
+		Rows:             response.Rows,
+		RowCount:         response.RowCount,
+	}, nil
+}
+
+// buildRunRealtimeReportRequest はGA4RealtimeRequestからAPIリクエストを構築する
+func buildRunRealtimeReportRequest(request *GA4RealtimeRequest) *analyticsdata.RunRealtimeReportRequest {
+	var dimensions []*analyticsdata.Dimension
+	for _, dim := range request.Dimensions {
+		dimensions = append(dimensions, &analyticsdata.Dimension{Name: dim})
+	}
+
+	var metrics []*analyticsdata.Metric
+	for _, metric := range request.Metrics {
+		metrics = append(metrics, &analyticsdata.Metric{Name: metric})
+	}
+
+	// 0分前（現在）も送信されるようにForceSendFieldsを指定する
+	var minuteRanges []*analyticsdata.MinuteRange
+	for _, mr := range request.MinuteRanges {
+		minuteRanges = append(minuteRanges, &analyticsdata.MinuteRange{
+			Name:            mr.Name,
+			StartMinutesAgo: int64(mr.StartMinutesAgo),
+			EndMinutesAgo:   int64(mr.EndMinutesAgo),
+			ForceSendFields: []string{"StartMinutesAgo", "EndMinutesAgo"},
+		})
+	}
+
+	return &analyticsdata.RunRealtimeReportRequest{
+		Dimensions:      dimensions,
+		Metrics:         metrics,
+		MinuteRanges:    minuteRanges,
+		DimensionFilter: realtimeStreamFilter(request.StreamIDs),
+		Limit:           request.Limit,
+
+		ReturnPropertyQuota: true,
+	}
+}
+
+// realtimeStreamFilter はリアルタイムレポートを設定済みのデータストリームに限定するフィルタを返す
+// ストリームが指定されていない場合はnilを返す
+func realtimeStreamFilter(streamIDs []string) *analyticsdata.FilterExpression {
+	if len(streamIDs) == 0 {
+		return nil
+	}
+
+	return &analyticsdata.FilterExpression{
+		Filter: &analyticsdata.Filter{
+			FieldName:    "streamId",
+			InListFilter: &analyticsdata.InListFilter{Values: streamIDs},
+		},
+	}
+}
+
+// buildRealtimeHeaders はリアルタイムレポートのレスポンスから出力列とスキーマを構築する
+// リアルタイムレポートはプロパティ単位で取得するため stream_id 列は含めない
+func buildRealtimeHeaders(response *GA4ReportResponse) ([]string, *Schema) {
+	headers := []string{"property_id"}
+	schema := &Schema{Dimensions: []string{"property_id"}}
+
+	for _, dimHeader := range response.DimensionHeaders {
+		headers = append(headers, dimHeader.Name)
+		schema.Dimensions = append(schema.Dimensions, dimHeader.Name)
+	}
+	for _, metricHeader := range response.MetricHeaders {
+		headers = append(headers, metricHeader.Name)
+		schema.Metrics = append(schema.Metrics, metricHeader.Name)
+	}
+	schema.MetricTypes = metricTypes(response.MetricHeaders)
+
+	return headers, schema
+}
+
+// describeMinuteRanges は分範囲をサマリー表示用の文字列にする
+func describeMinuteRanges(ranges []config.MinuteRange) string {
+	if len(ranges) == 0 {
+		return "直近30分"
+	}
+
+	var parts []string
+	for _, mr := range ranges {
+		part := fmt.Sprintf("%d-%d分前", mr.StartMinutesAgo, mr.EndMinutesAgo)
+		if mr.Name != "" {
+			part = fmt.Sprintf("%s (%s)", mr.Name, part)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
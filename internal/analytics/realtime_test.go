@@ -0,0 +1,135 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+func TestBuildRunRealtimeReportRequest(t *testing.T) {
+	request := &GA4RealtimeRequest{
+		PropertyID:   "987654321",
+		StreamIDs:    []string{"111", "222"},
+		Dimensions:   []string{"country"},
+		Metrics:      []string{"activeUsers"},
+		MinuteRanges: []config.MinuteRange{{Name: "now", StartMinutesAgo: 4, EndMinutesAgo: 0}},
+		Limit:        10,
+	}
+
+	got := buildRunRealtimeReportRequest(request)
+
+	// end_minutes_ago が0でも送信されること
+	body, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(body), `"endMinutesAgo":0`) {
+		t.Errorf("endMinutesAgo が送信されていません: %s", body)
+	}
+
+	filter := got.DimensionFilter.Filter
+	if filter.FieldName != "streamId" || !reflect.DeepEqual(filter.InListFilter.Values, []string{"111", "222"}) {
+		t.Errorf("DimensionFilter = %+v, want streamId in [111 222]", filter)
+	}
+	if got.Limit != 10 {
+		t.Errorf("Limit = %d, want 10", got.Limit)
+	}
+}
+
+func TestBuildRealtimeRequests_PropertyWide(t *testing.T) {
+	cfg := &config.Config{
+		Properties: []config.Property{{ID: "987654321", Streams: []config.Stream{{ID: "111"}}}},
+		Realtime:   &config.Realtime{Metrics: []string{"activeUsers"}, PropertyWide: true},
+	}
+
+	requests := buildRealtimeRequests(cfg)
+	if len(requests) != 1 {
+		t.Fatalf("len(requests) = %d, want 1", len(requests))
+	}
+	if len(requests[0].StreamIDs) != 0 {
+		t.Errorf("property_wide なのにストリームで絞り込まれています: %v", requests[0].StreamIDs)
+	}
+	if buildRunRealtimeReportRequest(requests[0]).DimensionFilter != nil {
+		t.Error("property_wide の場合はフィルタを設定しないでください")
+	}
+}
+
+func TestAnalyticsServiceImpl_GetRealtimeData(t *testing.T) {
+	var paths []string
+	client := newTestGA4Client(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+
+		var req analyticsdata.RunRealtimeReportRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("リクエストのデコードに失敗: %v", err)
+		}
+		if len(req.MinuteRanges) != 1 || req.MinuteRanges[0].StartMinutesAgo != 4 {
+			t.Errorf("MinuteRanges = %+v, want 4-0", req.MinuteRanges)
+		}
+
+		json.NewEncoder(w).Encode(&analyticsdata.RunRealtimeReportResponse{
+			DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: "country"}},
+			MetricHeaders:    []*analyticsdata.MetricHeader{{Name: "activeUsers", Type: "TYPE_INTEGER"}},
+			Rows: []*analyticsdata.Row{{
+				DimensionValues: []*analyticsdata.DimensionValue{{Value: "Japan"}},
+				MetricValues:    []*analyticsdata.MetricValue{{Value: "42"}},
+			}},
+			RowCount: 1,
+		})
+	})
+	service := &AnalyticsServiceImpl{client: client}
+
+	cfg := &config.Config{
+		Properties: []config.Property{
+			{ID: "111111111", Streams: []config.Stream{{ID: "1"}}},
+			{ID: "222222222", Streams: []config.Stream{{ID: "2"}}},
+		},
+		Realtime: &config.Realtime{
+			Dimensions:   []string{"country"},
+			Metrics:      []string{"activeUsers"},
+			MinuteRanges: []config.MinuteRange{{StartMinutesAgo: 4, EndMinutesAgo: 0}},
+		},
+	}
+
+	data, err := service.GetRealtimeData(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("GetRealtimeData() error = %v", err)
+	}
+
+	if len(paths) != 2 || !strings.HasSuffix(paths[0], ":runRealtimeReport") {
+		t.Errorf("リクエストパス = %v, want 2 runRealtimeReport calls", paths)
+	}
+	if want := []string{"property_id", "country", "activeUsers"}; !reflect.DeepEqual(data.Headers, want) {
+		t.Errorf("Headers = %v, want %v", data.Headers, want)
+	}
+	want := [][]string{{"111111111", "Japan", "42"}, {"222222222", "Japan", "42"}}
+	if !reflect.DeepEqual(data.Rows, want) {
+		t.Errorf("Rows = %v, want %v", data.Rows, want)
+	}
+	if data.Summary.DateRange != "4-0分前" {
+		t.Errorf("Summary.DateRange = %q, want %q", data.Summary.DateRange, "4-0分前")
+	}
+	if !data.Schema.IsMetric("activeUsers") || !data.Schema.IsDimension("country") {
+		t.Errorf("Schema = %+v", data.Schema)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ymotongpoo/ga/internal/errors"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// SamplingMetadata はサンプリングされたレポートの標本数を表す構造体
+type SamplingMetadata struct {
+	SamplesReadCount  int64 `json:"samples_read_count"`  // 集計に使用したイベント数
+	SamplingSpaceSize int64 `json:"sampling_space_size"` // 期間内の全イベント数
+}
+
+// ResponseMetadata はレポートのレスポンスに含まれるデータの精度に関する情報を表す構造体
+// プロパティ・ストリームごとに、全ページ・全期間のレスポンスをまとめて保持する
+type ResponseMetadata struct {
+	PropertyID            string             `json:"property_id"`
+	StreamID              string             `json:"stream_id,omitempty"`
+	CurrencyCode          string             `json:"currency_code,omitempty"`
+	TimeZone              string             `json:"time_zone,omitempty"`
+	SubjectToThresholding bool               `json:"subject_to_thresholding"`  // しきい値により一部の行が除外されている可能性がある
+	DataLossFromOtherRow  bool               `json:"data_loss_from_other_row"` // 行数の上限を超え、一部の行が (other) にまとめられた
+	Sampling              []SamplingMetadata `json:"sampling,omitempty"`       // 期間ごとのサンプリング情報（サンプリングされていない場合は空）
+}
+
+// newResponseMetadata はAPIレスポンスのメタデータをResponseMetadataに変換する
+// メタデータがない場合はnilを返す
+func newResponseMetadata(metadata *analyticsdata.ResponseMetaData) *ResponseMetadata {
+	if metadata == nil {
+		return nil
+	}
+
+	result := &ResponseMetadata{
+		CurrencyCode:          metadata.CurrencyCode,
+		TimeZone:              metadata.TimeZone,
+		SubjectToThresholding: metadata.SubjectToThresholding,
+		DataLossFromOtherRow:  metadata.DataLossFromOtherRow,
+	}
+	for _, sampling := range metadata.SamplingMetadatas {
+		if sampling == nil {
+			continue
+		}
+		result.Sampling = append(result.Sampling, SamplingMetadata{
+			SamplesReadCount:  sampling.SamplesReadCount,
+			SamplingSpaceSize: sampling.SamplingSpaceSize,
+		})
+	}
+	return result
+}
+
+// mergeResponseMetadata は複数のレスポンス（期間やチャンク）のメタデータを1つにまとめる
+// いずれかのレスポンスがしきい値やサンプリングの対象であれば、まとめた結果も対象とする
+func mergeResponseMetadata(a, b *ResponseMetadata) *ResponseMetadata {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+
+	merged := *a
+	if merged.CurrencyCode == "" {
+		merged.CurrencyCode = b.CurrencyCode
+	}
+	if merged.TimeZone == "" {
+		merged.TimeZone = b.TimeZone
+	}
+	merged.SubjectToThresholding = a.SubjectToThresholding || b.SubjectToThresholding
+	merged.DataLossFromOtherRow = a.DataLossFromOtherRow || b.DataLossFromOtherRow
+	merged.Sampling = append(append([]SamplingMetadata(nil), a.Sampling...), b.Sampling...)
+	return &merged
+}
+
+// mergePageMetadata は同じリクエストの後続ページのメタデータをまとめる
+// サンプリング情報は全ページで同じ期間を表すため、重複させずに先頭ページのものを使用する
+func mergePageMetadata(a, b *ResponseMetadata) *ResponseMetadata {
+	merged := mergeResponseMetadata(a, b)
+	if a != nil && b != nil && len(a.Sampling) > 0 {
+		merged.Sampling = a.Sampling
+	}
+	return merged
+}
+
+// IsSampled はレポートがサンプリングされたデータかどうかを返す
+func (m *ResponseMetadata) IsSampled() bool {
+	return m != nil && len(m.Sampling) > 0
+}
+
+// IsExact はサンプリングもしきい値も適用されていない正確なデータかどうかを返す
+func (m *ResponseMetadata) IsExact() bool {
+	return m == nil || (!m.IsSampled() && !m.SubjectToThresholding)
+}
+
+// SamplingRate は標本の割合（0〜1）を返す。サンプリングされていない場合は1を返す
+func (m *ResponseMetadata) SamplingRate() float64 {
+	var read, space int64
+	if m != nil {
+		for _, sampling := range m.Sampling {
+			read += sampling.SamplesReadCount
+			space += sampling.SamplingSpaceSize
+		}
+	}
+	if space == 0 {
+		return 1
+	}
+	return float64(read) / float64(space)
+}
+
+// Warnings はデータの精度に関する警告メッセージを返す
+func (m *ResponseMetadata) Warnings() []string {
+	if m == nil {
+		return nil
+	}
+
+	var warnings []string
+	if m.IsSampled() {
+		warnings = append(warnings, fmt.Sprintf("サンプリングされたデータです（標本の割合 %.1f%%）", m.SamplingRate()*100))
+	}
+	if m.SubjectToThresholding {
+		warnings = append(warnings, "しきい値が適用され、一部の行が除外されている可能性があります")
+	}
+	if m.DataLossFromOtherRow {
+		warnings = append(warnings, "行数の上限を超えたため、一部の行が (other) にまとめられています")
+	}
+	return warnings
+}
+
+// MetadataFor はプロパティ・ストリームのレスポンスのメタデータを返す
+// 該当するメタデータがない場合はnilを返す
+func (d *ReportData) MetadataFor(propertyID, streamID string) *ResponseMetadata {
+	for _, metadata := range d.Metadata {
+		if metadata.PropertyID == propertyID && metadata.StreamID == streamID {
+			return metadata
+		}
+	}
+	return nil
+}
+
+// printMetadataWarnings はデータの精度に関する警告をサマリーとして表示する
+func printMetadataWarnings(metadata []*ResponseMetadata) {
+	for _, m := range metadata {
+		for _, warning := range m.Warnings() {
+			fmt.Printf("   - ⚠️  プロパティ %s, ストリーム %s: %s\n", m.PropertyID, m.StreamID, warning)
+		}
+	}
+}
+
+// checkStrict はサンプリングまたはしきい値が適用されたレスポンスがある場合にエラーを返す
+// strict 指定時に、正確でない数値を出力しないために使用する
+func checkStrict(metadata []*ResponseMetadata) error {
+	var inexact []string
+	for _, m := range metadata {
+		if !m.IsExact() {
+			inexact = append(inexact, fmt.Sprintf("プロパティ %s, ストリーム %s", m.PropertyID, m.StreamID))
+		}
+	}
+	if len(inexact) == 0 {
+		return nil
+	}
+
+	return errors.NewValidationError(
+		fmt.Sprintf("strict モードのため中止しました: サンプリングまたはしきい値が適用されたデータが含まれています（%s）", strings.Join(inexact, "; ")),
+		nil,
+	)
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ymotongpoo/ga/internal/errors"
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+func TestNewResponseMetadata(t *testing.T) {
+	if got := newResponseMetadata(nil); got != nil {
+		t.Errorf("newResponseMetadata(nil) = %+v, want nil", got)
+	}
+
+	got := newResponseMetadata(&analyticsdata.ResponseMetaData{
+		CurrencyCode:          "JPY",
+		TimeZone:              "Asia/Tokyo",
+		SubjectToThresholding: true,
+		SamplingMetadatas: []*analyticsdata.SamplingMetadata{
+			{SamplesReadCount: 250, SamplingSpaceSize: 1000},
+		},
+	})
+	if got.CurrencyCode != "JPY" || got.TimeZone != "Asia/Tokyo" || !got.SubjectToThresholding || got.DataLossFromOtherRow {
+		t.Errorf("newResponseMetadata() = %+v", got)
+	}
+	if !got.IsSampled() || got.SamplingRate() != 0.25 {
+		t.Errorf("IsSampled() = %v, SamplingRate() = %g, want true, 0.25", got.IsSampled(), got.SamplingRate())
+	}
+}
+
+func TestMergeResponseMetadata(t *testing.T) {
+	first := &ResponseMetadata{CurrencyCode: "JPY", TimeZone: "Asia/Tokyo"}
+	second := &ResponseMetadata{
+		CurrencyCode:         "USD",
+		DataLossFromOtherRow: true,
+		Sampling:             []SamplingMetadata{{SamplesReadCount: 1, SamplingSpaceSize: 2}},
+	}
+
+	merged := mergeResponseMetadata(first, second)
+	if merged.CurrencyCode != "JPY" || merged.TimeZone != "Asia/Tokyo" {
+		t.Errorf("先頭のレスポンスの通貨・タイムゾーンを使用する必要があります: %+v", merged)
+	}
+	if !merged.DataLossFromOtherRow || !merged.IsSampled() {
+		t.Errorf("いずれかのレスポンスの警告を引き継ぐ必要があります: %+v", merged)
+	}
+	if first.DataLossFromOtherRow || first.IsSampled() {
+		t.Errorf("元のメタデータを変更してはいけません: %+v", first)
+	}
+
+	if got := mergeResponseMetadata(nil, second); got != second {
+		t.Errorf("mergeResponseMetadata(nil, b) = %+v, want b", got)
+	}
+	if got := mergeResponseMetadata(first, nil); got != first {
+		t.Errorf("mergeResponseMetadata(a, nil) = %+v, want a", got)
+	}
+}
+
+func TestMergePageMetadata(t *testing.T) {
+	page := func(thresholding bool) *ResponseMetadata {
+		return &ResponseMetadata{
+			SubjectToThresholding: thresholding,
+			Sampling:              []SamplingMetadata{{SamplesReadCount: 250, SamplingSpaceSize: 1000}},
+		}
+	}
+
+	merged := page(false)
+	for _, next := range []*ResponseMetadata{page(false), page(true)} {
+		merged = mergePageMetadata(merged, next)
+	}
+	if len(merged.Sampling) != 1 || merged.SamplingRate() != 0.25 {
+		t.Errorf("ページごとにサンプリング情報を重複させてはいけません: %+v", merged.Sampling)
+	}
+	if !merged.SubjectToThresholding {
+		t.Errorf("後続ページの警告を引き継ぐ必要があります: %+v", merged)
+	}
+
+	unsampled := &ResponseMetadata{}
+	if got := mergePageMetadata(unsampled, page(false)); len(got.Sampling) != 1 {
+		t.Errorf("先頭ページにサンプリング情報がない場合は後続ページのものを使用する必要があります: %+v", got.Sampling)
+	}
+}
+
+func TestResponseMetadata_Warnings(t *testing.T) {
+	var nilMetadata *ResponseMetadata
+	if got := nilMetadata.Warnings(); len(got) != 0 || !nilMetadata.IsExact() {
+		t.Errorf("nilのメタデータ: Warnings() = %v, IsExact() = %v", got, nilMetadata.IsExact())
+	}
+
+	exact := &ResponseMetadata{CurrencyCode: "JPY"}
+	if got := exact.Warnings(); len(got) != 0 || !exact.IsExact() {
+		t.Errorf("正確なデータ: Warnings() = %v, IsExact() = %v", got, exact.IsExact())
+	}
+
+	m := &ResponseMetadata{
+		SubjectToThresholding: true,
+		DataLossFromOtherRow:  true,
+		Sampling:              []SamplingMetadata{{SamplesReadCount: 1, SamplingSpaceSize: 8}},
+	}
+	warnings := strings.Join(m.Warnings(), "\n")
+	for _, want := range []string{"標本の割合 12.5%", "しきい値", "(other)"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("Warnings() = %q, want containing %q", warnings, want)
+		}
+	}
+	if m.IsExact() {
+		t.Error("IsExact() = true, want false")
+	}
+
+	// (other) への集約のみの場合は数値自体は正確
+	if !(&ResponseMetadata{DataLossFromOtherRow: true}).IsExact() {
+		t.Error("DataLossFromOtherRow のみの場合 IsExact() = false, want true")
+	}
+}
+
+func TestReportData_MetadataFor(t *testing.T) {
+	data := &ReportData{Metadata: []*ResponseMetadata{
+		{PropertyID: "111", StreamID: "1"},
+		{PropertyID: "111", StreamID: "2", SubjectToThresholding: true},
+	}}
+
+	if got := data.MetadataFor("111", "2"); got == nil || !got.SubjectToThresholding {
+		t.Errorf("MetadataFor(111, 2) = %+v", got)
+	}
+	if got := data.MetadataFor("222", "1"); got != nil {
+		t.Errorf("MetadataFor(222, 1) = %+v, want nil", got)
+	}
+}
+
+// metadataHandler は指定したプロパティへのリクエストにしきい値が適用されたレスポンスを返すテスト用ハンドラー
+func metadataHandler(thresholded string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response := singleRowReport("/", "1")
+		response.Metadata = &analyticsdata.ResponseMetaData{CurrencyCode: "JPY", TimeZone: "Asia/Tokyo"}
+		if strings.Contains(r.URL.Path, "properties/"+thresholded+":") {
+			response.Metadata.SubjectToThresholding = true
+		}
+		writeJSON(w, response)
+	}
+}
+
+func TestFetchDataConcurrently_ResponseMetadata(t *testing.T) {
+	service := &AnalyticsServiceImpl{client: newTestGA4Client(t, metadataHandler("222222222"))}
+
+	data, err := service.fetchDataConcurrently(context.Background(), testRequests("111111111", "222222222"), createTestConfig())
+	if err != nil {
+		t.Fatalf("fetchDataConcurrently() error = %v", err)
+	}
+
+	if len(data.Metadata) != 2 {
+		t.Fatalf("Metadata = %+v, want 2件", data.Metadata)
+	}
+	first, second := data.Metadata[0], data.Metadata[1]
+	if first.PropertyID != "111111111" || first.StreamID != "1" || first.CurrencyCode != "JPY" || first.SubjectToThresholding {
+		t.Errorf("Metadata[0] = %+v", first)
+	}
+	if second.PropertyID != "222222222" || !second.SubjectToThresholding {
+		t.Errorf("Metadata[1] = %+v", second)
+	}
+}
+
+func TestFetchDataConcurrently_Strict(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Strict = true
+
+	// しきい値が適用されたデータがある場合は失敗する
+	service := &AnalyticsServiceImpl{client: newTestGA4Client(t, metadataHandler("222222222"))}
+	_, err := service.fetchDataConcurrently(context.Background(), testRequests("111111111", "222222222"), cfg)
+	gaErr, ok := err.(*errors.GAError)
+	if !ok || gaErr.Type != errors.ValidationError || !strings.Contains(err.Error(), "プロパティ 222222222, ストリーム 1") {
+		t.Errorf("fetchDataConcurrently() error = %v, want strict error for property 222222222", err)
+	}
+
+	// 正確なデータのみの場合は成功する
+	service = &AnalyticsServiceImpl{client: newTestGA4Client(t, metadataHandler("999999999"))}
+	if _, err := service.fetchDataConcurrently(context.Background(), testRequests("111111111", "222222222"), cfg); err != nil {
+		t.Errorf("fetchDataConcurrently() error = %v", err)
+	}
+}
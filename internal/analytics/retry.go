@@ -0,0 +1,145 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	stderrors "errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"google.golang.org/api/googleapi"
+)
+
+// NewRetryConfig は設定ファイルのretry セクションからリトライ設定を作成する
+// 省略した項目はDefaultRetryConfigの値を使用する
+func NewRetryConfig(cfg *config.Retry) *RetryConfig {
+	retryConfig := *DefaultRetryConfig
+	retryConfig.RetryableErrors = append([]int(nil), DefaultRetryConfig.RetryableErrors...)
+	if cfg == nil {
+		return &retryConfig
+	}
+
+	if cfg.MaxRetries != nil {
+		retryConfig.MaxRetries = *cfg.MaxRetries
+	}
+	if cfg.BaseDelay > 0 {
+		retryConfig.BaseDelay = cfg.BaseDelay
+	}
+	if cfg.MaxDelay > 0 {
+		retryConfig.MaxDelay = cfg.MaxDelay
+	}
+	if cfg.BackoffFactor > 0 {
+		retryConfig.BackoffFactor = cfg.BackoffFactor
+	}
+	if cfg.Jitter != nil {
+		retryConfig.Jitter = *cfg.Jitter
+	}
+	if len(cfg.RetryableCodes) > 0 {
+		retryConfig.RetryableErrors = cfg.RetryableCodes
+	}
+	retryConfig.Budget = cfg.Budget
+
+	return &retryConfig
+}
+
+// retryDelay はattempt回目の失敗後に待機する時間を返す
+// サーバーが待機時間を指定した場合はその時間を、それ以外は指数バックオフにジッターを加えた時間を返す
+func (c *GA4Client) retryDelay(attempt int, err error) time.Duration {
+	if delay, ok := serverRetryDelay(err); ok {
+		return delay
+	}
+	return c.applyJitter(c.calculateBackoffDelay(attempt))
+}
+
+// applyJitter は待機時間を最大でJitterの割合だけランダムに短くする
+// 複数のワーカーが同時に429を受けた場合にリトライが同じ時刻に集中しないようにする
+func (c *GA4Client) applyJitter(delay time.Duration) time.Duration {
+	if c.retryConfig.Jitter <= 0 {
+		return delay
+	}
+	return time.Duration(float64(delay) * (1 - c.retryConfig.Jitter*rand.Float64()))
+}
+
+// serverRetryDelay はエラーレスポンスでサーバーが指定した待機時間を返す
+// Retry-Afterヘッダー（秒数またはHTTP日付）と google.rpc.RetryInfo の retryDelay に対応する
+func serverRetryDelay(err error) (time.Duration, bool) {
+	var apiErr *googleapi.Error
+	if !stderrors.As(err, &apiErr) {
+		return 0, false
+	}
+
+	if retryAfter := apiErr.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if at, err := http.ParseTime(retryAfter); err == nil {
+			return max(time.Until(at), 0), true
+		}
+	}
+
+	for _, detail := range apiErr.Details {
+		fields, ok := detail.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if typ, _ := fields["@type"].(string); !strings.HasSuffix(typ, "google.rpc.RetryInfo") {
+			continue
+		}
+		if value, ok := fields["retryDelay"].(string); ok {
+			if delay, err := time.ParseDuration(value); err == nil && delay >= 0 {
+				return delay, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// retryBudget は1回の実行でリトライの待機に使った合計時間を記録し、上限を超えないようにする
+// 複数のgoroutineから使用されるためmuで保護する。nilの場合は無制限
+type retryBudget struct {
+	mu    sync.Mutex
+	limit time.Duration
+	spent time.Duration
+}
+
+// newRetryBudget は上限を指定してretryBudgetを作成する。上限が0の場合はnil（無制限）を返す
+func newRetryBudget(limit time.Duration) *retryBudget {
+	if limit <= 0 {
+		return nil
+	}
+	return &retryBudget{limit: limit}
+}
+
+// spend は待機時間を記録する。上限を超える場合は記録せずにfalseを返す
+func (b *retryBudget) spend(delay time.Duration) bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.spent+delay > b.limit {
+		return false
+	}
+	b.spent += delay
+	return true
+}
@@ -0,0 +1,248 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"google.golang.org/api/googleapi"
+)
+
+func TestNewRetryConfig(t *testing.T) {
+	// 設定がない場合は既定値を使用する
+	got := NewRetryConfig(nil)
+	if got.MaxRetries != DefaultRetryConfig.MaxRetries || got.BaseDelay != DefaultRetryConfig.BaseDelay || got.Jitter != DefaultRetryConfig.Jitter {
+		t.Errorf("NewRetryConfig(nil) = %+v, want %+v", got, DefaultRetryConfig)
+	}
+	if got == DefaultRetryConfig {
+		t.Error("NewRetryConfig(nil) はDefaultRetryConfigのコピーを返す必要があります")
+	}
+
+	zero := 0
+	noJitter := 0.0
+	got = NewRetryConfig(&config.Retry{
+		MaxRetries:     &zero,
+		MaxDelay:       5 * time.Second,
+		Jitter:         &noJitter,
+		RetryableCodes: []int{503},
+		Budget:         time.Minute,
+	})
+	if got.MaxRetries != 0 {
+		t.Errorf("MaxRetries = %d, want 0", got.MaxRetries)
+	}
+	if got.BaseDelay != DefaultRetryConfig.BaseDelay {
+		t.Errorf("BaseDelay = %v, want default %v", got.BaseDelay, DefaultRetryConfig.BaseDelay)
+	}
+	if got.MaxDelay != 5*time.Second || got.Jitter != 0 || got.Budget != time.Minute {
+		t.Errorf("MaxDelay, Jitter, Budget = %v, %g, %v", got.MaxDelay, got.Jitter, got.Budget)
+	}
+	if len(got.RetryableErrors) != 1 || got.RetryableErrors[0] != 503 {
+		t.Errorf("RetryableErrors = %v, want [503]", got.RetryableErrors)
+	}
+}
+
+func TestServerRetryDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   time.Duration
+		wantOK bool
+	}{
+		{
+			name:   "Retry-After（秒数）",
+			err:    &googleapi.Error{Code: 429, Header: http.Header{"Retry-After": []string{"7"}}},
+			want:   7 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "Retry-After（過去の日付）",
+			err:    &googleapi.Error{Code: 503, Header: http.Header{"Retry-After": []string{"Wed, 21 Oct 2015 07:28:00 GMT"}}},
+			want:   0,
+			wantOK: true,
+		},
+		{
+			name: "RetryInfo",
+			err: &googleapi.Error{Code: 429, Details: []interface{}{
+				map[string]interface{}{"@type": "type.googleapis.com/google.rpc.ErrorInfo", "reason": "RATE_LIMIT_EXCEEDED"},
+				map[string]interface{}{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "2.5s"},
+			}},
+			want:   2500 * time.Millisecond,
+			wantOK: true,
+		},
+		{
+			name:   "ラップされたエラー",
+			err:    fmt.Errorf("wrapped: %w", &googleapi.Error{Code: 429, Header: http.Header{"Retry-After": []string{"3"}}}),
+			want:   3 * time.Second,
+			wantOK: true,
+		},
+		{
+			name: "指定なし",
+			err:  &googleapi.Error{Code: 500},
+		},
+		{
+			name: "不正なRetry-After",
+			err:  &googleapi.Error{Code: 429, Header: http.Header{"Retry-After": []string{"soon"}}},
+		},
+		{
+			name: "googleapiのエラーではない",
+			err:  fmt.Errorf("connection reset"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := serverRetryDelay(tt.err)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("serverRetryDelay() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestServerRetryDelay_FutureDate(t *testing.T) {
+	at := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	got, ok := serverRetryDelay(&googleapi.Error{Code: 503, Header: http.Header{"Retry-After": []string{at}}})
+	if !ok || got <= 5*time.Second || got > 10*time.Second {
+		t.Errorf("serverRetryDelay() = %v, %v, want about 10s", got, ok)
+	}
+}
+
+func TestGA4Client_applyJitter(t *testing.T) {
+	client := &GA4Client{retryConfig: &RetryConfig{Jitter: 0.5}}
+
+	base := time.Second
+	varied := false
+	for i := 0; i < 100; i++ {
+		got := client.applyJitter(base)
+		if got < base/2 || got > base {
+			t.Fatalf("applyJitter(%v) = %v, want between %v and %v", base, got, base/2, base)
+		}
+		if got != base {
+			varied = true
+		}
+	}
+	if !varied {
+		t.Error("applyJitter() が待機時間を変化させていません")
+	}
+
+	client.retryConfig.Jitter = 0
+	if got := client.applyJitter(base); got != base {
+		t.Errorf("Jitter 0 の applyJitter(%v) = %v, want %v", base, got, base)
+	}
+}
+
+func TestRetryBudget_Spend(t *testing.T) {
+	var unlimited *retryBudget
+	if !unlimited.spend(time.Hour) {
+		t.Error("nilのretryBudgetは常にtrueを返す必要があります")
+	}
+	if newRetryBudget(0) != nil {
+		t.Error("上限0のretryBudgetはnil（無制限）である必要があります")
+	}
+
+	budget := newRetryBudget(100 * time.Millisecond)
+	if !budget.spend(60 * time.Millisecond) {
+		t.Error("上限内の spend() = false, want true")
+	}
+	if budget.spend(60 * time.Millisecond) {
+		t.Error("上限を超える spend() = true, want false")
+	}
+	if !budget.spend(40 * time.Millisecond) {
+		t.Error("残りちょうどの spend() = false, want true")
+	}
+}
+
+func TestGA4Client_withRetry_HonorsRetryAfter(t *testing.T) {
+	var attempts int32
+	client := newTestGA4Client(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, `{"error":{"code":429,"message":"quota exceeded"}}`, http.StatusTooManyRequests)
+			return
+		}
+		writeJSON(w, singleRowReport("/", "1"))
+	})
+	client.retryConfig.MaxDelay = 5 * time.Second
+
+	request := &GA4ReportRequest{PropertyID: "123456789", StartDate: "2024-01-01", EndDate: "2024-01-31", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}}
+	started := time.Now()
+	if _, err := client.runReport(context.Background(), request); err != nil {
+		t.Fatalf("runReport() error = %v", err)
+	}
+
+	// バックオフ（1ms）ではなくサーバーが指定した1秒だけ待機する
+	if elapsed := time.Since(started); elapsed < time.Second {
+		t.Errorf("elapsed = %v, want at least 1s", elapsed)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestGA4Client_withRetry_StopsWhenBudgetExhausted(t *testing.T) {
+	var attempts int32
+	client := newTestGA4Client(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, `{"error":{"code":503,"message":"unavailable"}}`, http.StatusServiceUnavailable)
+	})
+	client.retryConfig.MaxRetries = 3
+	client.retryConfig.RetryableErrors = []int{503}
+	client.retryBudget = newRetryBudget(time.Second)
+
+	request := &GA4ReportRequest{PropertyID: "123456789", StartDate: "2024-01-01", EndDate: "2024-01-31", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}}
+	started := time.Now()
+	if _, err := client.runReport(context.Background(), request); err == nil {
+		t.Fatal("runReport() error = nil, want error")
+	}
+
+	// 60秒の待機は上限（1秒）を超えるため、待機せずに失敗する
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("elapsed = %v, want no wait", elapsed)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestGA4Client_withRetry_StopsWhenRetryAfterExceedsMaxDelay(t *testing.T) {
+	var attempts int32
+	client := newTestGA4Client(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Retry-After", "3600")
+		http.Error(w, `{"error":{"code":429,"message":"quota exceeded"}}`, http.StatusTooManyRequests)
+	})
+	client.retryConfig.MaxDelay = 30 * time.Second
+
+	request := &GA4ReportRequest{PropertyID: "123456789", StartDate: "2024-01-01", EndDate: "2024-01-31", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}}
+	started := time.Now()
+	if _, err := client.runReport(context.Background(), request); err == nil {
+		t.Fatal("runReport() error = nil, want error")
+	}
+
+	// 1時間の待機は max_delay（30秒）を超えるため、待機せずに失敗する
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("elapsed = %v, want no wait", elapsed)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import "strings"
+
+// Columns はスキーマの全列をディメンション、メトリクスの順に返す
+func (s *Schema) Columns() []string {
+	columns := make([]string, 0, len(s.Dimensions)+len(s.Metrics))
+	columns = append(columns, s.Dimensions...)
+	return append(columns, s.Metrics...)
+}
+
+// key はスキーマの列構成を識別する文字列を返す
+func (s *Schema) key() string {
+	return strings.Join(s.Dimensions, "\x00") + "\x01" + strings.Join(s.Metrics, "\x00")
+}
+
+// unionSchema は複数のスキーマの列を1つにまとめたスキーマを返す
+// ディメンション列、メトリクス列をそれぞれ最初に現れた順に並べる
+func unionSchema(schemas []*Schema) *Schema {
+	union := &Schema{}
+	for _, schema := range schemas {
+		for _, dim := range schema.Dimensions {
+			if !containsString(union.Dimensions, dim) {
+				union.Dimensions = append(union.Dimensions, dim)
+			}
+		}
+		for _, metric := range schema.Metrics {
+			if !containsString(union.Metrics, metric) {
+				union.Metrics = append(union.Metrics, metric)
+			}
+			if union.MetricType(metric) == "" && schema.MetricType(metric) != "" {
+				if union.MetricTypes == nil {
+					union.MetricTypes = make(map[string]MetricType)
+				}
+				union.MetricTypes[metric] = schema.MetricType(metric)
+			}
+		}
+		for _, pc := range schema.PivotColumns {
+			if _, ok := union.PivotColumn(pc.Name); !ok {
+				union.PivotColumns = append(union.PivotColumns, pc)
+			}
+		}
+		for _, cc := range schema.CohortColumns {
+			if _, ok := union.CohortColumn(cc.Name); !ok {
+				union.CohortColumns = append(union.CohortColumns, cc)
+			}
+		}
+	}
+	return union
+}
+
+// alignRow はfromスキーマの列の並びの行を、toスキーマの列の並びに変換する
+// fromスキーマにない列はnullValueで埋める
+func alignRow(row []string, from, to *Schema, nullValue string) []string {
+	index := make(map[string]int, len(row))
+	for i, column := range from.Columns() {
+		index[column] = i
+	}
+
+	aligned := make([]string, 0, len(to.Dimensions)+len(to.Metrics))
+	for _, column := range to.Columns() {
+		if i, ok := index[column]; ok && i < len(row) {
+			aligned = append(aligned, row[i])
+		} else {
+			aligned = append(aligned, nullValue)
+		}
+	}
+	return aligned
+}
+
+// reportPart は1リクエスト分の取得結果（列の構成と変換済みの行）を表す構造体
+type reportPart struct {
+	schema       *Schema
+	rows         [][]string
+	aggregations []Aggregation
+}
+
+// mergeParts は取得結果を全ストリームの列をまとめた1つの表にする
+// 列を持たないストリームの値はnullValueで埋める
+func mergeParts(parts []reportPart, nullValue string) ([]string, [][]string, *Schema) {
+	schemas := make([]*Schema, len(parts))
+	for i, part := range parts {
+		schemas[i] = part.schema
+	}
+	union := unionSchema(schemas)
+
+	var rows [][]string
+	for _, part := range parts {
+		if part.schema.key() == union.key() {
+			rows = append(rows, part.rows...)
+			continue
+		}
+		for _, row := range part.rows {
+			rows = append(rows, alignRow(row, part.schema, union, nullValue))
+		}
+	}
+	return union.Columns(), rows, union
+}
+
+// splitParts は取得結果を列の構成ごとの表に分ける
+// 表は最初に現れた順に並べる
+func splitParts(parts []reportPart) []*ReportData {
+	var tables []*ReportData
+	byKey := make(map[string]*ReportData)
+	for _, part := range parts {
+		key := part.schema.key()
+		table, ok := byKey[key]
+		if !ok {
+			table = &ReportData{Headers: part.schema.Columns(), Schema: part.schema}
+			byKey[key] = table
+			tables = append(tables, table)
+		}
+		table.Rows = append(table.Rows, part.rows...)
+		table.Aggregations = append(table.Aggregations, part.aggregations...)
+	}
+	return tables
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analytics
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+func TestUnionSchema(t *testing.T) {
+	schemas := []*Schema{
+		{Dimensions: []string{"property_id", "stream_id", "pagePath"}, Metrics: []string{"sessions"}},
+		{Dimensions: []string{"property_id", "stream_id", "country"}, Metrics: []string{"activeUsers", "sessions"}},
+	}
+
+	got := unionSchema(schemas)
+	want := &Schema{
+		Dimensions: []string{"property_id", "stream_id", "pagePath", "country"},
+		Metrics:    []string{"sessions", "activeUsers"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unionSchema() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAlignRow(t *testing.T) {
+	from := &Schema{Dimensions: []string{"property_id", "stream_id", "country"}, Metrics: []string{"activeUsers", "sessions"}}
+	to := &Schema{Dimensions: []string{"property_id", "stream_id", "pagePath", "country"}, Metrics: []string{"sessions", "activeUsers"}}
+
+	got := alignRow([]string{"111", "1", "Japan", "5", "10"}, from, to, "N/A")
+	want := []string{"111", "1", "N/A", "Japan", "10", "5"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("alignRow() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeAndSplitParts(t *testing.T) {
+	pages := &Schema{Dimensions: []string{"property_id", "stream_id", "pagePath"}, Metrics: []string{"sessions"}}
+	countries := &Schema{Dimensions: []string{"property_id", "stream_id", "country"}, Metrics: []string{"sessions"}}
+	parts := []reportPart{
+		{schema: pages, rows: [][]string{{"111", "1", "/", "10"}}},
+		{schema: countries, rows: [][]string{{"111", "2", "Japan", "20"}}},
+		{schema: pages, rows: [][]string{{"222", "3", "/about", "30"}}},
+	}
+
+	headers, rows, schema := mergeParts(parts, "")
+	if want := []string{"property_id", "stream_id", "pagePath", "country", "sessions"}; !reflect.DeepEqual(headers, want) {
+		t.Errorf("headers = %v, want %v", headers, want)
+	}
+	wantRows := [][]string{
+		{"111", "1", "/", "", "10"},
+		{"111", "2", "", "Japan", "20"},
+		{"222", "3", "/about", "", "30"},
+	}
+	if !reflect.DeepEqual(rows, wantRows) {
+		t.Errorf("rows = %v, want %v", rows, wantRows)
+	}
+	if !schema.IsDimension("country") || !schema.IsMetric("sessions") {
+		t.Errorf("schema = %+v", schema)
+	}
+
+	tables := splitParts(parts)
+	if len(tables) != 2 {
+		t.Fatalf("splitParts() = %d 個の表, want 2", len(tables))
+	}
+	if !reflect.DeepEqual(tables[0].Headers, []string{"property_id", "stream_id", "pagePath", "sessions"}) || len(tables[0].Rows) != 2 {
+		t.Errorf("tables[0] = %+v", tables[0])
+	}
+	if !reflect.DeepEqual(tables[1].Headers, []string{"property_id", "stream_id", "country", "sessions"}) || len(tables[1].Rows) != 1 {
+		t.Errorf("tables[1] = %+v", tables[1])
+	}
+}
+
+func TestFetchDataConcurrently_DifferentSchemas(t *testing.T) {
+	var calls []string
+	client := newTestGA4Client(t, reportHandler(t, &calls, func(req *analyticsdata.RunReportRequest) (*analyticsdata.RunReportResponse, int) {
+		dimension := req.Dimensions[0].Name
+		return &analyticsdata.RunReportResponse{
+			DimensionHeaders: []*analyticsdata.DimensionHeader{{Name: dimension}},
+			MetricHeaders:    []*analyticsdata.MetricHeader{{Name: "sessions", Type: "TYPE_INTEGER"}},
+			Rows: []*analyticsdata.Row{{
+				DimensionValues: []*analyticsdata.DimensionValue{{Value: dimension + "-value"}},
+				MetricValues:    []*analyticsdata.MetricValue{{Value: "1"}},
+			}},
+			RowCount: 1,
+		}, http.StatusOK
+	}))
+	service := &AnalyticsServiceImpl{client: client}
+
+	requests := []*GA4ReportRequest{
+		{PropertyID: "111111111", StreamID: "1", StartDate: "2024-01-01", EndDate: "2024-01-31", Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}},
+		{PropertyID: "111111111", StreamID: "2", StartDate: "2024-01-01", EndDate: "2024-01-31", Dimensions: []string{"country"}, Metrics: []string{"sessions"}},
+	}
+
+	cfg := createTestConfig()
+	cfg.NullValue = "(not set)"
+	cfg.SchemaMode = "split"
+
+	data, err := service.fetchDataConcurrently(context.Background(), requests, cfg)
+	if err != nil {
+		t.Fatalf("fetchDataConcurrently() error = %v", err)
+	}
+
+	if want := []string{"property_id", "stream_id", "pagePath", "country", "sessions"}; !reflect.DeepEqual(data.Headers, want) {
+		t.Errorf("Headers = %v, want %v", data.Headers, want)
+	}
+	wantRows := [][]string{
+		{"111111111", "1", "pagePath-value", "(not set)", "1"},
+		{"111111111", "2", "(not set)", "country-value", "1"},
+	}
+	if !reflect.DeepEqual(data.Rows, wantRows) {
+		t.Errorf("Rows = %v, want %v", data.Rows, wantRows)
+	}
+
+	if len(data.Tables) != 2 {
+		t.Fatalf("Tables = %d 個, want 2", len(data.Tables))
+	}
+	for i, table := range data.Tables {
+		if len(table.Headers) != 4 || len(table.Rows) != 1 || table.Summary.TotalRows != 1 {
+			t.Errorf("Tables[%d] = %+v", i, table)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// aggregations に指定できる集計の種類
+// GA4 Data API の MetricAggregation（TOTAL, MINIMUM, MAXIMUM）に対応する
+const (
+	AggregationTotal   = "total"
+	AggregationMinimum = "minimum"
+	AggregationMaximum = "maximum"
+)
+
+// AggregationTypes はaggregations に指定できる値の一覧（出力する順）
+var AggregationTypes = []string{AggregationTotal, AggregationMinimum, AggregationMaximum}
+
+// validateAggregations はaggregations の妥当性と他の設定との組み合わせを検証する
+func (c *ConfigServiceImpl) validateAggregations(stream Stream, streamPath string) error {
+	if len(stream.Aggregations) == 0 {
+		return nil
+	}
+	path := streamPath + ".aggregations"
+
+	seen := make(map[string]bool)
+	for _, aggregation := range stream.Aggregations {
+		if !contains(AggregationTypes, aggregation) {
+			return fmt.Errorf("%s が不正です（%s のいずれかを指定してください）: %s", path, strings.Join(AggregationTypes, ", "), aggregation)
+		}
+		if seen[aggregation] {
+			return fmt.Errorf("%s: %s が重複して指定されています", path, aggregation)
+		}
+		seen[aggregation] = true
+	}
+
+	// 分割した期間やピボット表の集計値は1つの合計にまとめられないため併用できない
+	switch {
+	case stream.ChunkBy != "":
+		return fmt.Errorf("%s: aggregations と chunk_by は同時に指定できません", streamPath)
+	case stream.Pivot != nil:
+		return fmt.Errorf("%s: aggregations と pivot は同時に指定できません", streamPath)
+	}
+
+	return nil
+}
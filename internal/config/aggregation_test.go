@@ -0,0 +1,94 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig_Aggregations(t *testing.T) {
+	service := &ConfigServiceImpl{}
+
+	tests := []struct {
+		name    string
+		modify  func(s *Stream)
+		wantErr string
+	}{
+		{name: "有効な集計値"},
+		{
+			name:   "一部のみ指定",
+			modify: func(s *Stream) { s.Aggregations = []string{"maximum"} },
+		},
+		{
+			name:    "不正な集計値",
+			modify:  func(s *Stream) { s.Aggregations = []string{"average"} },
+			wantErr: "aggregations が不正です",
+		},
+		{
+			name:    "大文字のAPI名",
+			modify:  func(s *Stream) { s.Aggregations = []string{"TOTAL"} },
+			wantErr: "aggregations が不正です",
+		},
+		{
+			name:    "重複",
+			modify:  func(s *Stream) { s.Aggregations = []string{"total", "total"} },
+			wantErr: "total が重複して指定されています",
+		},
+		{
+			name:    "chunk_byと併用",
+			modify:  func(s *Stream) { s.ChunkBy = "month" },
+			wantErr: "aggregations と chunk_by",
+		},
+		{
+			name: "pivotと併用",
+			modify: func(s *Stream) {
+				s.Pivot = &Pivot{Rows: []string{"pagePath"}, Columns: []string{"deviceCategory"}}
+			},
+			wantErr: "aggregations と pivot",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := Stream{
+				ID:           "1234567",
+				Dimensions:   []string{"pagePath", "deviceCategory"},
+				Metrics:      []string{"activeUsers"},
+				Aggregations: []string{"total", "minimum", "maximum"},
+			}
+			if tt.modify != nil {
+				tt.modify(&stream)
+			}
+			config := &Config{
+				StartDate:  "2024-01-01",
+				EndDate:    "2024-01-31",
+				Account:    "123456789",
+				Properties: []Property{{ID: "987654321", Streams: []Stream{stream}}},
+			}
+
+			err := service.ValidateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AllPropertiesKeyword は properties に指定するとアカウント配下のすべてのプロパティを対象にする値
+const AllPropertiesKeyword = "all"
+
+// AllProperties は properties: all の場合の対象プロパティの条件と、各データストリームに適用する設定
+// 対象のプロパティとストリームは実行時にAdmin APIで account から解決する
+type AllProperties struct {
+	Include string `yaml:"include,omitempty"` // 対象とするプロパティの表示名の正規表現（省略時はすべて）
+	Exclude string `yaml:"exclude,omitempty"` // 除外するプロパティの表示名の正規表現
+
+	// Template は解決した各データストリームに適用する設定（stream は指定しない）
+	// base_url を省略した場合はウェブストリームのURLを使用する
+	Template Stream `yaml:"template"`
+}
+
+// UnmarshalYAML は properties: all を PropertiesAll として読み込む
+func (c *Config) UnmarshalYAML(node *yaml.Node) error {
+	type plain Config
+
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value != "properties" || value.Kind != yaml.ScalarNode || value.Value != AllPropertiesKeyword {
+				continue
+			}
+
+			c.PropertiesAll = true
+			mapping := *node
+			mapping.Content = append(append([]*yaml.Node{}, node.Content[:i]...), node.Content[i+2:]...)
+			node = &mapping
+			break
+		}
+	}
+
+	return node.Decode((*plain)(c))
+}
+
+// Matches はプロパティの表示名が include に一致し、exclude に一致しないかを判定する
+func (a *AllProperties) Matches(displayName string) (bool, error) {
+	if a.Include != "" {
+		matched, err := regexp.MatchString(a.Include, displayName)
+		if err != nil {
+			return false, fmt.Errorf("all_properties.include の正規表現が不正です: %w", err)
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	if a.Exclude != "" {
+		matched, err := regexp.MatchString(a.Exclude, displayName)
+		if err != nil {
+			return false, fmt.Errorf("all_properties.exclude の正規表現が不正です: %w", err)
+		}
+		if matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// StreamFor はテンプレートからデータストリームの設定を作成する
+// テンプレートに base_url がない場合は defaultURL（ウェブストリームのURL）を使用する
+func (a *AllProperties) StreamFor(streamID, defaultURL string) Stream {
+	stream := a.Template
+	stream.ID = streamID
+	if stream.BaseURL == "" {
+		stream.BaseURL = defaultURL
+	}
+	return stream
+}
+
+// validateAllProperties は properties: all と all_properties の組み合わせと内容を検証する
+func (c *ConfigServiceImpl) validateAllProperties(config *Config) error {
+	if !config.PropertiesAll {
+		if config.AllProperties != nil {
+			return fmt.Errorf("all_properties は properties: %s の場合のみ指定できます", AllPropertiesKeyword)
+		}
+		return nil
+	}
+
+	all := config.AllProperties
+	if all == nil {
+		return fmt.Errorf("properties: %s の場合は all_properties.template で取得内容を指定してください", AllPropertiesKeyword)
+	}
+
+	for name, pattern := range map[string]string{"include": all.Include, "exclude": all.Exclude} {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("all_properties.%s の正規表現が不正です: %w", name, err)
+		}
+	}
+
+	if strings.TrimSpace(all.Template.ID) != "" {
+		return fmt.Errorf("all_properties.template には stream を指定できません（ストリームは実行時に解決します）")
+	}
+	return c.validateStream(config, all.Template, "all_properties.template")
+}
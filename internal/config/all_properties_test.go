@@ -0,0 +1,175 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// allPropertiesConfig はテスト用の properties: all の設定ファイルの内容
+const allPropertiesConfig = `
+start_date: "2024-01-01"
+end_date: "2024-01-31"
+account: "123456789"
+properties: all
+all_properties:
+  include: "^Client "
+  exclude: "(?i)staging"
+  template:
+    dimensions:
+      - date
+    metrics:
+      - sessions
+`
+
+func TestConfigService_LoadConfig_PropertiesAll(t *testing.T) {
+	service := NewConfigService()
+	path := filepath.Join(t.TempDir(), "ga.yaml")
+	if err := os.WriteFile(path, []byte(allPropertiesConfig), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	config, err := service.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !config.PropertiesAll || len(config.Properties) != 0 {
+		t.Errorf("PropertiesAll = %v, Properties = %+v", config.PropertiesAll, config.Properties)
+	}
+	if config.Account != "123456789" || config.StartDate != "2024-01-01" {
+		t.Errorf("config = %+v", config)
+	}
+	if config.AllProperties == nil || config.AllProperties.Include != "^Client " || strings.Join(config.AllProperties.Template.Metrics, ",") != "sessions" {
+		t.Fatalf("AllProperties = %+v", config.AllProperties)
+	}
+	if err := service.ValidateConfig(config); err != nil {
+		t.Errorf("ValidateConfig() error = %v", err)
+	}
+}
+
+func TestConfigService_LoadConfig_PropertiesInvalidScalar(t *testing.T) {
+	service := NewConfigService()
+	path := filepath.Join(t.TempDir(), "ga.yaml")
+	if err := os.WriteFile(path, []byte("account: \"1\"\nproperties: every\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := service.LoadConfig(path); err == nil {
+		t.Error("LoadConfig() error = nil, want error")
+	}
+}
+
+func TestConfigService_ValidateAllProperties(t *testing.T) {
+	template := Stream{Dimensions: []string{"date"}, Metrics: []string{"sessions"}}
+
+	tests := []struct {
+		name    string
+		all     bool
+		config  *AllProperties
+		wantErr string
+	}{
+		{name: "条件なし", all: true, config: &AllProperties{Template: template}},
+		{name: "all_properties なし", all: true, wantErr: "all_properties.template で取得内容を指定してください"},
+		{name: "properties: all 以外", config: &AllProperties{Template: template}, wantErr: "properties: all の場合のみ指定できます"},
+		{name: "include の正規表現が不正", all: true, config: &AllProperties{Include: "(", Template: template}, wantErr: "all_properties.include の正規表現が不正です"},
+		{name: "exclude の正規表現が不正", all: true, config: &AllProperties{Exclude: "[", Template: template}, wantErr: "all_properties.exclude の正規表現が不正です"},
+		{name: "stream を指定", all: true, config: &AllProperties{Template: Stream{ID: "1", Dimensions: []string{"date"}, Metrics: []string{"sessions"}}}, wantErr: "stream を指定できません"},
+		{name: "metrics なし", all: true, config: &AllProperties{Template: Stream{Dimensions: []string{"date"}}}, wantErr: "all_properties.template.metrics は必須項目です"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				StartDate:     "2024-01-01",
+				EndDate:       "2024-01-31",
+				Account:       "123456789",
+				PropertiesAll: tt.all,
+				AllProperties: tt.config,
+			}
+			if !tt.all {
+				config.Properties = []Property{{ID: "1", Streams: []Stream{{ID: "2", Dimensions: []string{"date"}, Metrics: []string{"sessions"}}}}}
+			}
+
+			err := NewConfigService().ValidateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateConfig() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAllProperties_Matches(t *testing.T) {
+	all := &AllProperties{Include: "^Client ", Exclude: "(?i)staging"}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Client A", true},
+		{"Client B Staging", false},
+		{"Internal", false},
+	}
+	for _, tt := range tests {
+		got, err := all.Matches(tt.name)
+		if err != nil {
+			t.Fatalf("Matches(%q) error = %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("Matches(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if got, _ := (&AllProperties{}).Matches("anything"); !got {
+		t.Error("条件がない場合はすべてのプロパティが一致する必要があります")
+	}
+}
+
+func TestAllProperties_StreamFor(t *testing.T) {
+	all := &AllProperties{Template: Stream{Dimensions: []string{"date"}, Metrics: []string{"sessions"}}}
+
+	stream := all.StreamFor("1001", "https://www.example.com")
+	if stream.ID != "1001" || stream.BaseURL != "https://www.example.com" || stream.Dimensions[0] != "date" {
+		t.Errorf("StreamFor() = %+v", stream)
+	}
+	if all.Template.ID != "" {
+		t.Errorf("テンプレートが変更されています: %+v", all.Template)
+	}
+
+	all.Template.BaseURL = "https://cdn.example.com"
+	if stream := all.StreamFor("1001", "https://www.example.com"); stream.BaseURL != "https://cdn.example.com" {
+		t.Errorf("テンプレートの base_url が優先されていません: %s", stream.BaseURL)
+	}
+}
+
+func TestConfigService_SaveDiscovered_PropertiesAll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ga.yaml")
+	if err := os.WriteFile(path, []byte(allPropertiesConfig), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	_, err := NewConfigService().SaveDiscovered(path, "123456789", discoveredProperties())
+	if err == nil || !strings.Contains(err.Error(), "properties: all") {
+		t.Errorf("SaveDiscovered() error = %v", err)
+	}
+}
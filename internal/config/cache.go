@@ -0,0 +1,65 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Cache はレポートのレスポンスをディスクに保存するキャッシュの設定を表す構造体
+// 省略した場合は既定の有効期間でキャッシュを使用する
+type Cache struct {
+	Disabled bool          `yaml:"disabled,omitempty"` // trueの場合はキャッシュを読み書きしない
+	TTL      time.Duration `yaml:"ttl,omitempty"`      // キャッシュの有効期間（例: 1h）
+
+	// KeepClosedRanges がtrueの場合は、終了日から ClosedRangeDays 日以上経ってから取得した
+	// 過去の期間のレスポンスを有効期間に関係なく使い続ける
+	KeepClosedRanges bool `yaml:"keep_closed_ranges,omitempty"`
+}
+
+const (
+	// DefaultCacheTTL はレスポンスのキャッシュの有効期間の既定値
+	DefaultCacheTTL = time.Hour
+	// ClosedRangeDays は期間の終了日から何日経つとデータが確定したとみなすか
+	// GA4は集計後も数日間データが更新されることがあるため余裕を持たせている
+	ClosedRangeDays = 3
+)
+
+// Enabled はキャッシュを使用するかを返す
+func (c *Cache) Enabled() bool {
+	return c == nil || !c.Disabled
+}
+
+// TTLOrDefault はキャッシュの有効期間を返す
+func (c *Cache) TTLOrDefault() time.Duration {
+	if c == nil || c.TTL == 0 {
+		return DefaultCacheTTL
+	}
+	return c.TTL
+}
+
+// validateCache はcache セクションを検証する
+func (c *ConfigServiceImpl) validateCache(cache *Cache) error {
+	if cache == nil {
+		return nil
+	}
+
+	if cache.TTL < 0 {
+		return fmt.Errorf("cache.ttl は0以上である必要があります: %v", cache.TTL)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateConfig_Cache(t *testing.T) {
+	service := &ConfigServiceImpl{}
+
+	tests := []struct {
+		name    string
+		cache   *Cache
+		wantErr string
+	}{
+		{name: "cacheなし", cache: nil},
+		{name: "有効な設定", cache: &Cache{TTL: 6 * time.Hour, KeepClosedRanges: true}},
+		{name: "無効化", cache: &Cache{Disabled: true}},
+		{
+			name:    "負の有効期間",
+			cache:   &Cache{TTL: -time.Minute},
+			wantErr: "cache.ttl は0以上",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				StartDate: "2024-01-01",
+				EndDate:   "2024-01-31",
+				Account:   "123456789",
+				Properties: []Property{{
+					ID: "987654321",
+					Streams: []Stream{{
+						ID:         "1234567",
+						Dimensions: []string{"date"},
+						Metrics:    []string{"sessions"},
+					}},
+				}},
+				Cache: tt.cache,
+			}
+
+			err := service.ValidateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCache_Defaults(t *testing.T) {
+	var c *Cache
+	if !c.Enabled() {
+		t.Error("Enabled() = false, want true")
+	}
+	if got := c.TTLOrDefault(); got != DefaultCacheTTL {
+		t.Errorf("TTLOrDefault() = %v, want %v", got, DefaultCacheTTL)
+	}
+
+	c = &Cache{Disabled: true, TTL: 30 * time.Minute}
+	if c.Enabled() {
+		t.Error("Enabled() = true, want false")
+	}
+	if got := c.TTLOrDefault(); got != 30*time.Minute {
+		t.Errorf("TTLOrDefault() = %v, want 30m", got)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AdditiveMetrics は期間をまたいで合算できるメトリクス
+var AdditiveMetrics = map[string]bool{
+	"sessions":               true,
+	"engagedSessions":        true,
+	"newUsers":               true,
+	"screenPageViews":        true,
+	"eventCount":             true,
+	"keyEvents":              true,
+	"conversions":            true,
+	"userEngagementDuration": true,
+	"totalRevenue":           true,
+	"purchaseRevenue":        true,
+	"transactions":           true,
+	"ecommercePurchases":     true,
+	"addToCarts":             true,
+	"checkouts":              true,
+	"itemsViewed":            true,
+	"itemsPurchased":         true,
+	"itemRevenue":            true,
+	"publisherAdClicks":      true,
+	"publisherAdImpressions": true,
+	"totalAdRevenue":         true,
+}
+
+// WeightedMetrics はセッション数などによる加重平均で再計算できるメトリクスと、その重みのメトリクス
+var WeightedMetrics = map[string]string{
+	"averageSessionDuration":    "sessions",
+	"bounceRate":                "sessions",
+	"engagementRate":            "sessions",
+	"screenPageViewsPerSession": "sessions",
+	"eventsPerSession":          "sessions",
+	"sessionKeyEventRate":       "sessions",
+}
+
+// chunkPeriodDimensions は分割単位ごとに、行が1つの期間に収まることを保証するディメンション
+var chunkPeriodDimensions = map[string][]string{
+	"day":   {"date", "dateHour", "dateHourMinute"},
+	"week":  {"date", "dateHour", "dateHourMinute", "isoYearIsoWeek"},
+	"month": {"date", "dateHour", "dateHourMinute", "yearMonth"},
+}
+
+// IsAdditiveMetric はメトリクスが期間をまたいで合算できるかを判定する
+// カスタム指標（customEvent:）はイベントパラメータの合計値なので合算できる
+func IsAdditiveMetric(name string) bool {
+	return AdditiveMetrics[name] || strings.HasPrefix(name, "customEvent:")
+}
+
+// validateChunkMetrics はchunk_byで分割した期間をまとめたときに各メトリクスの値を求められるかを検証する
+// dimensions に期間を区別するディメンション（date など）がある場合は行が期間をまたがないため検証しない
+func validateChunkMetrics(stream Stream, streamPath string) error {
+	if stream.ChunkBy == "" {
+		return nil
+	}
+	for _, dim := range stream.Dimensions {
+		if contains(chunkPeriodDimensions[stream.ChunkBy], dim) {
+			return nil
+		}
+	}
+
+	hint := fmt.Sprintf("dimensions に %s のいずれかを含めるか、chunk_by を外してください", strings.Join(chunkPeriodDimensions[stream.ChunkBy], ", "))
+	if len(stream.CalculatedMetrics) > 0 {
+		return fmt.Errorf("%s: calculated_metrics は期間をまたいで合算できないため chunk_by と同時に指定できません（%s）", streamPath, hint)
+	}
+	for _, metric := range stream.Metrics {
+		if IsAdditiveMetric(metric) {
+			continue
+		}
+		if weight := WeightedMetrics[metric]; weight != "" {
+			if contains(stream.Metrics, weight) {
+				continue
+			}
+			return fmt.Errorf("%s: %s を期間をまたいで再計算するには metrics に %s が必要です（%s）", streamPath, metric, weight, hint)
+		}
+		return fmt.Errorf("%s: %s は期間をまたいで合算できないため chunk_by と同時に指定できません（%s）", streamPath, metric, hint)
+	}
+
+	return nil
+}
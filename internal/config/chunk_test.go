@@ -0,0 +1,98 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig_ChunkMetrics(t *testing.T) {
+	service := &ConfigServiceImpl{}
+
+	tests := []struct {
+		name       string
+		chunkBy    string
+		dimensions []string
+		metrics    []string
+		calculated map[string]string
+		wantErr    string
+	}{
+		{name: "合算できるメトリクス", chunkBy: "month", dimensions: []string{"pagePath"}, metrics: []string{"sessions", "screenPageViews", "customEvent:value"}},
+		{name: "加重平均で再計算", chunkBy: "month", dimensions: []string{"pagePath"}, metrics: []string{"sessions", "engagementRate"}},
+		{name: "dateがあれば行が期間をまたがない", chunkBy: "day", dimensions: []string{"date", "pagePath"}, metrics: []string{"activeUsers"}, calculated: map[string]string{"pv_per_session": "screenPageViews/sessions"}},
+		{name: "月ごとの分割とyearMonth", chunkBy: "month", dimensions: []string{"yearMonth"}, metrics: []string{"activeUsers"}},
+		{
+			name:       "週ごとの分割とyearMonth",
+			chunkBy:    "week",
+			dimensions: []string{"yearMonth"},
+			metrics:    []string{"activeUsers"},
+			wantErr:    "activeUsers は期間をまたいで合算できないため chunk_by と同時に指定できません",
+		},
+		{
+			name:       "合算できないメトリクス",
+			chunkBy:    "month",
+			dimensions: []string{"pagePath"},
+			metrics:    []string{"sessions", "activeUsers"},
+			wantErr:    "activeUsers は期間をまたいで合算できない",
+		},
+		{
+			name:       "重みのメトリクスがない",
+			chunkBy:    "month",
+			dimensions: []string{"pagePath"},
+			metrics:    []string{"bounceRate"},
+			wantErr:    "bounceRate を期間をまたいで再計算するには metrics に sessions が必要です",
+		},
+		{
+			name:       "計算指標",
+			chunkBy:    "week",
+			dimensions: []string{"pagePath"},
+			metrics:    []string{"sessions"},
+			calculated: map[string]string{"pv_per_session": "screenPageViews/sessions"},
+			wantErr:    "calculated_metrics は期間をまたいで合算できないため chunk_by と同時に指定できません",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				StartDate: "2024-01-01",
+				EndDate:   "2024-03-31",
+				Account:   "123456789",
+				Properties: []Property{{
+					ID: "987654321",
+					Streams: []Stream{{
+						ID:                "1234567",
+						Dimensions:        tt.dimensions,
+						Metrics:           tt.metrics,
+						CalculatedMetrics: tt.calculated,
+						ChunkBy:           tt.chunkBy,
+					}},
+				}},
+			}
+
+			err := service.ValidateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Cohort はコホートレポート（獲得期間ごとのユーザーの継続状況）の設定を表す構造体
+type Cohort struct {
+	Granularity string        `yaml:"granularity"`          // 期間の単位（daily, weekly, monthly）
+	Periods     int           `yaml:"periods"`              // 獲得期間を0として集計する期間の数
+	Cohorts     []CohortRange `yaml:"cohorts,omitempty"`    // 省略時は集計期間を granularity ごとに分割したコホート
+	Accumulate  bool          `yaml:"accumulate,omitempty"` // trueの場合は各期間までの累計値を取得する
+}
+
+// CohortRange は初回訪問日の期間で定義する1つのコホートを表す構造体
+type CohortRange struct {
+	Name      string `yaml:"name,omitempty"` // 省略時は start_date
+	StartDate string `yaml:"start_date"`
+	EndDate   string `yaml:"end_date"`
+}
+
+// cohortGranularities はgranularity に指定できる値とGA4 APIの値・期間のディメンション・期間の分割単位の対応
+var cohortGranularities = map[string]struct {
+	api       string
+	dimension string
+	unit      string
+}{
+	"daily":   {api: "DAILY", dimension: "cohortNthDay", unit: "day"},
+	"weekly":  {api: "WEEKLY", dimension: "cohortNthWeek", unit: "week"},
+	"monthly": {api: "MONTHLY", dimension: "cohortNthMonth", unit: "month"},
+}
+
+// CohortDimension はコホート名のディメンション
+const CohortDimension = "cohort"
+
+// CohortGranularities はgranularity に指定できる値の一覧
+func CohortGranularities() []string {
+	var names []string
+	for name := range cohortGranularities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// APIGranularity はgranularity に対応するGA4 APIの値（DAILY, WEEKLY, MONTHLY）を返す
+func (c *Cohort) APIGranularity() string {
+	return cohortGranularities[c.Granularity].api
+}
+
+// PeriodDimension はgranularity に対応する期間のディメンション（cohortNthDay など）を返す
+func (c *Cohort) PeriodDimension() string {
+	return cohortGranularities[c.Granularity].dimension
+}
+
+// PeriodUnit はgranularity に対応する期間の単位（day, week, month）を返す
+func (c *Cohort) PeriodUnit() string {
+	return cohortGranularities[c.Granularity].unit
+}
+
+// isCohortPeriodDimension はディメンションがコホートの期間のディメンションかを判定する
+func isCohortPeriodDimension(name string) bool {
+	for _, g := range cohortGranularities {
+		if g.dimension == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateCohort はcohort の妥当性と他の設定との組み合わせを検証する
+func (c *ConfigServiceImpl) validateCohort(stream Stream, streamPath string) error {
+	cohort := stream.Cohort
+	if cohort == nil {
+		return nil
+	}
+	path := streamPath + ".cohort"
+
+	if _, ok := cohortGranularities[cohort.Granularity]; !ok {
+		return fmt.Errorf("%s.granularity が不正です（%s のいずれかを指定してください）: %s", path, strings.Join(CohortGranularities(), ", "), cohort.Granularity)
+	}
+	if cohort.Periods <= 0 {
+		return fmt.Errorf("%s.periods は1以上で指定してください: %d", path, cohort.Periods)
+	}
+
+	names := make(map[string]bool)
+	for i, r := range cohort.Cohorts {
+		rangePath := fmt.Sprintf("%s.cohorts[%d]", path, i)
+		start, err := time.Parse(DateLayout, r.StartDate)
+		if err != nil {
+			return fmt.Errorf("%s.start_date の形式が不正です（YYYY-MM-DD）: %s", rangePath, r.StartDate)
+		}
+		end, err := time.Parse(DateLayout, r.EndDate)
+		if err != nil {
+			return fmt.Errorf("%s.end_date の形式が不正です（YYYY-MM-DD）: %s", rangePath, r.EndDate)
+		}
+		if start.After(end) {
+			return fmt.Errorf("%s.start_date は end_date より前の日付である必要があります", rangePath)
+		}
+
+		name := r.Name
+		if name == "" {
+			name = r.StartDate
+		}
+		if names[name] {
+			return fmt.Errorf("%s: コホート名 %s が重複しています", rangePath, name)
+		}
+		names[name] = true
+	}
+
+	// 期間のディメンションは granularity に合わせて自動で追加する
+	for _, dim := range stream.Dimensions {
+		if isCohortPeriodDimension(dim) && dim != cohort.PeriodDimension() {
+			return fmt.Errorf("%s: granularity が %s の場合、ディメンション %s は使用できません（%s を使用してください）", path, cohort.Granularity, dim, cohort.PeriodDimension())
+		}
+	}
+
+	switch {
+	case stream.ChunkBy != "":
+		return fmt.Errorf("%s: cohort と chunk_by は同時に指定できません", streamPath)
+	case stream.Compare != nil:
+		return fmt.Errorf("%s: cohort と compare は同時に指定できません", streamPath)
+	case stream.Pivot != nil:
+		return fmt.Errorf("%s: cohort と pivot は同時に指定できません", streamPath)
+	case len(stream.Aggregations) > 0:
+		return fmt.Errorf("%s: cohort と aggregations は同時に指定できません", streamPath)
+	case stream.Limit > 0 || len(stream.OrderBy) > 0:
+		return fmt.Errorf("%s: cohort と limit / order_by は同時に指定できません", streamPath)
+	case stream.PageSize > 0 || stream.MaxRows > 0:
+		return fmt.Errorf("%s: cohort と page_size / max_rows は同時に指定できません", streamPath)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig_Cohort(t *testing.T) {
+	service := &ConfigServiceImpl{}
+
+	tests := []struct {
+		name    string
+		modify  func(s *Stream)
+		wantErr string
+	}{
+		{name: "有効なコホート（集計期間から自動作成）"},
+		{
+			name: "コホートを明示",
+			modify: func(s *Stream) {
+				s.Cohort.Cohorts = []CohortRange{
+					{Name: "january", StartDate: "2024-01-01", EndDate: "2024-01-31"},
+					{StartDate: "2024-02-01", EndDate: "2024-02-29"},
+				}
+			},
+		},
+		{
+			name:   "期間のディメンションを明示",
+			modify: func(s *Stream) { s.Dimensions = []string{"cohort", "cohortNthWeek", "country"} },
+		},
+		{
+			name:    "不正なgranularity",
+			modify:  func(s *Stream) { s.Cohort.Granularity = "yearly" },
+			wantErr: "cohort.granularity が不正です",
+		},
+		{
+			name:    "periodsなし",
+			modify:  func(s *Stream) { s.Cohort.Periods = 0 },
+			wantErr: "cohort.periods は1以上",
+		},
+		{
+			name: "不正な日付",
+			modify: func(s *Stream) {
+				s.Cohort.Cohorts = []CohortRange{{StartDate: "2024/01/01", EndDate: "2024-01-31"}}
+			},
+			wantErr: "cohorts[0].start_date の形式が不正です",
+		},
+		{
+			name: "開始日が終了日より後",
+			modify: func(s *Stream) {
+				s.Cohort.Cohorts = []CohortRange{{StartDate: "2024-02-01", EndDate: "2024-01-31"}}
+			},
+			wantErr: "cohorts[0].start_date は end_date より前",
+		},
+		{
+			name: "コホート名の重複",
+			modify: func(s *Stream) {
+				s.Cohort.Cohorts = []CohortRange{
+					{Name: "2024-01-01", StartDate: "2024-01-08", EndDate: "2024-01-14"},
+					{StartDate: "2024-01-01", EndDate: "2024-01-07"},
+				}
+			},
+			wantErr: "コホート名 2024-01-01 が重複しています",
+		},
+		{
+			name:    "granularityと異なる期間のディメンション",
+			modify:  func(s *Stream) { s.Dimensions = []string{"cohortNthDay"} },
+			wantErr: "cohortNthWeek を使用してください",
+		},
+		{
+			name:    "chunk_byと併用",
+			modify:  func(s *Stream) { s.ChunkBy = "month" },
+			wantErr: "cohort と chunk_by",
+		},
+		{
+			name:    "compareと併用",
+			modify:  func(s *Stream) { s.Compare = &Comparison{Type: "previous_period"} },
+			wantErr: "cohort と compare",
+		},
+		{
+			name:    "aggregationsと併用",
+			modify:  func(s *Stream) { s.Aggregations = []string{"total"} },
+			wantErr: "cohort と aggregations",
+		},
+		{
+			name:    "limitと併用",
+			modify:  func(s *Stream) { s.Limit = 10 },
+			wantErr: "cohort と limit / order_by",
+		},
+		{
+			name:    "order_byと併用",
+			modify:  func(s *Stream) { s.OrderBy = []OrderBy{{Field: "cohortActiveUsers", Desc: true}} },
+			wantErr: "cohort と limit / order_by",
+		},
+		{
+			name:    "page_sizeと併用",
+			modify:  func(s *Stream) { s.PageSize = 1000 },
+			wantErr: "cohort と page_size / max_rows",
+		},
+		{
+			name:    "max_rowsと併用",
+			modify:  func(s *Stream) { s.MaxRows = 100 },
+			wantErr: "cohort と page_size / max_rows",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := Stream{
+				ID:      "1234567",
+				Metrics: []string{"cohortActiveUsers"},
+				Cohort:  &Cohort{Granularity: "weekly", Periods: 6},
+			}
+			if tt.modify != nil {
+				tt.modify(&stream)
+			}
+			config := &Config{
+				StartDate:  "2024-01-01",
+				EndDate:    "2024-01-31",
+				Account:    "123456789",
+				Properties: []Property{{ID: "987654321", Streams: []Stream{stream}}},
+			}
+
+			err := service.ValidateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCohort_Granularity(t *testing.T) {
+	cohort := &Cohort{Granularity: "monthly"}
+	if got := cohort.APIGranularity(); got != "MONTHLY" {
+		t.Errorf("APIGranularity() = %q, want MONTHLY", got)
+	}
+	if got := cohort.PeriodDimension(); got != "cohortNthMonth" {
+		t.Errorf("PeriodDimension() = %q, want cohortNthMonth", got)
+	}
+	if got := cohort.PeriodUnit(); got != "month" {
+		t.Errorf("PeriodUnit() = %q, want month", got)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "fmt"
+
+// Concurrency はAPI呼び出しの同時実行数と頻度の制限を表す構造体
+// 429 (Too Many Requests) によるバックオフは全ての呼び出しで共有される
+type Concurrency struct {
+	MaxInFlight            int     `yaml:"max_in_flight,omitempty"`              // 全体で同時に実行するAPI呼び出しの最大数
+	MaxInFlightPerProperty int     `yaml:"max_in_flight_per_property,omitempty"` // プロパティごとに同時に実行するAPI呼び出しの最大数
+	RequestsPerSecond      float64 `yaml:"requests_per_second,omitempty"`        // 1秒あたりのAPI呼び出し数の上限
+	Burst                  int     `yaml:"burst,omitempty"`                      // 一度に連続して送信できるAPI呼び出しの数
+}
+
+const (
+	// DefaultMaxInFlight は全体で同時に実行するAPI呼び出し数の既定値
+	DefaultMaxInFlight = 10
+	// DefaultMaxInFlightPerProperty はプロパティごとに同時に実行するAPI呼び出し数の既定値
+	// GA4標準プロパティの同時リクエスト数のクォータ（10）より余裕を持たせている
+	DefaultMaxInFlightPerProperty = 5
+	// DefaultRequestsPerSecond は1秒あたりのAPI呼び出し数の既定値
+	DefaultRequestsPerSecond = 10.0
+	// DefaultBurst は一度に連続して送信できるAPI呼び出し数の既定値
+	DefaultBurst = 5
+)
+
+// MaxInFlightOrDefault は全体の同時実行数を返す
+func (c *Concurrency) MaxInFlightOrDefault() int {
+	if c == nil || c.MaxInFlight == 0 {
+		return DefaultMaxInFlight
+	}
+	return c.MaxInFlight
+}
+
+// MaxInFlightPerPropertyOrDefault はプロパティごとの同時実行数を返す
+func (c *Concurrency) MaxInFlightPerPropertyOrDefault() int {
+	if c == nil || c.MaxInFlightPerProperty == 0 {
+		return min(DefaultMaxInFlightPerProperty, c.MaxInFlightOrDefault())
+	}
+	return c.MaxInFlightPerProperty
+}
+
+// RequestsPerSecondOrDefault は1秒あたりのAPI呼び出し数の上限を返す
+func (c *Concurrency) RequestsPerSecondOrDefault() float64 {
+	if c == nil || c.RequestsPerSecond == 0 {
+		return DefaultRequestsPerSecond
+	}
+	return c.RequestsPerSecond
+}
+
+// BurstOrDefault は一度に連続して送信できるAPI呼び出しの数を返す
+func (c *Concurrency) BurstOrDefault() int {
+	if c == nil || c.Burst == 0 {
+		return DefaultBurst
+	}
+	return c.Burst
+}
+
+// validateConcurrency はconcurrency セクションを検証する
+func (c *ConfigServiceImpl) validateConcurrency(cc *Concurrency) error {
+	if cc == nil {
+		return nil
+	}
+
+	if cc.MaxInFlight < 0 {
+		return fmt.Errorf("concurrency.max_in_flight は0以上である必要があります: %d", cc.MaxInFlight)
+	}
+	if cc.MaxInFlightPerProperty < 0 {
+		return fmt.Errorf("concurrency.max_in_flight_per_property は0以上である必要があります: %d", cc.MaxInFlightPerProperty)
+	}
+	if cc.MaxInFlightPerPropertyOrDefault() > cc.MaxInFlightOrDefault() {
+		return fmt.Errorf("concurrency.max_in_flight_per_property (%d) は concurrency.max_in_flight (%d) 以下である必要があります", cc.MaxInFlightPerPropertyOrDefault(), cc.MaxInFlightOrDefault())
+	}
+	if cc.RequestsPerSecond < 0 {
+		return fmt.Errorf("concurrency.requests_per_second は0以上である必要があります: %g", cc.RequestsPerSecond)
+	}
+	if cc.Burst < 0 {
+		return fmt.Errorf("concurrency.burst は0以上である必要があります: %d", cc.Burst)
+	}
+
+	return nil
+}
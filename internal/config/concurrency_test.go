@@ -0,0 +1,106 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig_Concurrency(t *testing.T) {
+	service := &ConfigServiceImpl{}
+
+	tests := []struct {
+		name        string
+		concurrency *Concurrency
+		wantErr     string
+	}{
+		{name: "concurrencyなし", concurrency: nil},
+		{name: "有効な設定", concurrency: &Concurrency{MaxInFlight: 4, MaxInFlightPerProperty: 2, RequestsPerSecond: 0.5, Burst: 1}},
+		{name: "全体のみ指定（プロパティごとは全体に合わせる）", concurrency: &Concurrency{MaxInFlight: 2}},
+		{
+			name:        "負の同時実行数",
+			concurrency: &Concurrency{MaxInFlight: -1},
+			wantErr:     "concurrency.max_in_flight は0以上",
+		},
+		{
+			name:        "プロパティごとが全体より大きい",
+			concurrency: &Concurrency{MaxInFlight: 3, MaxInFlightPerProperty: 4},
+			wantErr:     "concurrency.max_in_flight_per_property (4) は concurrency.max_in_flight (3) 以下",
+		},
+		{
+			name:        "負のレート",
+			concurrency: &Concurrency{RequestsPerSecond: -2},
+			wantErr:     "concurrency.requests_per_second は0以上",
+		},
+		{
+			name:        "負のバースト",
+			concurrency: &Concurrency{Burst: -1},
+			wantErr:     "concurrency.burst は0以上",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				StartDate: "2024-01-01",
+				EndDate:   "2024-01-31",
+				Account:   "123456789",
+				Properties: []Property{{
+					ID: "987654321",
+					Streams: []Stream{{
+						ID:         "1234567",
+						Dimensions: []string{"date"},
+						Metrics:    []string{"sessions"},
+					}},
+				}},
+				Concurrency: tt.concurrency,
+			}
+
+			err := service.ValidateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConcurrency_Defaults(t *testing.T) {
+	var c *Concurrency
+	if got := c.MaxInFlightOrDefault(); got != DefaultMaxInFlight {
+		t.Errorf("MaxInFlightOrDefault() = %d, want %d", got, DefaultMaxInFlight)
+	}
+	if got := c.MaxInFlightPerPropertyOrDefault(); got != DefaultMaxInFlightPerProperty {
+		t.Errorf("MaxInFlightPerPropertyOrDefault() = %d, want %d", got, DefaultMaxInFlightPerProperty)
+	}
+	if got := c.RequestsPerSecondOrDefault(); got != DefaultRequestsPerSecond {
+		t.Errorf("RequestsPerSecondOrDefault() = %g, want %g", got, DefaultRequestsPerSecond)
+	}
+	if got := c.BurstOrDefault(); got != DefaultBurst {
+		t.Errorf("BurstOrDefault() = %d, want %d", got, DefaultBurst)
+	}
+
+	// 全体の同時実行数が既定値より小さい場合、プロパティごとの既定値は全体に合わせる
+	c = &Concurrency{MaxInFlight: 2}
+	if got := c.MaxInFlightPerPropertyOrDefault(); got != 2 {
+		t.Errorf("MaxInFlightPerPropertyOrDefault() = %d, want 2", got)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -31,14 +32,45 @@ type ConfigService interface {
 
 	// ValidateConfig は設定ファイルの内容を検証する
 	ValidateConfig(config *Config) error
+
+	// SaveDiscovered はAdmin APIで取得したプロパティとストリームを設定ファイルに反映する
+	SaveDiscovered(path, account string, properties []Property) ([]string, error)
 }
 
 // Config はアプリケーション設定を表す構造体
 type Config struct {
 	StartDate  string     `yaml:"start_date"`
 	EndDate    string     `yaml:"end_date"`
+	DateRange  string     `yaml:"date_range,omitempty"` // 名前付き期間（start_date/end_date の代わりに指定）
+	Timezone   string     `yaml:"timezone,omitempty"`   // 日付を解決するタイムゾーン（例: Asia/Tokyo）
 	Account    string     `yaml:"account"`
 	Properties []Property `yaml:"properties"`
+
+	// PropertiesAll は properties: all が指定されている場合にtrueになる
+	// 実行時に Properties をアカウント配下のプロパティで置き換える
+	PropertiesAll bool           `yaml:"-"`
+	AllProperties *AllProperties `yaml:"all_properties,omitempty"` // properties: all の対象の条件と取得内容
+
+	Realtime *Realtime `yaml:"realtime,omitempty"` // ga realtime で使用するリアルタイムレポートの設定
+
+	Quota       *Quota       `yaml:"quota,omitempty"`       // クォータ残量による取得の抑制設定
+	Concurrency *Concurrency `yaml:"concurrency,omitempty"` // API呼び出しの同時実行数と頻度の制限
+	Retry       *Retry       `yaml:"retry,omitempty"`       // API呼び出しのリトライ方法
+	Cache       *Cache       `yaml:"cache,omitempty"`       // レポートのレスポンスのキャッシュ
+
+	Incremental *Incremental `yaml:"incremental,omitempty"` // 前回の取得以降のデータのみを取得する差分取得の設定
+
+	// ContinueOnError がtrueの場合は一部のリクエストが失敗しても、取得できたデータを出力する
+	ContinueOnError bool `yaml:"continue_on_error,omitempty"`
+
+	// Strict がtrueの場合はサンプリングまたはしきい値が適用されたデータを取得したときに失敗する
+	Strict bool `yaml:"strict,omitempty"`
+
+	// ストリームごとに dimensions/metrics が異なる場合の出力方法
+	SchemaMode string `yaml:"schema_mode,omitempty"` // union（既定）または split
+	NullValue  string `yaml:"null_value,omitempty"`  // union で列を持たないストリームの値（省略時は空文字）
+
+	NumberFormat *NumberFormat `yaml:"number_format,omitempty"` // CSV・表形式のメトリクスの書式
 }
 
 // Property はGoogle Analytics プロパティを表す構造体
@@ -53,8 +85,99 @@ type Stream struct {
 	BaseURL    string   `yaml:"base_url,omitempty"`
 	Dimensions []string `yaml:"dimensions"`
 	Metrics    []string `yaml:"metrics"`
+	PageSize   int      `yaml:"page_size,omitempty"` // 1回のAPI呼び出しで取得する行数（省略時は既定値）
+	MaxRows    int      `yaml:"max_rows,omitempty"`  // 取得する最大行数（0は無制限）
+
+	// PropertyWide がtrueの場合はstreamIdで絞り込まず、プロパティ全体のデータを取得する
+	PropertyWide bool `yaml:"property_wide,omitempty"`
+
+	DimensionFilter *FilterExpression `yaml:"dimension_filter,omitempty"`
+	MetricFilter    *FilterExpression `yaml:"metric_filter,omitempty"`
+
+	OrderBy []OrderBy `yaml:"order_by,omitempty"` // 並び替え条件（先頭から優先）
+	Limit   int       `yaml:"limit,omitempty"`    // 上位N件のみ取得する場合の件数（0は無制限）
+
+	Compare *Comparison `yaml:"compare,omitempty"` // 比較期間（指定時は差分列を出力する）
+
+	ChunkBy string `yaml:"chunk_by,omitempty"` // 期間の分割単位（day, week, month）。省略時は分割しない
+
+	// CalculatedMetrics は別名とメトリクス式（例: screenPageViews/sessions）の対応
+	// 出力列には別名を使い、metrics の後に別名の昇順で並べる
+	CalculatedMetrics map[string]string `yaml:"calculated_metrics,omitempty"`
+
+	Pivot *Pivot `yaml:"pivot,omitempty"` // ピボット表（行 × 列のクロス集計）として取得する場合の設定
+
+	Cohort *Cohort `yaml:"cohort,omitempty"` // コホートレポート（継続率の表）として取得する場合の設定
+
+	// Aggregations はAPIで計算する集計値（total, minimum, maximum）
+	// 非加算のメトリクス（activeUsers など）も正しく集計される
+	Aggregations []string `yaml:"aggregations,omitempty"`
+
+	// FetchStartDate は差分取得でこのストリームを取得する開始日（YYYY-MM-DD形式。空の場合は start_date）
+	FetchStartDate string `yaml:"-"`
+}
+
+// Comparison は比較期間を表す構造体
+type Comparison struct {
+	Type      string `yaml:"type"`                 // previous_period, previous_year, custom のいずれか
+	StartDate string `yaml:"start_date,omitempty"` // type が custom の場合の開始日
+	EndDate   string `yaml:"end_date,omitempty"`   // type が custom の場合の終了日
+}
+
+// ComparisonTypes はcompare.type に指定できる値の一覧
+var ComparisonTypes = []string{
+	"previous_period", // 集計期間の直前の同じ日数の期間
+	"previous_year",   // 前年の同じ期間
+	"custom",          // start_date / end_date で明示した期間
+}
+
+// ComparisonTimeDimensions はcompare と同時に使用できない日付・時間のディメンションの一覧
+// 集計期間と比較期間で値が重ならないため、同じ行にまとめられず差分が意味を持たない
+// 期間内の位置で比較する場合は nthDay などの nth* ディメンションを使用する
+var ComparisonTimeDimensions = []string{
+	"date",
+	"dateHour",
+	"dateHourMinute",
+	"day",
+	"isoWeek",
+	"isoYear",
+	"isoYearIsoWeek",
+	"month",
+	"week",
+	"year",
+	"yearMonth",
+	"yearWeek",
 }
 
+// OrderBy は並び替え条件を表す構造体
+type OrderBy struct {
+	Field string `yaml:"field"`          // dimensions または metrics に含まれる名前
+	Desc  bool   `yaml:"desc,omitempty"` // trueの場合は降順
+	Type  string `yaml:"type,omitempty"` // ディメンションの並び順（alphanumeric, case_insensitive_alphanumeric, numeric）
+}
+
+// DimensionOrderTypes はorder_by.type とGA4 APIの値の対応
+var DimensionOrderTypes = map[string]string{
+	"alphanumeric":                  "ALPHANUMERIC",
+	"case_insensitive_alphanumeric": "CASE_INSENSITIVE_ALPHANUMERIC",
+	"numeric":                       "NUMERIC",
+}
+
+// ChunkUnits はchunk_by に指定できる分割単位の一覧
+var ChunkUnits = []string{"day", "week", "month"}
+
+// metricNamePattern はメトリクスのAPI名の形式（customEvent:name などのカスタム指標を含む）
+var metricNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*(:[A-Za-z0-9_]+)?$`)
+
+// calculatedMetricNamePattern は計算指標の別名の形式
+var calculatedMetricNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// metricExpressionPattern は計算指標の式に使用できる文字（メトリクス名、数値、四則演算、括弧）
+var metricExpressionPattern = regexp.MustCompile(`^[A-Za-z0-9_:.+\-*/() ]+$`)
+
+// MaxPageSize はGA4 Data APIの1リクエストで取得できる最大行数
+const MaxPageSize = 250000
+
 // ConfigServiceImpl はConfigServiceの実装
 type ConfigServiceImpl struct{}
 
@@ -111,43 +234,91 @@ func (c *ConfigServiceImpl) ValidateConfig(config *Config) error {
 		return err
 	}
 
+	// properties: all の検証
+	if err := c.validateAllProperties(config); err != nil {
+		return err
+	}
+
+	// リアルタイムレポート設定の検証
+	if err := c.validateRealtime(config.Realtime); err != nil {
+		return err
+	}
+
+	// クォータ設定の検証
+	if err := c.validateQuota(config.Quota); err != nil {
+		return err
+	}
+
+	// 同時実行数とレート制限の設定の検証
+	if err := c.validateConcurrency(config.Concurrency); err != nil {
+		return err
+	}
+
+	// リトライ設定の検証
+	if err := c.validateRetry(config.Retry); err != nil {
+		return err
+	}
+
+	// キャッシュ設定の検証
+	if err := c.validateCache(config.Cache); err != nil {
+		return err
+	}
+
+	// 差分取得の設定の検証
+	if err := c.validateIncremental(config); err != nil {
+		return err
+	}
+
+	// 出力する表の構成の検証
+	if err := c.validateSchemaMode(config); err != nil {
+		return err
+	}
+
+	// 数値の書式の検証
+	if err := c.validateNumberFormat(config.NumberFormat); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // validateRequiredFields は必須項目の存在を検証する
 func (c *ConfigServiceImpl) validateRequiredFields(config *Config) error {
-	if strings.TrimSpace(config.StartDate) == "" {
-		return fmt.Errorf("start_date は必須項目です")
-	}
-	if strings.TrimSpace(config.EndDate) == "" {
-		return fmt.Errorf("end_date は必須項目です")
+	if config.DateRange == "" {
+		if strings.TrimSpace(config.StartDate) == "" {
+			return fmt.Errorf("start_date は必須項目です")
+		}
+		if strings.TrimSpace(config.EndDate) == "" {
+			return fmt.Errorf("end_date は必須項目です")
+		}
 	}
 	if strings.TrimSpace(config.Account) == "" {
 		return fmt.Errorf("account は必須項目です")
 	}
-	if len(config.Properties) == 0 {
+	if len(config.Properties) == 0 && !config.PropertiesAll {
 		return fmt.Errorf("properties は必須項目です")
 	}
 	return nil
 }
 
 // validateDateFormat は日付形式を検証する
+// YYYY-MM-DD 形式のほか、today, yesterday, NdaysAgo と名前付き期間（date_range）を受け付ける
 func (c *ConfigServiceImpl) validateDateFormat(config *Config) error {
-	dateFormat := "2006-01-02"
-
-	// start_date の検証
-	if _, err := time.Parse(dateFormat, config.StartDate); err != nil {
-		return fmt.Errorf("start_date の形式が不正です（YYYY-MM-DD形式で入力してください）: %s", config.StartDate)
+	if _, err := config.Location(); err != nil {
+		return err
 	}
 
-	// end_date の検証
-	if _, err := time.Parse(dateFormat, config.EndDate); err != nil {
-		return fmt.Errorf("end_date の形式が不正です（YYYY-MM-DD形式で入力してください）: %s", config.EndDate)
+	if config.DateRange != "" {
+		if config.StartDate != "" || config.EndDate != "" {
+			return fmt.Errorf("date_range と start_date / end_date は同時に指定できません")
+		}
 	}
 
-	// 日付の論理的検証（開始日 <= 終了日）
-	startDate, _ := time.Parse(dateFormat, config.StartDate)
-	endDate, _ := time.Parse(dateFormat, config.EndDate)
+	// 日付の解決と論理的検証（開始日 <= 終了日）
+	startDate, endDate, err := config.DateBounds(time.Now())
+	if err != nil {
+		return err
+	}
 	if startDate.After(endDate) {
 		return fmt.Errorf("start_date は end_date より前の日付である必要があります")
 	}
@@ -190,40 +361,232 @@ func (c *ConfigServiceImpl) validatePropertiesAndStreams(config *Config) error {
 				return fmt.Errorf("properties[%d].streams[%d].stream ID の形式が不正です（数字のみ）: %s", i, j, stream.ID)
 			}
 
-			// base_urlの検証（オプション項目）
-			if err := c.validateBaseURL(stream.BaseURL, i, j); err != nil {
+			if err := c.validateStream(config, stream, fmt.Sprintf("properties[%d].streams[%d]", i, j)); err != nil {
 				return err
 			}
+		}
+	}
 
-			// ディメンションとメトリクスの検証
-			if len(stream.Dimensions) == 0 {
-				return fmt.Errorf("properties[%d].streams[%d].dimensions は必須項目です", i, j)
+	return nil
+}
+
+// validateStream はストリームのストリームID以外の設定を検証する
+func (c *ConfigServiceImpl) validateStream(config *Config, stream Stream, streamPath string) error {
+	// base_urlの検証（オプション項目）
+	if err := c.validateBaseURL(stream.BaseURL, streamPath); err != nil {
+		return err
+	}
+
+	// ページングの検証
+	if err := c.validatePaging(stream, streamPath); err != nil {
+		return err
+	}
+
+	// フィルタの検証
+	if err := validateFilterExpression(stream.DimensionFilter, streamPath+".dimension_filter", false); err != nil {
+		return err
+	}
+	if err := validateFilterExpression(stream.MetricFilter, streamPath+".metric_filter", true); err != nil {
+		return err
+	}
+
+	// 並び替え条件の検証
+	if err := c.validateOrderBy(stream, streamPath); err != nil {
+		return err
+	}
+
+	// 比較期間の検証
+	if err := c.validateComparison(config, stream, streamPath+".compare"); err != nil {
+		return err
+	}
+
+	// 期間分割の検証
+	if err := c.validateChunkBy(stream, streamPath); err != nil {
+		return err
+	}
+
+	// ディメンションとメトリクスの検証（コホートレポートではコホートと期間のディメンションを自動で追加する）
+	if len(stream.Dimensions) == 0 && stream.Cohort == nil {
+		return fmt.Errorf("%s.dimensions は必須項目です", streamPath)
+	}
+	if len(stream.Metrics) == 0 && len(stream.CalculatedMetrics) == 0 {
+		return fmt.Errorf("%s.metrics は必須項目です", streamPath)
+	}
+
+	// メトリクス名の形式の検証（利用可能かどうかはメタデータで検証する）
+	for _, metric := range stream.Metrics {
+		if !metricNamePattern.MatchString(metric) {
+			return fmt.Errorf("%s のメトリクス名の形式が不正です: %s", streamPath, metric)
+		}
+	}
+
+	// 計算指標の検証
+	if err := c.validateCalculatedMetrics(stream, streamPath); err != nil {
+		return err
+	}
+
+	// ピボット表の検証
+	if err := c.validatePivot(stream, streamPath); err != nil {
+		return err
+	}
+
+	// コホートレポートの検証
+	if err := c.validateCohort(stream, streamPath); err != nil {
+		return err
+	}
+
+	// 集計値の検証
+	if err := c.validateAggregations(stream, streamPath); err != nil {
+		return err
+	}
+
+	// 分割した期間をまとめたときにメトリクスの値を求められるかの検証
+	if err := validateChunkMetrics(stream, streamPath); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateCalculatedMetrics は計算指標の別名と式を検証する
+func (c *ConfigServiceImpl) validateCalculatedMetrics(stream Stream, streamPath string) error {
+	for _, name := range stream.CalculatedMetricNames() {
+		path := fmt.Sprintf("%s.calculated_metrics.%s", streamPath, name)
+		if !calculatedMetricNamePattern.MatchString(name) {
+			return fmt.Errorf("%s: 別名には英数字とアンダースコアのみ使用できます", path)
+		}
+		if contains(stream.Metrics, name) || contains(stream.Dimensions, name) {
+			return fmt.Errorf("%s: 別名が dimensions または metrics の名前と重複しています", path)
+		}
+
+		expression := stream.CalculatedMetrics[name]
+		if strings.TrimSpace(expression) == "" {
+			return fmt.Errorf("%s: 式は必須項目です", path)
+		}
+		if !metricExpressionPattern.MatchString(expression) {
+			return fmt.Errorf("%s: 式にはメトリクス名、数値、+ - * / と括弧のみ使用できます: %s", path, expression)
+		}
+	}
+	return nil
+}
+
+// CalculatedMetricNames は計算指標の別名を出力順（昇順）で返す
+func (s Stream) CalculatedMetricNames() []string {
+	names := make([]string, 0, len(s.CalculatedMetrics))
+	for name := range s.CalculatedMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validatePaging はpage_sizeとmax_rowsの妥当性を検証する
+func (c *ConfigServiceImpl) validatePaging(stream Stream, streamPath string) error {
+	if stream.PageSize < 0 || stream.PageSize > MaxPageSize {
+		return fmt.Errorf("%s.page_size は 0（既定値）または 1 から %d の範囲で指定してください: %d", streamPath, MaxPageSize, stream.PageSize)
+	}
+	if stream.MaxRows < 0 {
+		return fmt.Errorf("%s.max_rows は 0 以上で指定してください: %d", streamPath, stream.MaxRows)
+	}
+	return nil
+}
+
+// validateOrderBy はorder_byとlimitの妥当性を検証する
+func (c *ConfigServiceImpl) validateOrderBy(stream Stream, streamPath string) error {
+	if stream.Limit < 0 {
+		return fmt.Errorf("%s.limit は 0 以上で指定してください: %d", streamPath, stream.Limit)
+	}
+
+	for k, order := range stream.OrderBy {
+		path := fmt.Sprintf("%s.order_by[%d]", streamPath, k)
+		switch {
+		case contains(stream.Dimensions, order.Field):
+			if _, ok := DimensionOrderTypes[order.Type]; order.Type != "" && !ok {
+				return fmt.Errorf("%s.type が不正です（%s のいずれかを指定してください）: %s", path, joinKeys(DimensionOrderTypes), order.Type)
 			}
-			if len(stream.Metrics) == 0 {
-				return fmt.Errorf("properties[%d].streams[%d].metrics は必須項目です", i, j)
+		case contains(stream.Metrics, order.Field) || stream.CalculatedMetrics[order.Field] != "":
+			if order.Type != "" && order.Type != "numeric" {
+				return fmt.Errorf("%s.type: メトリクスの並び順は numeric のみ指定できます: %s", path, order.Type)
 			}
+		default:
+			return fmt.Errorf("%s.field はこのストリームの dimensions または metrics に含まれている必要があります: %s", path, order.Field)
+		}
+	}
 
-			// 有効なメトリクスの検証
-			validMetrics := map[string]bool{
-				"sessions":               true,
-				"activeUsers":            true,
-				"newUsers":               true,
-				"averageSessionDuration": true,
-			}
+	return nil
+}
 
-			for _, metric := range stream.Metrics {
-				if !validMetrics[metric] {
-					return fmt.Errorf("properties[%d].streams[%d] に無効なメトリクスが含まれています: %s", i, j, metric)
-				}
-			}
+// validateComparison は比較期間の妥当性とディメンションとの組み合わせを検証する
+func (c *ConfigServiceImpl) validateComparison(config *Config, stream Stream, path string) error {
+	cmp := stream.Compare
+	if cmp == nil {
+		return nil
+	}
+
+	for _, dim := range stream.Dimensions {
+		if contains(ComparisonTimeDimensions, dim) {
+			return fmt.Errorf("%s: 日付・時間のディメンション %s は集計期間と比較期間で値が重ならないため compare と同時に指定できません（期間内の位置で比較する場合は nthDay などを使用してください）", path, dim)
 		}
 	}
 
+	if !contains(ComparisonTypes, cmp.Type) {
+		return fmt.Errorf("%s.type が不正です（%s のいずれかを指定してください）: %s", path, strings.Join(ComparisonTypes, ", "), cmp.Type)
+	}
+
+	if cmp.Type != "custom" {
+		if cmp.StartDate != "" || cmp.EndDate != "" {
+			return fmt.Errorf("%s: start_date / end_date は type が custom の場合のみ指定できます", path)
+		}
+		return nil
+	}
+
+	if strings.TrimSpace(cmp.StartDate) == "" || strings.TrimSpace(cmp.EndDate) == "" {
+		return fmt.Errorf("%s: type が custom の場合は start_date と end_date の両方を指定してください", path)
+	}
+
+	start, end, err := config.ComparisonBounds(cmp, time.Now())
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if start.After(end) {
+		return fmt.Errorf("%s.start_date は end_date より前の日付である必要があります", path)
+	}
+
 	return nil
 }
 
+// validateChunkBy はchunk_byの妥当性と他の設定との組み合わせを検証する
+func (c *ConfigServiceImpl) validateChunkBy(stream Stream, streamPath string) error {
+	if stream.ChunkBy == "" {
+		return nil
+	}
+
+	if !contains(ChunkUnits, stream.ChunkBy) {
+		return fmt.Errorf("%s.chunk_by が不正です（%s のいずれかを指定してください）: %s", streamPath, strings.Join(ChunkUnits, ", "), stream.ChunkBy)
+	}
+	// 上位N件は期間ごとに適用されるため、分割後にまとめると正しい結果にならない
+	if stream.Limit > 0 {
+		return fmt.Errorf("%s: chunk_by と limit は同時に指定できません", streamPath)
+	}
+	if stream.Compare != nil {
+		return fmt.Errorf("%s: chunk_by と compare は同時に指定できません", streamPath)
+	}
+
+	return nil
+}
+
+// contains はスライスに値が含まれているかを判定する
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // validateBaseURL はbase_urlの妥当性を検証する
-func (c *ConfigServiceImpl) validateBaseURL(baseURL string, propertyIndex, streamIndex int) error {
+func (c *ConfigServiceImpl) validateBaseURL(baseURL, streamPath string) error {
 	// base_urlは省略可能なので、空文字列の場合は検証をスキップ
 	if strings.TrimSpace(baseURL) == "" {
 		return nil
@@ -233,10 +596,10 @@ func (c *ConfigServiceImpl) validateBaseURL(baseURL string, propertyIndex, strea
 	urlPattern := `^https?://[^\s/$.?#].[^\s]*$`
 	matched, err := regexp.MatchString(urlPattern, baseURL)
 	if err != nil {
-		return fmt.Errorf("properties[%d].streams[%d].base_url の検証中にエラーが発生しました: %w", propertyIndex, streamIndex, err)
+		return fmt.Errorf("%s.base_url の検証中にエラーが発生しました: %w", streamPath, err)
 	}
 	if !matched {
-		return fmt.Errorf("properties[%d].streams[%d].base_url の形式が不正です（http://またはhttps://で始まる有効なURLを入力してください）: %s", propertyIndex, streamIndex, baseURL)
+		return fmt.Errorf("%s.base_url の形式が不正です（http://またはhttps://で始まる有効なURLを入力してください）: %s", streamPath, baseURL)
 	}
 
 	return nil
@@ -328,7 +328,7 @@ func TestConfigServiceImpl_ValidateConfig(t *testing.T) {
 						{
 							ID:         "1234567",
 							Dimensions: []string{"date"},
-							Metrics:    []string{"invalidMetric"},
+							Metrics:    []string{"invalid metric!"},
 						},
 					},
 				},
@@ -341,6 +341,132 @@ func TestConfigServiceImpl_ValidateConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("任意のメトリクスと計算指標", func(t *testing.T) {
+		tests := []struct {
+			metrics    []string
+			calculated map[string]string
+			wantErr    bool
+		}{
+			{metrics: []string{"screenPageViews", "engagementRate", "keyEvents", "totalRevenue", "customEvent:reading_time"}},
+			{metrics: []string{"sessions"}, calculated: map[string]string{"pages_per_session": "screenPageViews/sessions"}},
+			{calculated: map[string]string{"engaged_pct": "(engagedSessions / sessions) * 100"}},
+			{metrics: []string{"sessions"}, calculated: map[string]string{"sessions": "screenPageViews/sessions"}, wantErr: true},
+			{metrics: []string{"sessions"}, calculated: map[string]string{"pages per session": "screenPageViews/sessions"}, wantErr: true},
+			{metrics: []string{"sessions"}, calculated: map[string]string{"ratio": ""}, wantErr: true},
+			{metrics: []string{"sessions"}, calculated: map[string]string{"ratio": "sessions % 2"}, wantErr: true},
+		}
+
+		for _, tt := range tests {
+			config := &Config{
+				StartDate: "2023-01-01",
+				EndDate:   "2023-01-31",
+				Account:   "123456789",
+				Properties: []Property{{ID: "987654321", Streams: []Stream{{
+					ID:                "1234567",
+					Dimensions:        []string{"date"},
+					Metrics:           tt.metrics,
+					CalculatedMetrics: tt.calculated,
+				}}}},
+			}
+
+			err := service.ValidateConfig(config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("metrics=%v, calculated_metrics=%v: ValidateConfig() error = %v, wantErr %v", tt.metrics, tt.calculated, err, tt.wantErr)
+			}
+		}
+	})
+
+	t.Run("不正なpage_sizeとmax_rows", func(t *testing.T) {
+		for _, stream := range []Stream{
+			{ID: "1234567", Dimensions: []string{"date"}, Metrics: []string{"sessions"}, PageSize: MaxPageSize + 1},
+			{ID: "1234567", Dimensions: []string{"date"}, Metrics: []string{"sessions"}, PageSize: -1},
+			{ID: "1234567", Dimensions: []string{"date"}, Metrics: []string{"sessions"}, MaxRows: -1},
+		} {
+			config := &Config{
+				StartDate:  "2023-01-01",
+				EndDate:    "2023-01-31",
+				Account:    "123456789",
+				Properties: []Property{{ID: "987654321", Streams: []Stream{stream}}},
+			}
+
+			if err := service.ValidateConfig(config); err == nil {
+				t.Errorf("page_size=%d, max_rows=%d でエラーが発生しませんでした", stream.PageSize, stream.MaxRows)
+			}
+		}
+	})
+
+	t.Run("order_byの検証", func(t *testing.T) {
+		tests := []struct {
+			orderBy []OrderBy
+			limit   int
+			wantErr bool
+		}{
+			{orderBy: []OrderBy{{Field: "sessions", Desc: true}}, limit: 50},
+			{orderBy: []OrderBy{{Field: "date", Type: "numeric"}}},
+			{orderBy: []OrderBy{{Field: "country"}}, wantErr: true},
+			{orderBy: []OrderBy{{Field: "date", Type: "random"}}, wantErr: true},
+			{orderBy: []OrderBy{{Field: "sessions", Type: "alphanumeric"}}, wantErr: true},
+			{limit: -1, wantErr: true},
+		}
+
+		for _, tt := range tests {
+			config := &Config{
+				StartDate: "2023-01-01",
+				EndDate:   "2023-01-31",
+				Account:   "123456789",
+				Properties: []Property{{ID: "987654321", Streams: []Stream{{
+					ID:         "1234567",
+					Dimensions: []string{"date"},
+					Metrics:    []string{"sessions"},
+					OrderBy:    tt.orderBy,
+					Limit:      tt.limit,
+				}}}},
+			}
+
+			err := service.ValidateConfig(config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("order_by=%+v, limit=%d: ValidateConfig() error = %v, wantErr %v", tt.orderBy, tt.limit, err, tt.wantErr)
+			}
+		}
+	})
+
+	t.Run("chunk_byの検証", func(t *testing.T) {
+		tests := []struct {
+			chunkBy string
+			limit   int
+			compare *Comparison
+			wantErr bool
+		}{
+			{chunkBy: "day"},
+			{chunkBy: "week"},
+			{chunkBy: "month"},
+			{chunkBy: "year", wantErr: true},
+			{chunkBy: "month", limit: 50, wantErr: true},
+			{chunkBy: "month", compare: &Comparison{Type: "previous_year"}, wantErr: true},
+		}
+
+		for _, tt := range tests {
+			config := &Config{
+				StartDate: "2023-01-01",
+				EndDate:   "2023-12-31",
+				Account:   "123456789",
+				Properties: []Property{{ID: "987654321", Streams: []Stream{{
+					ID:         "1234567",
+					Dimensions: []string{"date"},
+					Metrics:    []string{"sessions"},
+					ChunkBy:    tt.chunkBy,
+					Limit:      tt.limit,
+					Compare:    tt.compare,
+				}}}},
+			}
+
+			err := service.ValidateConfig(config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("chunk_by=%s, limit=%d, compare=%+v: ValidateConfig() error = %v, wantErr %v", tt.chunkBy, tt.limit, tt.compare, err, tt.wantErr)
+			}
+		}
+	})
+
 	t.Run("開始日が終了日より後", func(t *testing.T) {
 		config := &Config{
 			StartDate: "2023-02-01",
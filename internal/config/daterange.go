@@ -0,0 +1,213 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// DateLayout は設定ファイルとGA4 APIで使用する日付形式
+const DateLayout = "2006-01-02"
+
+// NamedDateRanges はdate_range に指定できる名前付き期間の一覧
+var NamedDateRanges = []string{
+	"last_7_days",   // 7日前から昨日まで
+	"last_week",     // 先週の月曜日から日曜日まで
+	"last_month",    // 先月の1日から末日まで
+	"month_to_date", // 今月1日から今日まで
+	"last_quarter",  // 前四半期の初日から末日まで
+	"year_to_date",  // 今年の1月1日から今日まで
+}
+
+// dateFormatHint は日付形式エラーで表示する入力例
+const dateFormatHint = "YYYY-MM-DD, today, yesterday, NdaysAgo のいずれかで入力してください"
+
+// daysAgoPattern はGA4の相対日付（NdaysAgo）の形式
+var daysAgoPattern = regexp.MustCompile(`^(\d+)daysAgo$`)
+
+// ResolveDate は日付表現（YYYY-MM-DD, today, yesterday, NdaysAgo）を基準日時nowから解決する
+func ResolveDate(value string, now time.Time) (time.Time, error) {
+	today := truncateToDay(now)
+
+	switch value {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if m := daysAgoPattern.FindStringSubmatch(value); m != nil {
+		days, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("相対日付の日数が不正です: %s", value)
+		}
+		return today.AddDate(0, 0, -days), nil
+	}
+
+	date, err := time.ParseInLocation(DateLayout, value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("日付の形式が不正です: %s", value)
+	}
+	return date, nil
+}
+
+// ResolveNamedDateRange は名前付き期間を基準日時nowから開始日と終了日に解決する
+func ResolveNamedDateRange(name string, now time.Time) (time.Time, time.Time, error) {
+	today := truncateToDay(now)
+
+	switch name {
+	case "last_7_days":
+		return today.AddDate(0, 0, -7), today.AddDate(0, 0, -1), nil
+	case "last_week":
+		// 月曜日始まりの週として扱う
+		offset := (int(today.Weekday()) + 6) % 7
+		thisMonday := today.AddDate(0, 0, -offset)
+		return thisMonday.AddDate(0, 0, -7), thisMonday.AddDate(0, 0, -1), nil
+	case "last_month":
+		firstOfMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+		return firstOfMonth.AddDate(0, -1, 0), firstOfMonth.AddDate(0, 0, -1), nil
+	case "month_to_date":
+		firstOfMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+		return firstOfMonth, today, nil
+	case "last_quarter":
+		quarterStartMonth := time.Month((int(today.Month())-1)/3*3 + 1)
+		firstOfQuarter := time.Date(today.Year(), quarterStartMonth, 1, 0, 0, 0, 0, today.Location())
+		return firstOfQuarter.AddDate(0, -3, 0), firstOfQuarter.AddDate(0, 0, -1), nil
+	case "year_to_date":
+		return time.Date(today.Year(), time.January, 1, 0, 0, 0, 0, today.Location()), today, nil
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("date_range が不正です（%v のいずれかを指定してください）: %s", NamedDateRanges, name)
+}
+
+// Location はtimezoneで指定されたタイムゾーンを返す（省略時はローカルタイムゾーン）
+func (c *Config) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("timezone が不正です: %s", c.Timezone)
+	}
+	return loc, nil
+}
+
+// DateBounds は設定の期間をtimezoneにおける基準日時nowから解決して返す
+func (c *Config) DateBounds(now time.Time) (time.Time, time.Time, error) {
+	loc, err := c.Location()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	now = now.In(loc)
+
+	if c.DateRange != "" {
+		return ResolveNamedDateRange(c.DateRange, now)
+	}
+
+	start, err := ResolveDate(c.StartDate, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("start_date の形式が不正です（%s）: %s", dateFormatHint, c.StartDate)
+	}
+	end, err := ResolveDate(c.EndDate, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("end_date の形式が不正です（%s）: %s", dateFormatHint, c.EndDate)
+	}
+	return start, end, nil
+}
+
+// ComparisonBounds は比較期間を集計期間と基準日時nowから解決して返す
+func (c *Config) ComparisonBounds(cmp *Comparison, now time.Time) (time.Time, time.Time, error) {
+	start, end, err := c.DateBounds(now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	switch cmp.Type {
+	case "previous_period":
+		days := int(end.Sub(start).Hours()/24+0.5) + 1
+		prevEnd := start.AddDate(0, 0, -1)
+		return prevEnd.AddDate(0, 0, -(days - 1)), prevEnd, nil
+	case "previous_year":
+		return start.AddDate(-1, 0, 0), end.AddDate(-1, 0, 0), nil
+	case "custom":
+		loc, err := c.Location()
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		now = now.In(loc)
+
+		cmpStart, err := ResolveDate(cmp.StartDate, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("start_date の形式が不正です（%s）: %s", dateFormatHint, cmp.StartDate)
+		}
+		cmpEnd, err := ResolveDate(cmp.EndDate, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("end_date の形式が不正です（%s）: %s", dateFormatHint, cmp.EndDate)
+		}
+		return cmpStart, cmpEnd, nil
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("比較期間の種類が不正です: %s", cmp.Type)
+}
+
+// ComparisonDates は比較期間を解決してYYYY-MM-DD形式の開始日と終了日を返す
+func (c *Config) ComparisonDates(cmp *Comparison, now time.Time) (string, string, error) {
+	start, end, err := c.ComparisonBounds(cmp, now)
+	if err != nil {
+		return "", "", err
+	}
+	return start.Format(DateLayout), end.Format(DateLayout), nil
+}
+
+// ResolveDates は相対日付や名前付き期間を基準日時nowにおける具体的な日付に解決し、
+// StartDate と EndDate をYYYY-MM-DD形式で上書きする
+// 各ストリームの比較期間も解決し、type を custom とした具体的な期間に書き換える
+// 実行中に日付が変わっても期間がずれないよう、実行開始時に一度だけ呼び出す
+func (c *Config) ResolveDates(now time.Time) error {
+	// 比較期間は解決前の集計期間を基準にするため先に解決する
+	for i := range c.Properties {
+		for j := range c.Properties[i].Streams {
+			cmp := c.Properties[i].Streams[j].Compare
+			if cmp == nil {
+				continue
+			}
+			start, end, err := c.ComparisonDates(cmp, now)
+			if err != nil {
+				return err
+			}
+			cmp.Type = "custom"
+			cmp.StartDate = start
+			cmp.EndDate = end
+		}
+	}
+
+	start, end, err := c.DateBounds(now)
+	if err != nil {
+		return err
+	}
+
+	c.StartDate = start.Format(DateLayout)
+	c.EndDate = end.Format(DateLayout)
+	c.DateRange = ""
+	return nil
+}
+
+// truncateToDay は日時をそのタイムゾーンにおける0時に切り捨てる
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,279 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+// 2024-05-15（水曜日）を基準日とする
+var testNow = time.Date(2024, time.May, 15, 10, 30, 0, 0, time.UTC)
+
+func TestResolveDate(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "2024-01-31", want: "2024-01-31"},
+		{value: "today", want: "2024-05-15"},
+		{value: "yesterday", want: "2024-05-14"},
+		{value: "0daysAgo", want: "2024-05-15"},
+		{value: "30daysAgo", want: "2024-04-15"},
+		{value: "2024/01/31", wantErr: true},
+		{value: "daysAgo", wantErr: true},
+		{value: "tomorrow", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ResolveDate(tt.value, testNow)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveDate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Format(DateLayout) != tt.want {
+				t.Errorf("ResolveDate(%q) = %s, want %s", tt.value, got.Format(DateLayout), tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveNamedDateRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		now       time.Time
+		wantStart string
+		wantEnd   string
+	}{
+		{name: "last_7_days", now: testNow, wantStart: "2024-05-08", wantEnd: "2024-05-14"},
+		{name: "last_week", now: testNow, wantStart: "2024-05-06", wantEnd: "2024-05-12"},
+		{name: "last_week", now: time.Date(2024, time.May, 13, 0, 0, 0, 0, time.UTC), wantStart: "2024-05-06", wantEnd: "2024-05-12"},
+		{name: "last_week", now: time.Date(2024, time.May, 19, 0, 0, 0, 0, time.UTC), wantStart: "2024-05-06", wantEnd: "2024-05-12"},
+		{name: "last_month", now: testNow, wantStart: "2024-04-01", wantEnd: "2024-04-30"},
+		{name: "last_month", now: time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC), wantStart: "2024-02-01", wantEnd: "2024-02-29"},
+		{name: "month_to_date", now: testNow, wantStart: "2024-05-01", wantEnd: "2024-05-15"},
+		{name: "last_quarter", now: testNow, wantStart: "2024-01-01", wantEnd: "2024-03-31"},
+		{name: "last_quarter", now: time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC), wantStart: "2023-10-01", wantEnd: "2023-12-31"},
+		{name: "year_to_date", now: testNow, wantStart: "2024-01-01", wantEnd: "2024-05-15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.now.Format(DateLayout), func(t *testing.T) {
+			start, end, err := ResolveNamedDateRange(tt.name, tt.now)
+			if err != nil {
+				t.Fatalf("ResolveNamedDateRange() error = %v", err)
+			}
+			if start.Format(DateLayout) != tt.wantStart || end.Format(DateLayout) != tt.wantEnd {
+				t.Errorf("ResolveNamedDateRange(%s) = %s - %s, want %s - %s",
+					tt.name, start.Format(DateLayout), end.Format(DateLayout), tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+
+	if _, _, err := ResolveNamedDateRange("last_decade", testNow); err == nil {
+		t.Error("不正な名前付き期間でエラーが発生しませんでした")
+	}
+}
+
+func TestConfig_ResolveDates(t *testing.T) {
+	t.Run("タイムゾーンを考慮して解決する", func(t *testing.T) {
+		// UTCでは5月15日 20:00、東京では5月16日 05:00
+		now := time.Date(2024, time.May, 15, 20, 0, 0, 0, time.UTC)
+		config := &Config{StartDate: "7daysAgo", EndDate: "today", Timezone: "Asia/Tokyo"}
+
+		if err := config.ResolveDates(now); err != nil {
+			t.Fatalf("ResolveDates() error = %v", err)
+		}
+		if config.StartDate != "2024-05-09" || config.EndDate != "2024-05-16" {
+			t.Errorf("resolved = %s - %s, want 2024-05-09 - 2024-05-16", config.StartDate, config.EndDate)
+		}
+	})
+
+	t.Run("名前付き期間を解決する", func(t *testing.T) {
+		config := &Config{DateRange: "last_month"}
+
+		if err := config.ResolveDates(testNow); err != nil {
+			t.Fatalf("ResolveDates() error = %v", err)
+		}
+		if config.StartDate != "2024-04-01" || config.EndDate != "2024-04-30" || config.DateRange != "" {
+			t.Errorf("resolved = %+v, want 2024-04-01 - 2024-04-30", config)
+		}
+	})
+
+	t.Run("不正なタイムゾーン", func(t *testing.T) {
+		config := &Config{StartDate: "today", EndDate: "today", Timezone: "Mars/Olympus"}
+		if err := config.ResolveDates(testNow); err == nil {
+			t.Error("不正なタイムゾーンでエラーが発生しませんでした")
+		}
+	})
+}
+
+func TestValidateConfig_DateRange(t *testing.T) {
+	service := &ConfigServiceImpl{}
+	properties := []Property{{ID: "987654321", Streams: []Stream{{ID: "1234567", Dimensions: []string{"date"}, Metrics: []string{"sessions"}}}}}
+
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr bool
+	}{
+		{name: "相対日付", config: &Config{StartDate: "28daysAgo", EndDate: "yesterday"}},
+		{name: "名前付き期間", config: &Config{DateRange: "last_quarter", Timezone: "Asia/Tokyo"}},
+		{name: "名前付き期間と開始日の同時指定", config: &Config{DateRange: "last_week", StartDate: "2024-01-01"}, wantErr: true},
+		{name: "不正な名前付き期間", config: &Config{DateRange: "last_century"}, wantErr: true},
+		{name: "相対日付の順序が逆", config: &Config{StartDate: "yesterday", EndDate: "7daysAgo"}, wantErr: true},
+		{name: "終了日なし", config: &Config{StartDate: "yesterday"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Account = "123456789"
+			tt.config.Properties = properties
+
+			err := service.ValidateConfig(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfig_ComparisonBounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    *Config
+		cmp       *Comparison
+		wantStart string
+		wantEnd   string
+	}{
+		{
+			name:      "直前の期間",
+			config:    &Config{StartDate: "2024-03-01", EndDate: "2024-03-31"},
+			cmp:       &Comparison{Type: "previous_period"},
+			wantStart: "2024-01-30",
+			wantEnd:   "2024-02-29",
+		},
+		{
+			name:      "直前の期間（名前付き期間）",
+			config:    &Config{DateRange: "last_7_days"},
+			cmp:       &Comparison{Type: "previous_period"},
+			wantStart: "2024-05-01",
+			wantEnd:   "2024-05-07",
+		},
+		{
+			name:      "前年同期間",
+			config:    &Config{StartDate: "2024-02-01", EndDate: "2024-02-29"},
+			cmp:       &Comparison{Type: "previous_year"},
+			wantStart: "2023-02-01",
+			wantEnd:   "2023-03-01",
+		},
+		{
+			name:      "期間を明示",
+			config:    &Config{StartDate: "2024-05-01", EndDate: "2024-05-14"},
+			cmp:       &Comparison{Type: "custom", StartDate: "30daysAgo", EndDate: "2024-04-30"},
+			wantStart: "2024-04-15",
+			wantEnd:   "2024-04-30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := tt.config.ComparisonDates(tt.cmp, testNow)
+			if err != nil {
+				t.Fatalf("ComparisonDates() error = %v", err)
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("ComparisonDates() = %s - %s, want %s - %s", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestConfig_ResolveDates_Comparison(t *testing.T) {
+	config := &Config{
+		DateRange: "last_month",
+		Properties: []Property{{
+			ID: "987654321",
+			Streams: []Stream{
+				{ID: "1", Compare: &Comparison{Type: "previous_year"}},
+				{ID: "2"},
+			},
+		}},
+	}
+
+	if err := config.ResolveDates(testNow); err != nil {
+		t.Fatalf("ResolveDates() error = %v", err)
+	}
+
+	cmp := config.Properties[0].Streams[0].Compare
+	if cmp.Type != "custom" || cmp.StartDate != "2023-04-01" || cmp.EndDate != "2023-04-30" {
+		t.Errorf("resolved comparison = %+v, want custom 2023-04-01 - 2023-04-30", cmp)
+	}
+	if config.Properties[0].Streams[1].Compare != nil {
+		t.Error("比較期間のないストリームに比較期間が設定されました")
+	}
+}
+
+func TestValidateConfig_Comparison(t *testing.T) {
+	service := &ConfigServiceImpl{}
+
+	tests := []struct {
+		name       string
+		cmp        *Comparison
+		dimensions []string // 省略時は pagePath
+		wantErr    bool
+	}{
+		{name: "直前の期間", cmp: &Comparison{Type: "previous_period"}},
+		{name: "前年同期間", cmp: &Comparison{Type: "previous_year"}},
+		{name: "期間を明示", cmp: &Comparison{Type: "custom", StartDate: "2023-01-01", EndDate: "2023-01-31"}},
+		{name: "不正な種類", cmp: &Comparison{Type: "last_decade"}, wantErr: true},
+		{name: "customで終了日なし", cmp: &Comparison{Type: "custom", StartDate: "2023-01-01"}, wantErr: true},
+		{name: "custom以外で日付指定", cmp: &Comparison{Type: "previous_year", StartDate: "2023-01-01"}, wantErr: true},
+		{name: "customで日付の順序が逆", cmp: &Comparison{Type: "custom", StartDate: "2023-02-01", EndDate: "2023-01-01"}, wantErr: true},
+		{name: "期間内の位置で比較", cmp: &Comparison{Type: "previous_period"}, dimensions: []string{"nthDay", "dayOfWeek"}},
+		{name: "dateディメンション", cmp: &Comparison{Type: "previous_period"}, dimensions: []string{"date", "pagePath"}, wantErr: true},
+		{name: "yearMonthディメンション", cmp: &Comparison{Type: "previous_year"}, dimensions: []string{"yearMonth"}, wantErr: true},
+		{name: "weekディメンション", cmp: &Comparison{Type: "previous_year"}, dimensions: []string{"week"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dimensions := tt.dimensions
+			if dimensions == nil {
+				dimensions = []string{"pagePath"}
+			}
+			config := &Config{
+				StartDate: "2024-01-01",
+				EndDate:   "2024-01-31",
+				Account:   "123456789",
+				Properties: []Property{{
+					ID: "987654321",
+					Streams: []Stream{{
+						ID:         "1234567",
+						Dimensions: dimensions,
+						Metrics:    []string{"sessions"},
+						Compare:    tt.cmp,
+					}},
+				}},
+			}
+
+			err := service.ValidateConfig(config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
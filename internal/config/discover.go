@@ -0,0 +1,230 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// discoveredConfigHeader は ga discover で新規に作成する設定ファイルの先頭のコメント
+const discoveredConfigHeader = `# ga discover で作成した設定ファイル
+# 各ストリームの dimensions と metrics は必要に応じて変更してください
+`
+
+// ga discover で新規に作成する設定ファイルの集計期間（直近7日間）
+const (
+	discoveredStartDate = "7daysAgo"
+	discoveredEndDate   = "yesterday"
+)
+
+// SaveDiscovered はAdmin APIで取得したプロパティとストリームを設定ファイルに反映し、変更内容を返す
+// ファイルが存在しない場合は新規に作成する。存在する場合は未登録のプロパティ・ストリームを追加し、
+// account と base_url が未設定の場合は補完する（既存の値とコメントは保持し、元のファイルは .bak に保存する）
+func (c *ConfigServiceImpl) SaveDiscovered(path, account string, properties []Property) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c.createDiscovered(path, account, properties)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("YAML形式が不正です: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("設定ファイル '%s' の形式が不正です（トップレベルがマッピングではありません）", path)
+	}
+
+	changes, err := mergeDiscovered(doc.Content[0], account, properties)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	updated, err := encodeYAML(&doc)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path+".bak", data, 0644); err != nil {
+		return nil, fmt.Errorf("設定ファイルのバックアップ '%s' の作成に失敗しました: %w", path+".bak", err)
+	}
+	if err := os.WriteFile(path, updated, 0644); err != nil {
+		return nil, fmt.Errorf("設定ファイル '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	return changes, nil
+}
+
+// createDiscovered は取得したプロパティとストリームから設定ファイルを新規に作成する
+func (c *ConfigServiceImpl) createDiscovered(path, account string, properties []Property) ([]string, error) {
+	config := &Config{
+		StartDate:  discoveredStartDate,
+		EndDate:    discoveredEndDate,
+		Account:    account,
+		Properties: properties,
+	}
+
+	data, err := encodeYAML(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, append([]byte(discoveredConfigHeader), data...), 0644); err != nil {
+		return nil, fmt.Errorf("設定ファイル '%s' の書き込みに失敗しました: %w", path, err)
+	}
+
+	changes := []string{fmt.Sprintf("設定ファイル '%s' を作成しました", path)}
+	for _, property := range properties {
+		changes = append(changes, fmt.Sprintf("プロパティ %s（ストリーム %d 件）を追加しました", property.ID, len(property.Streams)))
+	}
+	return changes, nil
+}
+
+// mergeDiscovered は設定ファイルのトップレベルのマッピングに取得したプロパティとストリームを反映する
+func mergeDiscovered(root *yaml.Node, account string, properties []Property) ([]string, error) {
+	var changes []string
+
+	if node := mappingValue(root, "account"); account != "" && (node == nil || node.Value == "") {
+		if err := setMappingValue(root, "account", account); err != nil {
+			return nil, err
+		}
+		changes = append(changes, fmt.Sprintf("account を %s に設定しました", account))
+	}
+
+	propertiesNode := mappingValue(root, "properties")
+	if propertiesNode != nil && propertiesNode.Kind == yaml.ScalarNode && propertiesNode.Value == AllPropertiesKeyword {
+		return nil, fmt.Errorf("properties: %s の設定ファイルにはプロパティを追加できません（プロパティは実行時に解決されます）", AllPropertiesKeyword)
+	}
+	if propertiesNode == nil || propertiesNode.Kind != yaml.SequenceNode {
+		propertiesNode = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if err := setMappingValue(root, "properties", propertiesNode); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, property := range properties {
+		propertyNode := findItem(propertiesNode, "property", property.ID)
+		if propertyNode == nil {
+			if err := appendItem(propertiesNode, property); err != nil {
+				return nil, err
+			}
+			changes = append(changes, fmt.Sprintf("プロパティ %s（ストリーム %d 件）を追加しました", property.ID, len(property.Streams)))
+			continue
+		}
+
+		streamsNode := mappingValue(propertyNode, "streams")
+		if streamsNode == nil || streamsNode.Kind != yaml.SequenceNode {
+			streamsNode = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			if err := setMappingValue(propertyNode, "streams", streamsNode); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, stream := range property.Streams {
+			streamNode := findItem(streamsNode, "stream", stream.ID)
+			if streamNode == nil {
+				if err := appendItem(streamsNode, stream); err != nil {
+					return nil, err
+				}
+				changes = append(changes, fmt.Sprintf("プロパティ %s にストリーム %s を追加しました", property.ID, stream.ID))
+				continue
+			}
+
+			if node := mappingValue(streamNode, "base_url"); stream.BaseURL != "" && (node == nil || node.Value == "") {
+				if err := setMappingValue(streamNode, "base_url", stream.BaseURL); err != nil {
+					return nil, err
+				}
+				changes = append(changes, fmt.Sprintf("ストリーム %s の base_url を %s に設定しました", stream.ID, stream.BaseURL))
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// mappingValue はマッピングノードのキーに対応する値のノードを返す（存在しない場合はnil）
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue はマッピングノードのキーに値を設定する（キーが存在しない場合は末尾に追加する）
+func setMappingValue(mapping *yaml.Node, key string, value any) error {
+	node, ok := value.(*yaml.Node)
+	if !ok {
+		node = &yaml.Node{}
+		if err := node.Encode(value); err != nil {
+			return fmt.Errorf("%s の変換に失敗しました: %w", key, err)
+		}
+	}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			// 既存の値に付いたコメントは引き継ぐ
+			node.LineComment = mapping.Content[i+1].LineComment
+			mapping.Content[i+1] = node
+			return nil
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, node)
+	return nil
+}
+
+// findItem はシーケンスノードからキーの値がvalueのマッピングを探す（存在しない場合はnil）
+func findItem(sequence *yaml.Node, key, value string) *yaml.Node {
+	for _, item := range sequence.Content {
+		if node := mappingValue(item, key); node != nil && node.Value == value {
+			return item
+		}
+	}
+	return nil
+}
+
+// appendItem はシーケンスノードの末尾に値を追加する
+func appendItem(sequence *yaml.Node, value any) error {
+	node := &yaml.Node{}
+	if err := node.Encode(value); err != nil {
+		return fmt.Errorf("設定の変換に失敗しました: %w", err)
+	}
+	sequence.Content = append(sequence.Content, node)
+	return nil
+}
+
+// encodeYAML は値を2スペースのインデントのYAMLに変換する
+func encodeYAML(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return nil, fmt.Errorf("YAMLへの変換に失敗しました: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("YAMLへの変換に失敗しました: %w", err)
+	}
+	return buf.Bytes(), nil
+}
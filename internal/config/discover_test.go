@@ -0,0 +1,189 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// discoveredProperties はテスト用のAdmin APIで取得したプロパティ
+func discoveredProperties() []Property {
+	return []Property{
+		{
+			ID: "111",
+			Streams: []Stream{
+				{ID: "1001", BaseURL: "https://www.example.com", Dimensions: []string{"date", "pagePath"}, Metrics: []string{"sessions"}},
+				{ID: "1002", BaseURL: "https://blog.example.com", Dimensions: []string{"date", "pagePath"}, Metrics: []string{"sessions"}},
+			},
+		},
+		{
+			ID: "222",
+			Streams: []Stream{
+				{ID: "2001", Dimensions: []string{"date", "unifiedScreenName"}, Metrics: []string{"sessions"}},
+			},
+		},
+	}
+}
+
+func TestConfigService_SaveDiscovered_Create(t *testing.T) {
+	service := NewConfigService()
+	path := filepath.Join(t.TempDir(), "ga.yaml")
+
+	changes, err := service.SaveDiscovered(path, "100", discoveredProperties())
+	if err != nil {
+		t.Fatalf("SaveDiscovered() error = %v", err)
+	}
+	if len(changes) != 3 {
+		t.Errorf("changes = %v, want 3 entries", changes)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !strings.HasPrefix(string(data), "# ga discover で作成した設定ファイル") {
+		t.Errorf("設定ファイルの先頭にコメントがありません:\n%s", data)
+	}
+
+	config, err := service.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("作成した設定ファイルの読み込みに失敗しました: %v", err)
+	}
+	if config.Account != "100" || config.StartDate != "7daysAgo" || config.EndDate != "yesterday" {
+		t.Errorf("config = %+v", config)
+	}
+	if len(config.Properties) != 2 || len(config.Properties[0].Streams) != 2 {
+		t.Fatalf("properties = %+v", config.Properties)
+	}
+	if got := config.Properties[0].Streams[1].BaseURL; got != "https://blog.example.com" {
+		t.Errorf("base_url = %q", got)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Errorf("新規作成時にバックアップが作成されています: %v", err)
+	}
+}
+
+func TestConfigService_SaveDiscovered_Update(t *testing.T) {
+	service := NewConfigService()
+	path := filepath.Join(t.TempDir(), "ga.yaml")
+
+	original := `# レポート設定
+start_date: "2024-01-01"
+end_date: "2024-01-31"
+account: ""
+properties:
+  - property: "111"
+    streams:
+      - stream: "1001" # メインサイト
+        dimensions:
+          - date
+        metrics:
+          - activeUsers
+      - stream: "1003"
+        base_url: https://legacy.example.com
+        dimensions:
+          - date
+        metrics:
+          - activeUsers
+`
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	changes, err := service.SaveDiscovered(path, "100", discoveredProperties())
+	if err != nil {
+		t.Fatalf("SaveDiscovered() error = %v", err)
+	}
+
+	wantChanges := []string{
+		"account を 100 に設定しました",
+		"ストリーム 1001 の base_url を https://www.example.com に設定しました",
+		"プロパティ 111 にストリーム 1002 を追加しました",
+		"プロパティ 222（ストリーム 1 件）を追加しました",
+	}
+	if strings.Join(changes, "\n") != strings.Join(wantChanges, "\n") {
+		t.Errorf("changes = %v, want %v", changes, wantChanges)
+	}
+
+	backup, err := os.ReadFile(path + ".bak")
+	if err != nil || string(backup) != original {
+		t.Errorf("バックアップの内容が元のファイルと異なります: %v\n%s", err, backup)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	for _, comment := range []string{"# レポート設定", "# メインサイト"} {
+		if !strings.Contains(string(data), comment) {
+			t.Errorf("コメント %q が保持されていません:\n%s", comment, data)
+		}
+	}
+
+	config, err := service.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("更新した設定ファイルの読み込みに失敗しました: %v", err)
+	}
+	if config.StartDate != "2024-01-01" || config.Account != "100" {
+		t.Errorf("config = %+v", config)
+	}
+	streams := config.Properties[0].Streams
+	if len(streams) != 3 {
+		t.Fatalf("streams = %+v", streams)
+	}
+	// 既存のストリームの設定は変更しない
+	if streams[0].BaseURL != "https://www.example.com" || strings.Join(streams[0].Metrics, ",") != "activeUsers" {
+		t.Errorf("stream 1001 = %+v", streams[0])
+	}
+	if streams[1].ID != "1003" || streams[1].BaseURL != "https://legacy.example.com" {
+		t.Errorf("stream 1003 = %+v", streams[1])
+	}
+	if streams[2].ID != "1002" || streams[2].BaseURL != "https://blog.example.com" {
+		t.Errorf("stream 1002 = %+v", streams[2])
+	}
+	if len(config.Properties) != 2 || config.Properties[1].ID != "222" {
+		t.Errorf("properties = %+v", config.Properties)
+	}
+
+	// 2回目は変更がないためファイルを書き換えない
+	if err := os.Remove(path + ".bak"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	changes, err = service.SaveDiscovered(path, "100", discoveredProperties())
+	if err != nil {
+		t.Fatalf("SaveDiscovered() error = %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("changes = %v, want none", changes)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Errorf("変更がない場合にバックアップが作成されています: %v", err)
+	}
+}
+
+func TestConfigService_SaveDiscovered_InvalidFile(t *testing.T) {
+	service := NewConfigService()
+	path := filepath.Join(t.TempDir(), "ga.yaml")
+	if err := os.WriteFile(path, []byte("- item\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := service.SaveDiscovered(path, "100", discoveredProperties()); err == nil {
+		t.Error("SaveDiscovered() error = nil, want error")
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FilterExpression はdimension_filter / metric_filter の式を表す構造体
+// and, or, not, または field を使った単一フィルタのいずれか1つを指定する
+type FilterExpression struct {
+	And []FilterExpression `yaml:"and,omitempty"`
+	Or  []FilterExpression `yaml:"or,omitempty"`
+	Not *FilterExpression  `yaml:"not,omitempty"`
+
+	// 単一フィルタ（field と、string / in_list / numeric / between のいずれか1つ）
+	Field   string         `yaml:"field,omitempty"`
+	String  *StringFilter  `yaml:"string,omitempty"`
+	InList  *InListFilter  `yaml:"in_list,omitempty"`
+	Numeric *NumericFilter `yaml:"numeric,omitempty"`
+	Between *BetweenFilter `yaml:"between,omitempty"`
+}
+
+// StringFilter は文字列の一致条件を表す構造体
+type StringFilter struct {
+	MatchType     string `yaml:"match_type,omitempty"` // 省略時は exact
+	Value         string `yaml:"value"`
+	CaseSensitive bool   `yaml:"case_sensitive,omitempty"`
+}
+
+// InListFilter は値のリストとの一致条件を表す構造体
+type InListFilter struct {
+	Values        []string `yaml:"values"`
+	CaseSensitive bool     `yaml:"case_sensitive,omitempty"`
+}
+
+// NumericFilter は数値の比較条件を表す構造体
+type NumericFilter struct {
+	Operation string   `yaml:"operation"`
+	Value     *float64 `yaml:"value"`
+}
+
+// BetweenFilter は数値の範囲条件を表す構造体（from, to を含む）
+type BetweenFilter struct {
+	From *float64 `yaml:"from"`
+	To   *float64 `yaml:"to"`
+}
+
+// StringMatchTypes はstring フィルタの match_type とGA4 APIの値の対応
+var StringMatchTypes = map[string]string{
+	"exact":          "EXACT",
+	"begins_with":    "BEGINS_WITH",
+	"ends_with":      "ENDS_WITH",
+	"contains":       "CONTAINS",
+	"full_regexp":    "FULL_REGEXP",
+	"partial_regexp": "PARTIAL_REGEXP",
+}
+
+// NumericOperations はnumeric フィルタの operation とGA4 APIの値の対応
+var NumericOperations = map[string]string{
+	"equal":                 "EQUAL",
+	"less_than":             "LESS_THAN",
+	"less_than_or_equal":    "LESS_THAN_OR_EQUAL",
+	"greater_than":          "GREATER_THAN",
+	"greater_than_or_equal": "GREATER_THAN_OR_EQUAL",
+}
+
+// validateFilterExpression はフィルタ式を再帰的に検証する
+// path はエラーメッセージに含めるYAML上のパス、isMetric はmetric_filterかどうか
+func validateFilterExpression(expr *FilterExpression, path string, isMetric bool) error {
+	if expr == nil {
+		return nil
+	}
+
+	isLeaf := expr.Field != "" || expr.String != nil || expr.InList != nil || expr.Numeric != nil || expr.Between != nil
+	kinds := 0
+	for _, set := range []bool{expr.And != nil, expr.Or != nil, expr.Not != nil, isLeaf} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return fmt.Errorf("%s には and, or, not, field のいずれか1つを指定してください", path)
+	}
+
+	switch {
+	case expr.And != nil:
+		return validateFilterGroup(expr.And, path+".and", isMetric)
+	case expr.Or != nil:
+		return validateFilterGroup(expr.Or, path+".or", isMetric)
+	case expr.Not != nil:
+		return validateFilterExpression(expr.Not, path+".not", isMetric)
+	}
+
+	return validateFilter(expr, path, isMetric)
+}
+
+// validateFilterGroup はand / or グループの各要素を検証する
+func validateFilterGroup(group []FilterExpression, path string, isMetric bool) error {
+	if len(group) == 0 {
+		return fmt.Errorf("%s には1つ以上の条件を指定してください", path)
+	}
+	for i := range group {
+		if err := validateFilterExpression(&group[i], fmt.Sprintf("%s[%d]", path, i), isMetric); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateFilter は単一フィルタを検証する
+func validateFilter(expr *FilterExpression, path string, isMetric bool) error {
+	if strings.TrimSpace(expr.Field) == "" {
+		return fmt.Errorf("%s.field は必須項目です", path)
+	}
+
+	count := 0
+	for _, set := range []bool{expr.String != nil, expr.InList != nil, expr.Numeric != nil, expr.Between != nil} {
+		if set {
+			count++
+		}
+	}
+	if count != 1 {
+		return fmt.Errorf("%s には string, in_list, numeric, between のいずれか1つを指定してください", path)
+	}
+
+	if isMetric && (expr.String != nil || expr.InList != nil) {
+		return fmt.Errorf("%s: metric_filter では numeric または between のみ使用できます", path)
+	}
+
+	switch {
+	case expr.String != nil:
+		if expr.String.MatchType != "" {
+			if _, ok := StringMatchTypes[expr.String.MatchType]; !ok {
+				return fmt.Errorf("%s.string.match_type が不正です（%s のいずれかを指定してください）: %s", path, joinKeys(StringMatchTypes), expr.String.MatchType)
+			}
+		}
+	case expr.InList != nil:
+		if len(expr.InList.Values) == 0 {
+			return fmt.Errorf("%s.in_list.values には1つ以上の値を指定してください", path)
+		}
+	case expr.Numeric != nil:
+		if _, ok := NumericOperations[expr.Numeric.Operation]; !ok {
+			return fmt.Errorf("%s.numeric.operation が不正です（%s のいずれかを指定してください）: %s", path, joinKeys(NumericOperations), expr.Numeric.Operation)
+		}
+		if expr.Numeric.Value == nil {
+			return fmt.Errorf("%s.numeric.value は必須項目です", path)
+		}
+	case expr.Between != nil:
+		if expr.Between.From == nil || expr.Between.To == nil {
+			return fmt.Errorf("%s.between には from と to の両方を指定してください", path)
+		}
+		if *expr.Between.From > *expr.Between.To {
+			return fmt.Errorf("%s.between.from は to 以下である必要があります", path)
+		}
+	}
+
+	return nil
+}
+
+// joinKeys はエラーメッセージ用にマップのキーをソートして連結する
+func joinKeys(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestFilterExpression_UnmarshalYAML(t *testing.T) {
+	input := `
+and:
+  - field: pagePath
+    string:
+      match_type: begins_with
+      value: /blog
+  - not:
+      field: country
+      in_list:
+        values: ["Japan", "United States"]
+`
+	var expr FilterExpression
+	if err := yaml.Unmarshal([]byte(input), &expr); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+
+	if len(expr.And) != 2 {
+		t.Fatalf("And length = %d, want 2", len(expr.And))
+	}
+	if expr.And[0].Field != "pagePath" || expr.And[0].String == nil || expr.And[0].String.MatchType != "begins_with" {
+		t.Errorf("And[0] = %+v, want pagePath begins_with", expr.And[0])
+	}
+	if expr.And[1].Not == nil || expr.And[1].Not.InList == nil || len(expr.And[1].Not.InList.Values) != 2 {
+		t.Errorf("And[1] = %+v, want not in_list", expr.And[1])
+	}
+
+	if err := validateFilterExpression(&expr, "dimension_filter", false); err != nil {
+		t.Errorf("validateFilterExpression() error = %v", err)
+	}
+}
+
+func TestValidateFilterExpression(t *testing.T) {
+	ten := 10.0
+	five := 5.0
+
+	tests := []struct {
+		name     string
+		expr     *FilterExpression
+		isMetric bool
+		wantPath string // 空の場合はエラーなしを期待
+	}{
+		{
+			name:     "数値フィルタ",
+			expr:     &FilterExpression{Field: "sessions", Numeric: &NumericFilter{Operation: "greater_than", Value: &ten}},
+			isMetric: true,
+		},
+		{
+			name:     "範囲フィルタ",
+			expr:     &FilterExpression{Field: "sessions", Between: &BetweenFilter{From: &five, To: &ten}},
+			isMetric: true,
+		},
+		{
+			name:     "field未指定",
+			expr:     &FilterExpression{String: &StringFilter{Value: "/blog"}},
+			wantPath: "f.field",
+		},
+		{
+			name: "and と field の同時指定",
+			expr: &FilterExpression{
+				Field: "pagePath",
+				And:   []FilterExpression{{Field: "pagePath", String: &StringFilter{Value: "/"}}},
+			},
+			wantPath: "f ",
+		},
+		{
+			name:     "空のorグループ",
+			expr:     &FilterExpression{Or: []FilterExpression{}},
+			wantPath: "f.or",
+		},
+		{
+			name: "ネストした不正なmatch_type",
+			expr: &FilterExpression{Or: []FilterExpression{
+				{Field: "pagePath", String: &StringFilter{Value: "/"}},
+				{Field: "pagePath", String: &StringFilter{MatchType: "starts", Value: "/"}},
+			}},
+			wantPath: "f.or[1].string.match_type",
+		},
+		{
+			name:     "不正なoperation",
+			expr:     &FilterExpression{Field: "sessions", Numeric: &NumericFilter{Operation: ">", Value: &ten}},
+			isMetric: true,
+			wantPath: "f.numeric.operation",
+		},
+		{
+			name:     "valueなし",
+			expr:     &FilterExpression{Field: "sessions", Numeric: &NumericFilter{Operation: "equal"}},
+			isMetric: true,
+			wantPath: "f.numeric.value",
+		},
+		{
+			name:     "from > to",
+			expr:     &FilterExpression{Field: "sessions", Between: &BetweenFilter{From: &ten, To: &five}},
+			isMetric: true,
+			wantPath: "f.between.from",
+		},
+		{
+			name:     "metric_filterで文字列フィルタ",
+			expr:     &FilterExpression{Field: "sessions", String: &StringFilter{Value: "1"}},
+			isMetric: true,
+			wantPath: "f: metric_filter",
+		},
+		{
+			name:     "空のin_list",
+			expr:     &FilterExpression{Not: &FilterExpression{Field: "country", InList: &InListFilter{}}},
+			wantPath: "f.not.in_list.values",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFilterExpression(tt.expr, "f", tt.isMetric)
+			if tt.wantPath == "" {
+				if err != nil {
+					t.Errorf("validateFilterExpression() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("エラーが発生しませんでした")
+			}
+			if !strings.HasPrefix(err.Error(), tt.wantPath) {
+				t.Errorf("error = %q, want prefix %q", err.Error(), tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_FilterPath(t *testing.T) {
+	service := &ConfigServiceImpl{}
+	config := &Config{
+		StartDate: "2023-01-01",
+		EndDate:   "2023-01-31",
+		Account:   "123456789",
+		Properties: []Property{
+			{
+				ID: "987654321",
+				Streams: []Stream{
+					{
+						ID:              "1234567",
+						Dimensions:      []string{"pagePath"},
+						Metrics:         []string{"sessions"},
+						DimensionFilter: &FilterExpression{Field: "pagePath"},
+					},
+				},
+			},
+		},
+	}
+
+	err := service.ValidateConfig(config)
+	if err == nil {
+		t.Fatal("不正なフィルタでエラーが発生しませんでした")
+	}
+	if !strings.HasPrefix(err.Error(), "properties[0].streams[0].dimension_filter") {
+		t.Errorf("error = %q, want YAML path prefix", err.Error())
+	}
+}
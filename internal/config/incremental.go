@@ -0,0 +1,102 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "fmt"
+
+// Incremental は前回の取得以降のデータのみを取得する差分取得の設定を表す構造体
+// ストリームごとに取得済みの最終日を状態ファイルに記録し、次回はその日以降を取得して出力ファイルに反映する
+type Incremental struct {
+	StateFile string `yaml:"state_file,omitempty"` // 状態ファイルのパス（省略時は出力ファイル名に .state.json を付けたファイル）
+
+	// OverlapDays は取得済みの最終日から遡って再取得する日数（省略時は3）
+	// GA4のデータは48〜72時間程度確定しないため、直近の日のデータを取得し直して置き換える
+	OverlapDays *int `yaml:"overlap_days,omitempty"`
+}
+
+const (
+	// DefaultOverlapDays は取得済みの最終日から遡って再取得する日数の既定値
+	DefaultOverlapDays = 3
+	// MaxOverlapDays は overlap_days に指定できる最大値
+	MaxOverlapDays = 30
+)
+
+// incrementalDimension は差分取得で既存の出力の行を置き換えるために必要なディメンション
+const incrementalDimension = "date"
+
+// OverlapDaysOrDefault は再取得する日数を返す
+func (i *Incremental) OverlapDaysOrDefault() int {
+	if i == nil || i.OverlapDays == nil {
+		return DefaultOverlapDays
+	}
+	return *i.OverlapDays
+}
+
+// StateFileOrDefault は状態ファイルのパスを返す
+func (i *Incremental) StateFileOrDefault(outputPath string) string {
+	if i != nil && i.StateFile != "" {
+		return i.StateFile
+	}
+	return outputPath + ".state.json"
+}
+
+// validateIncremental はincremental セクションと、差分取得できるストリームの設定かを検証する
+func (c *ConfigServiceImpl) validateIncremental(config *Config) error {
+	inc := config.Incremental
+	if inc == nil {
+		return nil
+	}
+
+	if days := inc.OverlapDaysOrDefault(); days < 0 || days > MaxOverlapDays {
+		return fmt.Errorf("incremental.overlap_days は0から%dの範囲で指定してください: %d", MaxOverlapDays, days)
+	}
+	if config.SplitBySchema() {
+		return fmt.Errorf("incremental と schema_mode: split は同時に指定できません")
+	}
+
+	for i, property := range config.Properties {
+		for j, stream := range property.Streams {
+			if err := validateIncrementalStream(stream, fmt.Sprintf("properties[%d].streams[%d]", i, j)); err != nil {
+				return err
+			}
+		}
+	}
+	if config.PropertiesAll && config.AllProperties != nil {
+		return validateIncrementalStream(config.AllProperties.Template, "all_properties.template")
+	}
+	return nil
+}
+
+// validateIncrementalStream はストリームが日ごとの行を置き換えられる設定かを検証する
+// 期間全体を集計する比較・ピボット・コホート・集計値・上位N件は、取得する期間が変わると結果が変わるため指定できない
+func validateIncrementalStream(stream Stream, streamPath string) error {
+	if !contains(stream.Dimensions, incrementalDimension) {
+		return fmt.Errorf("%s: incremental を指定した場合は dimensions に %s を含めてください", streamPath, incrementalDimension)
+	}
+
+	switch {
+	case stream.Compare != nil:
+		return fmt.Errorf("%s: incremental と compare は同時に指定できません", streamPath)
+	case stream.Pivot != nil:
+		return fmt.Errorf("%s: incremental と pivot は同時に指定できません", streamPath)
+	case stream.Cohort != nil:
+		return fmt.Errorf("%s: incremental と cohort は同時に指定できません", streamPath)
+	case len(stream.Aggregations) > 0:
+		return fmt.Errorf("%s: incremental と aggregations は同時に指定できません", streamPath)
+	case stream.Limit > 0:
+		return fmt.Errorf("%s: incremental と limit は同時に指定できません", streamPath)
+	}
+	return nil
+}
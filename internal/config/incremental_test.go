@@ -0,0 +1,99 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// incrementalTestConfig は差分取得のテスト用の設定を作成する
+func incrementalTestConfig(stream Stream) *Config {
+	stream.ID = "1234567"
+	return &Config{
+		StartDate:   "2024-01-01",
+		EndDate:     "2024-01-31",
+		Account:     "123456789",
+		Properties:  []Property{{ID: "987654321", Streams: []Stream{stream}}},
+		Incremental: &Incremental{},
+	}
+}
+
+func TestConfigService_ValidateIncremental(t *testing.T) {
+	base := Stream{Dimensions: []string{"date", "pagePath"}, Metrics: []string{"sessions"}}
+	overlap := func(days int) *int { return &days }
+
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr string
+	}{
+		{name: "有効な設定", modify: func(c *Config) {}},
+		{name: "overlap_days が0", modify: func(c *Config) { c.Incremental.OverlapDays = overlap(0) }},
+		{name: "overlap_days が負", modify: func(c *Config) { c.Incremental.OverlapDays = overlap(-1) }, wantErr: "incremental.overlap_days は0から30の範囲"},
+		{name: "overlap_days が大きすぎる", modify: func(c *Config) { c.Incremental.OverlapDays = overlap(31) }, wantErr: "incremental.overlap_days は0から30の範囲"},
+		{name: "date がない", modify: func(c *Config) { c.Properties[0].Streams[0].Dimensions = []string{"pagePath"} }, wantErr: "dimensions に date を含めてください"},
+		{name: "limit", modify: func(c *Config) { c.Properties[0].Streams[0].Limit = 10 }, wantErr: "incremental と limit は同時に指定できません"},
+		{name: "aggregations", modify: func(c *Config) { c.Properties[0].Streams[0].Aggregations = []string{"total"} }, wantErr: "incremental と aggregations は同時に指定できません"},
+		{name: "compare", modify: func(c *Config) { c.Properties[0].Streams[0].Compare = &Comparison{Type: "previous_period"} }, wantErr: "date は集計期間と比較期間で値が重ならないため compare と同時に指定できません"},
+		{name: "schema_mode: split", modify: func(c *Config) { c.SchemaMode = "split" }, wantErr: "incremental と schema_mode: split は同時に指定できません"},
+		{
+			name: "properties: all のテンプレートに date がない",
+			modify: func(c *Config) {
+				c.Properties = nil
+				c.PropertiesAll = true
+				c.AllProperties = &AllProperties{Template: Stream{Dimensions: []string{"pagePath"}, Metrics: []string{"sessions"}}}
+			},
+			wantErr: "all_properties.template: incremental を指定した場合は dimensions に date を含めてください",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := incrementalTestConfig(base)
+			tt.modify(config)
+
+			err := NewConfigService().ValidateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateConfig() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIncremental_Defaults(t *testing.T) {
+	var inc *Incremental
+	if got := inc.OverlapDaysOrDefault(); got != DefaultOverlapDays {
+		t.Errorf("OverlapDaysOrDefault() = %d, want %d", got, DefaultOverlapDays)
+	}
+	if got := inc.StateFileOrDefault("out/daily.csv"); got != "out/daily.csv.state.json" {
+		t.Errorf("StateFileOrDefault() = %s", got)
+	}
+
+	days := 0
+	inc = &Incremental{StateFile: "state.json", OverlapDays: &days}
+	if got := inc.OverlapDaysOrDefault(); got != 0 {
+		t.Errorf("OverlapDaysOrDefault() = %d, want 0", got)
+	}
+	if got := inc.StateFileOrDefault("out/daily.csv"); got != "state.json" {
+		t.Errorf("StateFileOrDefault() = %s", got)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DurationFormatSeconds は時間のメトリクスをAPIの値（秒、分など）のまま出力する（既定）
+	DurationFormatSeconds = "seconds"
+	// DurationFormatHMS は時間のメトリクスを hh:mm:ss 形式で出力する
+	DurationFormatHMS = "hms"
+)
+
+// MaxDecimalPlaces は decimal_places に指定できる最大値
+const MaxDecimalPlaces = 10
+
+// NumberFormat はCSV・表形式で出力するメトリクスの数値の書式を表す構造体
+// JSON出力は常に数値のまま出力するため、この設定の影響を受けない
+type NumberFormat struct {
+	Locale        string `yaml:"locale,omitempty"`         // 桁区切りと小数点の記号を決めるロケール（例: ja-JP, de-DE）。省略時は区切りなし
+	DecimalPlaces *int   `yaml:"decimal_places,omitempty"` // 小数のメトリクスを丸める桁数（省略時は丸めない）
+	Duration      string `yaml:"duration,omitempty"`       // 時間のメトリクスの書式（seconds または hms）
+}
+
+// NumberSeparators は数値の桁区切りと小数点の記号を表す構造体
+type NumberSeparators struct {
+	Grouping string
+	Decimal  string
+}
+
+// localeSeparators は言語ごとの桁区切りと小数点の記号
+// 空白で区切る言語は、表計算ソフトで数値が分割されないようノーブレークスペースを使う
+var localeSeparators = map[string]NumberSeparators{
+	"en": {Grouping: ",", Decimal: "."},
+	"ja": {Grouping: ",", Decimal: "."},
+	"zh": {Grouping: ",", Decimal: "."},
+	"ko": {Grouping: ",", Decimal: "."},
+	"de": {Grouping: ".", Decimal: ","},
+	"es": {Grouping: ".", Decimal: ","},
+	"it": {Grouping: ".", Decimal: ","},
+	"nl": {Grouping: ".", Decimal: ","},
+	"pt": {Grouping: ".", Decimal: ","},
+	"id": {Grouping: ".", Decimal: ","},
+	"tr": {Grouping: ".", Decimal: ","},
+	"fr": {Grouping: "\u00a0", Decimal: ","},
+	"ru": {Grouping: "\u00a0", Decimal: ","},
+	"pl": {Grouping: "\u00a0", Decimal: ","},
+	"sv": {Grouping: "\u00a0", Decimal: ","},
+}
+
+// LocaleSeparators はロケール（例: ja-JP, de_DE, fr）の桁区切りと小数点の記号を返す
+// 対応していないロケールの場合はfalseを返す
+func LocaleSeparators(locale string) (NumberSeparators, bool) {
+	language, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	separators, ok := localeSeparators[strings.ToLower(language)]
+	return separators, ok
+}
+
+// DurationOrDefault は時間のメトリクスの書式を返す
+func (f *NumberFormat) DurationOrDefault() string {
+	if f == nil || f.Duration == "" {
+		return DurationFormatSeconds
+	}
+	return f.Duration
+}
+
+// validateNumberFormat はnumber_format セクションを検証する
+func (c *ConfigServiceImpl) validateNumberFormat(f *NumberFormat) error {
+	if f == nil {
+		return nil
+	}
+
+	if f.Locale != "" {
+		if _, ok := LocaleSeparators(f.Locale); !ok {
+			return fmt.Errorf("number_format.locale: %s には対応していません（en, ja, de, fr などの言語で始まるロケールを指定してください）", f.Locale)
+		}
+	}
+	if f.DecimalPlaces != nil && (*f.DecimalPlaces < 0 || *f.DecimalPlaces > MaxDecimalPlaces) {
+		return fmt.Errorf("number_format.decimal_places は0から%dの範囲で指定してください: %d", MaxDecimalPlaces, *f.DecimalPlaces)
+	}
+	switch f.DurationOrDefault() {
+	case DurationFormatSeconds, DurationFormatHMS:
+	default:
+		return fmt.Errorf("number_format.duration には %s または %s を指定してください: %s", DurationFormatSeconds, DurationFormatHMS, f.Duration)
+	}
+
+	return nil
+}
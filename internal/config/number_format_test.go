@@ -0,0 +1,102 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig_NumberFormat(t *testing.T) {
+	service := &ConfigServiceImpl{}
+	two := 2
+	negative := -1
+
+	tests := []struct {
+		name    string
+		format  *NumberFormat
+		wantErr string
+	}{
+		{name: "number_formatなし", format: nil},
+		{name: "有効な設定", format: &NumberFormat{Locale: "de-DE", DecimalPlaces: &two, Duration: DurationFormatHMS}},
+		{name: "言語のみのロケール", format: &NumberFormat{Locale: "ja"}},
+		{
+			name:    "対応していないロケール",
+			format:  &NumberFormat{Locale: "xx-YY"},
+			wantErr: "number_format.locale: xx-YY には対応していません",
+		},
+		{
+			name:    "負の桁数",
+			format:  &NumberFormat{DecimalPlaces: &negative},
+			wantErr: "number_format.decimal_places は0から10の範囲",
+		},
+		{
+			name:    "不明な時間の書式",
+			format:  &NumberFormat{Duration: "minutes"},
+			wantErr: "number_format.duration には seconds または hms",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				StartDate: "2024-01-01",
+				EndDate:   "2024-01-31",
+				Account:   "123456789",
+				Properties: []Property{{
+					ID: "987654321",
+					Streams: []Stream{{
+						ID:         "1234567",
+						Dimensions: []string{"date"},
+						Metrics:    []string{"sessions"},
+					}},
+				}},
+				NumberFormat: tt.format,
+			}
+
+			err := service.ValidateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLocaleSeparators(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   NumberSeparators
+		wantOK bool
+	}{
+		{locale: "ja-JP", want: NumberSeparators{Grouping: ",", Decimal: "."}, wantOK: true},
+		{locale: "de_DE", want: NumberSeparators{Grouping: ".", Decimal: ","}, wantOK: true},
+		{locale: "FR", want: NumberSeparators{Grouping: "\u00a0", Decimal: ","}, wantOK: true},
+		{locale: "xx"},
+		{locale: ""},
+	}
+
+	for _, tt := range tests {
+		got, ok := LocaleSeparators(tt.locale)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("LocaleSeparators(%q) = %+v, %v, want %+v, %v", tt.locale, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
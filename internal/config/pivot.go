@@ -0,0 +1,102 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "fmt"
+
+// Pivot はピボット表（クロス集計）の行と列を表す構造体
+// rows と columns には dimensions に含まれるディメンションをすべて振り分ける
+type Pivot struct {
+	Rows        []string `yaml:"rows"`                   // 行にするディメンション
+	Columns     []string `yaml:"columns"`                // 列にするディメンション
+	RowLimit    int      `yaml:"row_limit,omitempty"`    // 行の最大数（省略時は DefaultPivotRowLimit）
+	ColumnLimit int      `yaml:"column_limit,omitempty"` // 列の値の組み合わせの最大数（省略時は DefaultPivotColumnLimit）
+}
+
+// ピボット表の行と列の既定の最大数
+// GA4 Data API では各ピボットの limit の積が MaxPageSize 以下である必要がある
+const (
+	DefaultPivotRowLimit    = 10000
+	DefaultPivotColumnLimit = 25
+)
+
+// RowLimitOrDefault は行の最大数を返す（未指定の場合は既定値）
+func (p *Pivot) RowLimitOrDefault() int {
+	if p.RowLimit > 0 {
+		return p.RowLimit
+	}
+	return DefaultPivotRowLimit
+}
+
+// ColumnLimitOrDefault は列の値の組み合わせの最大数を返す（未指定の場合は既定値）
+func (p *Pivot) ColumnLimitOrDefault() int {
+	if p.ColumnLimit > 0 {
+		return p.ColumnLimit
+	}
+	return DefaultPivotColumnLimit
+}
+
+// validatePivot はpivot の妥当性と他の設定との組み合わせを検証する
+func (c *ConfigServiceImpl) validatePivot(stream Stream, streamPath string) error {
+	pivot := stream.Pivot
+	if pivot == nil {
+		return nil
+	}
+	path := streamPath + ".pivot"
+
+	if len(pivot.Rows) == 0 {
+		return fmt.Errorf("%s.rows は必須項目です", path)
+	}
+	if len(pivot.Columns) == 0 {
+		return fmt.Errorf("%s.columns は必須項目です", path)
+	}
+
+	used := make(map[string]bool)
+	for _, field := range append(append([]string{}, pivot.Rows...), pivot.Columns...) {
+		if !contains(stream.Dimensions, field) {
+			return fmt.Errorf("%s: %s はこのストリームの dimensions に含まれている必要があります", path, field)
+		}
+		if used[field] {
+			return fmt.Errorf("%s: %s が rows と columns に重複して指定されています", path, field)
+		}
+		used[field] = true
+	}
+	for _, dim := range stream.Dimensions {
+		if !used[dim] {
+			return fmt.Errorf("%s: ディメンション %s を rows または columns に指定してください", path, dim)
+		}
+	}
+
+	if pivot.RowLimit < 0 || pivot.ColumnLimit < 0 {
+		return fmt.Errorf("%s: row_limit と column_limit は 0 以上で指定してください", path)
+	}
+	if pivot.RowLimitOrDefault()*pivot.ColumnLimitOrDefault() > MaxPageSize {
+		return fmt.Errorf("%s: row_limit と column_limit の積は %d 以下である必要があります", path, MaxPageSize)
+	}
+
+	// ピボットレポートは行と列の上限で取得範囲が決まるため、ページングや期間の分割とは併用できない
+	switch {
+	case stream.ChunkBy != "":
+		return fmt.Errorf("%s: pivot と chunk_by は同時に指定できません", streamPath)
+	case stream.Compare != nil:
+		return fmt.Errorf("%s: pivot と compare は同時に指定できません", streamPath)
+	case stream.Limit > 0 || len(stream.OrderBy) > 0:
+		return fmt.Errorf("%s: pivot と limit / order_by は同時に指定できません（row_limit / column_limit を使用してください）", streamPath)
+	case stream.PageSize > 0 || stream.MaxRows > 0:
+		return fmt.Errorf("%s: pivot と page_size / max_rows は同時に指定できません", streamPath)
+	}
+
+	return nil
+}
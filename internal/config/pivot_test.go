@@ -0,0 +1,98 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig_Pivot(t *testing.T) {
+	service := &ConfigServiceImpl{}
+
+	tests := []struct {
+		name    string
+		modify  func(s *Stream)
+		wantErr string
+	}{
+		{name: "有効なピボット"},
+		{
+			name:    "列なし",
+			modify:  func(s *Stream) { s.Pivot.Columns = nil },
+			wantErr: "pivot.columns は必須項目です",
+		},
+		{
+			name:    "dimensionsにないフィールド",
+			modify:  func(s *Stream) { s.Pivot.Columns = []string{"country"} },
+			wantErr: "country はこのストリームの dimensions",
+		},
+		{
+			name:    "振り分けられていないディメンション",
+			modify:  func(s *Stream) { s.Dimensions = append(s.Dimensions, "country") },
+			wantErr: "ディメンション country を rows または columns",
+		},
+		{
+			name:    "行と列で重複",
+			modify:  func(s *Stream) { s.Pivot.Columns = []string{"pagePath"} },
+			wantErr: "重複",
+		},
+		{
+			name:    "上限の積が大きすぎる",
+			modify:  func(s *Stream) { s.Pivot.RowLimit = 100000; s.Pivot.ColumnLimit = 10 },
+			wantErr: "row_limit と column_limit の積",
+		},
+		{
+			name:    "chunk_byと併用",
+			modify:  func(s *Stream) { s.ChunkBy = "month" },
+			wantErr: "pivot と chunk_by",
+		},
+		{
+			name:    "limitと併用",
+			modify:  func(s *Stream) { s.Limit = 10 },
+			wantErr: "pivot と limit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := Stream{
+				ID:         "1234567",
+				Dimensions: []string{"pagePath", "deviceCategory"},
+				Metrics:    []string{"sessions"},
+				Pivot:      &Pivot{Rows: []string{"pagePath"}, Columns: []string{"deviceCategory"}},
+			}
+			if tt.modify != nil {
+				tt.modify(&stream)
+			}
+			config := &Config{
+				StartDate:  "2024-01-01",
+				EndDate:    "2024-01-31",
+				Account:    "123456789",
+				Properties: []Property{{ID: "987654321", Streams: []Stream{stream}}},
+			}
+
+			err := service.ValidateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
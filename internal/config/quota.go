@@ -0,0 +1,66 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "fmt"
+
+// Quota はプロパティのクォータ残量に応じてデータ取得を抑制する設定を表す構造体
+// 残量の割合はトークン（1日あたり・1時間あたり・プロジェクトの1時間あたり）のうち最も少ないものを使う
+type Quota struct {
+	SlowdownBelow *float64 `yaml:"slowdown_below,omitempty"` // 残量がこの割合（%）を下回ったらAPI呼び出しの前に待機する（0は待機しない）
+	StopBelow     *float64 `yaml:"stop_below,omitempty"`     // 残量がこの割合（%）を下回ったら取得を中止する（0は中止しない）
+	Disabled      bool     `yaml:"disabled,omitempty"`       // trueの場合は残量による抑制を行わない
+}
+
+// DefaultQuotaSlowdownBelow は待機を始めるクォータ残量の既定値（%）
+const DefaultQuotaSlowdownBelow = 20.0
+
+// DefaultQuotaStopBelow は取得を中止するクォータ残量の既定値（%）
+const DefaultQuotaStopBelow = 5.0
+
+// SlowdownBelowOrDefault は待機を始めるクォータ残量（%）を返す
+func (q *Quota) SlowdownBelowOrDefault() float64 {
+	if q == nil || q.SlowdownBelow == nil {
+		return DefaultQuotaSlowdownBelow
+	}
+	return *q.SlowdownBelow
+}
+
+// StopBelowOrDefault は取得を中止するクォータ残量（%）を返す
+func (q *Quota) StopBelowOrDefault() float64 {
+	if q == nil || q.StopBelow == nil {
+		return DefaultQuotaStopBelow
+	}
+	return *q.StopBelow
+}
+
+// validateQuota はquota セクションを検証する
+func (c *ConfigServiceImpl) validateQuota(q *Quota) error {
+	if q == nil {
+		return nil
+	}
+
+	if slowdown := q.SlowdownBelowOrDefault(); slowdown < 0 || slowdown > 100 {
+		return fmt.Errorf("quota.slowdown_below は0から100の範囲で指定してください: %g", slowdown)
+	}
+	if stop := q.StopBelowOrDefault(); stop < 0 || stop > 100 {
+		return fmt.Errorf("quota.stop_below は0から100の範囲で指定してください: %g", stop)
+	}
+	if q.StopBelowOrDefault() > q.SlowdownBelowOrDefault() {
+		return fmt.Errorf("quota.stop_below (%g) は quota.slowdown_below (%g) 以下である必要があります", q.StopBelowOrDefault(), q.SlowdownBelowOrDefault())
+	}
+
+	return nil
+}
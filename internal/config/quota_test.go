@@ -0,0 +1,130 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateConfig_Quota(t *testing.T) {
+	service := &ConfigServiceImpl{}
+
+	tests := []struct {
+		name    string
+		quota   *Quota
+		wantErr string
+	}{
+		{name: "quotaなし", quota: nil},
+		{name: "有効な設定", quota: &Quota{SlowdownBelow: float64Ptr(30), StopBelow: float64Ptr(10)}},
+		{name: "中止のみ指定", quota: &Quota{StopBelow: float64Ptr(1)}},
+		{name: "中止しない", quota: &Quota{StopBelow: float64Ptr(0)}},
+		{name: "待機も中止もしない", quota: &Quota{SlowdownBelow: float64Ptr(0), StopBelow: float64Ptr(0)}},
+		{
+			name:    "範囲外の割合",
+			quota:   &Quota{SlowdownBelow: float64Ptr(120)},
+			wantErr: "quota.slowdown_below は0から100",
+		},
+		{
+			name:    "負の割合",
+			quota:   &Quota{StopBelow: float64Ptr(-1)},
+			wantErr: "quota.stop_below は0から100",
+		},
+		{
+			name:    "待機しない場合の中止",
+			quota:   &Quota{SlowdownBelow: float64Ptr(0), StopBelow: float64Ptr(5)},
+			wantErr: "quota.stop_below (5) は quota.slowdown_below (0) 以下",
+		},
+		{
+			name:    "中止が待機より大きい",
+			quota:   &Quota{StopBelow: float64Ptr(30)},
+			wantErr: "quota.stop_below (30) は quota.slowdown_below (20) 以下",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				StartDate: "2024-01-01",
+				EndDate:   "2024-01-31",
+				Account:   "123456789",
+				Properties: []Property{{
+					ID: "987654321",
+					Streams: []Stream{{
+						ID:         "1234567",
+						Dimensions: []string{"date"},
+						Metrics:    []string{"sessions"},
+					}},
+				}},
+				Quota: tt.quota,
+			}
+
+			err := service.ValidateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQuota_Defaults(t *testing.T) {
+	var q *Quota
+	if got := q.SlowdownBelowOrDefault(); got != DefaultQuotaSlowdownBelow {
+		t.Errorf("SlowdownBelowOrDefault() = %v, want %v", got, DefaultQuotaSlowdownBelow)
+	}
+	if got := q.StopBelowOrDefault(); got != DefaultQuotaStopBelow {
+		t.Errorf("StopBelowOrDefault() = %v, want %v", got, DefaultQuotaStopBelow)
+	}
+
+	q = &Quota{SlowdownBelow: float64Ptr(50), StopBelow: float64Ptr(25)}
+	if got := q.SlowdownBelowOrDefault(); got != 50 {
+		t.Errorf("SlowdownBelowOrDefault() = %v, want 50", got)
+	}
+	if got := q.StopBelowOrDefault(); got != 25 {
+		t.Errorf("StopBelowOrDefault() = %v, want 25", got)
+	}
+
+	// 0を指定した場合は既定値に置き換えない
+	q = &Quota{StopBelow: float64Ptr(0)}
+	if got := q.StopBelowOrDefault(); got != 0 {
+		t.Errorf("StopBelowOrDefault() = %v, want 0", got)
+	}
+}
+
+func TestQuota_UnmarshalZero(t *testing.T) {
+	var cfg Config
+	if err := yaml.Unmarshal([]byte("quota:\n  stop_below: 0\n"), &cfg); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+	if got := cfg.Quota.StopBelowOrDefault(); got != 0 {
+		t.Errorf("StopBelowOrDefault() = %v, want 0", got)
+	}
+	if got := cfg.Quota.SlowdownBelowOrDefault(); got != DefaultQuotaSlowdownBelow {
+		t.Errorf("SlowdownBelowOrDefault() = %v, want %v", got, DefaultQuotaSlowdownBelow)
+	}
+}
+
+// float64Ptr はfloat64のポインタを返す
+func float64Ptr(v float64) *float64 {
+	return &v
+}
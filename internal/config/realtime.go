@@ -0,0 +1,125 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Realtime はリアルタイムレポート（ga realtime）の設定を表す構造体
+// 対象プロパティとストリームは properties の設定を使用する
+type Realtime struct {
+	Dimensions   []string      `yaml:"dimensions"`
+	Metrics      []string      `yaml:"metrics"`
+	MinuteRanges []MinuteRange `yaml:"minute_ranges,omitempty"` // 省略時は直近30分
+	Limit        int           `yaml:"limit,omitempty"`         // 取得する最大行数（0は無制限）
+
+	// PropertyWide がtrueの場合はstreamIdで絞り込まず、プロパティ全体のデータを取得する
+	PropertyWide bool `yaml:"property_wide,omitempty"`
+}
+
+// MinuteRange はリアルタイムレポートの集計対象とする分の範囲を表す構造体
+// 例: start_minutes_ago: 29, end_minutes_ago: 0 は直近30分
+type MinuteRange struct {
+	Name            string `yaml:"name,omitempty"`
+	StartMinutesAgo int    `yaml:"start_minutes_ago"`
+	EndMinutesAgo   int    `yaml:"end_minutes_ago"`
+}
+
+// RealtimeDimensions はリアルタイムレポートで使用できるディメンションの一覧
+// customUser: で始まるユーザースコープのカスタムディメンションも使用できる
+var RealtimeDimensions = []string{
+	"appVersion",
+	"audienceId",
+	"audienceName",
+	"audienceResourceName",
+	"city",
+	"cityId",
+	"country",
+	"countryId",
+	"deviceCategory",
+	"eventName",
+	"minutesAgo",
+	"platform",
+	"streamId",
+	"streamName",
+	"unifiedScreenName",
+}
+
+// RealtimeMetrics はリアルタイムレポートで使用できるメトリクスの一覧
+var RealtimeMetrics = []string{
+	"activeUsers",
+	"eventCount",
+	"keyEvents",
+	"screenPageViews",
+}
+
+// MaxMinuteRanges はリアルタイムレポートに指定できる分範囲の最大数
+const MaxMinuteRanges = 2
+
+// MaxMinutesAgo は分範囲に指定できる最大値（GA4 360プロパティの場合。標準プロパティは29まで）
+const MaxMinutesAgo = 59
+
+// IsRealtimeDimension はディメンションがリアルタイムレポートで使用できるかを判定する
+func IsRealtimeDimension(name string) bool {
+	return contains(RealtimeDimensions, name) || strings.HasPrefix(name, "customUser:")
+}
+
+// IsRealtimeMetric はメトリクスがリアルタイムレポートで使用できるかを判定する
+func IsRealtimeMetric(name string) bool {
+	return contains(RealtimeMetrics, name)
+}
+
+// validateRealtime はrealtime セクションを検証する
+func (c *ConfigServiceImpl) validateRealtime(rt *Realtime) error {
+	if rt == nil {
+		return nil
+	}
+
+	if len(rt.Metrics) == 0 {
+		return fmt.Errorf("realtime.metrics は必須項目です")
+	}
+
+	for _, dim := range rt.Dimensions {
+		if !IsRealtimeDimension(dim) {
+			return fmt.Errorf("realtime.dimensions: %s はリアルタイムレポートで使用できません（%s, customUser:* のいずれかを指定してください）", dim, strings.Join(RealtimeDimensions, ", "))
+		}
+	}
+	for _, metric := range rt.Metrics {
+		if !IsRealtimeMetric(metric) {
+			return fmt.Errorf("realtime.metrics: %s はリアルタイムレポートで使用できません（%s のいずれかを指定してください）", metric, strings.Join(RealtimeMetrics, ", "))
+		}
+	}
+
+	if rt.Limit < 0 {
+		return fmt.Errorf("realtime.limit は0以上である必要があります: %d", rt.Limit)
+	}
+
+	if len(rt.MinuteRanges) > MaxMinuteRanges {
+		return fmt.Errorf("realtime.minute_ranges は最大%d個まで指定できます", MaxMinuteRanges)
+	}
+	for i, mr := range rt.MinuteRanges {
+		path := fmt.Sprintf("realtime.minute_ranges[%d]", i)
+		if mr.EndMinutesAgo < 0 || mr.StartMinutesAgo > MaxMinutesAgo {
+			return fmt.Errorf("%s は0から%dの範囲で指定してください", path, MaxMinutesAgo)
+		}
+		if mr.StartMinutesAgo < mr.EndMinutesAgo {
+			return fmt.Errorf("%s.start_minutes_ago は end_minutes_ago 以上である必要があります", path)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig_Realtime(t *testing.T) {
+	service := &ConfigServiceImpl{}
+
+	tests := []struct {
+		name     string
+		realtime *Realtime
+		wantErr  string
+	}{
+		{name: "realtimeなし", realtime: nil},
+		{
+			name:     "有効な設定",
+			realtime: &Realtime{Dimensions: []string{"country", "customUser:plan"}, Metrics: []string{"activeUsers"}},
+		},
+		{
+			name: "分範囲を2つ指定",
+			realtime: &Realtime{
+				Metrics: []string{"activeUsers"},
+				MinuteRanges: []MinuteRange{
+					{Name: "last5", StartMinutesAgo: 4, EndMinutesAgo: 0},
+					{Name: "prev", StartMinutesAgo: 29, EndMinutesAgo: 5},
+				},
+			},
+		},
+		{
+			name:     "メトリクスなし",
+			realtime: &Realtime{Dimensions: []string{"country"}},
+			wantErr:  "realtime.metrics は必須項目です",
+		},
+		{
+			name:     "リアルタイム非対応のディメンション",
+			realtime: &Realtime{Dimensions: []string{"pagePath"}, Metrics: []string{"activeUsers"}},
+			wantErr:  "realtime.dimensions: pagePath",
+		},
+		{
+			name:     "リアルタイム非対応のメトリクス",
+			realtime: &Realtime{Metrics: []string{"sessions"}},
+			wantErr:  "realtime.metrics: sessions",
+		},
+		{
+			name: "分範囲が多すぎる",
+			realtime: &Realtime{
+				Metrics:      []string{"activeUsers"},
+				MinuteRanges: []MinuteRange{{StartMinutesAgo: 1}, {StartMinutesAgo: 2}, {StartMinutesAgo: 3}},
+			},
+			wantErr: "最大2個",
+		},
+		{
+			name:     "分範囲の順序が逆",
+			realtime: &Realtime{Metrics: []string{"activeUsers"}, MinuteRanges: []MinuteRange{{StartMinutesAgo: 0, EndMinutesAgo: 10}}},
+			wantErr:  "realtime.minute_ranges[0].start_minutes_ago",
+		},
+		{
+			name:     "分範囲が上限を超える",
+			realtime: &Realtime{Metrics: []string{"activeUsers"}, MinuteRanges: []MinuteRange{{StartMinutesAgo: 60}}},
+			wantErr:  "realtime.minute_ranges[0] は0から59",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				StartDate: "2024-01-01",
+				EndDate:   "2024-01-31",
+				Account:   "123456789",
+				Properties: []Property{{
+					ID: "987654321",
+					Streams: []Stream{{
+						ID:         "1234567",
+						Dimensions: []string{"date"},
+						Metrics:    []string{"sessions"},
+					}},
+				}},
+				Realtime: tt.realtime,
+			}
+
+			err := service.ValidateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Retry はAPI呼び出しのリトライ方法を表す構造体
+// 省略した項目は既定値を使用する
+type Retry struct {
+	MaxRetries     *int          `yaml:"max_retries,omitempty"`     // 最大リトライ回数（0はリトライしない）
+	BaseDelay      time.Duration `yaml:"base_delay,omitempty"`      // 初回のリトライまでの待機時間（例: 1s）
+	MaxDelay       time.Duration `yaml:"max_delay,omitempty"`       // 待機時間の上限（例: 30s）
+	BackoffFactor  float64       `yaml:"backoff_factor,omitempty"`  // リトライごとに待機時間に掛ける倍率
+	Jitter         *float64      `yaml:"jitter,omitempty"`          // 待機時間をランダムに短くする割合（0〜1）
+	RetryableCodes []int         `yaml:"retryable_codes,omitempty"` // リトライするHTTPステータスコード
+	Budget         time.Duration `yaml:"budget,omitempty"`          // 1回の実行でリトライの待機に使える合計時間（0は無制限）
+}
+
+// validateRetry はretry セクションを検証する
+func (c *ConfigServiceImpl) validateRetry(r *Retry) error {
+	if r == nil {
+		return nil
+	}
+
+	if r.MaxRetries != nil && *r.MaxRetries < 0 {
+		return fmt.Errorf("retry.max_retries は0以上である必要があります: %d", *r.MaxRetries)
+	}
+	if r.BaseDelay < 0 || r.MaxDelay < 0 || r.Budget < 0 {
+		return fmt.Errorf("retry.base_delay, retry.max_delay, retry.budget は0以上である必要があります")
+	}
+	if r.BaseDelay > 0 && r.MaxDelay > 0 && r.BaseDelay > r.MaxDelay {
+		return fmt.Errorf("retry.base_delay (%v) は retry.max_delay (%v) 以下である必要があります", r.BaseDelay, r.MaxDelay)
+	}
+	if r.BackoffFactor != 0 && r.BackoffFactor < 1 {
+		return fmt.Errorf("retry.backoff_factor は1以上である必要があります: %g", r.BackoffFactor)
+	}
+	if r.Jitter != nil && (*r.Jitter < 0 || *r.Jitter > 1) {
+		return fmt.Errorf("retry.jitter は0から1の範囲で指定してください: %g", *r.Jitter)
+	}
+	for _, code := range r.RetryableCodes {
+		if code < 400 || code > 599 {
+			return fmt.Errorf("retry.retryable_codes には400から599のHTTPステータスコードを指定してください: %d", code)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateConfig_Retry(t *testing.T) {
+	service := &ConfigServiceImpl{}
+	negative := -1
+	zero := 0
+	tooLarge := 1.5
+
+	tests := []struct {
+		name    string
+		retry   *Retry
+		wantErr string
+	}{
+		{name: "retryなし", retry: nil},
+		{name: "有効な設定", retry: &Retry{BaseDelay: time.Second, MaxDelay: 10 * time.Second, BackoffFactor: 1.5, RetryableCodes: []int{429, 503}, Budget: time.Minute}},
+		{name: "リトライしない", retry: &Retry{MaxRetries: &zero}},
+		{
+			name:    "負のリトライ回数",
+			retry:   &Retry{MaxRetries: &negative},
+			wantErr: "retry.max_retries は0以上",
+		},
+		{
+			name:    "初回の待機時間が上限より長い",
+			retry:   &Retry{BaseDelay: time.Minute, MaxDelay: time.Second},
+			wantErr: "retry.base_delay (1m0s) は retry.max_delay (1s) 以下",
+		},
+		{
+			name:    "1未満の倍率",
+			retry:   &Retry{BackoffFactor: 0.5},
+			wantErr: "retry.backoff_factor は1以上",
+		},
+		{
+			name:    "範囲外のジッター",
+			retry:   &Retry{Jitter: &tooLarge},
+			wantErr: "retry.jitter は0から1の範囲",
+		},
+		{
+			name:    "HTTPエラーではないステータスコード",
+			retry:   &Retry{RetryableCodes: []int{200}},
+			wantErr: "retry.retryable_codes には400から599",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				StartDate: "2024-01-01",
+				EndDate:   "2024-01-31",
+				Account:   "123456789",
+				Properties: []Property{{
+					ID: "987654321",
+					Streams: []Stream{{
+						ID:         "1234567",
+						Dimensions: []string{"date"},
+						Metrics:    []string{"sessions"},
+					}},
+				}},
+				Retry: tt.retry,
+			}
+
+			err := service.ValidateConfig(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetry_UnmarshalYAML(t *testing.T) {
+	input := `
+max_retries: 0
+base_delay: 500ms
+max_delay: 20s
+backoff_factor: 3
+jitter: 0
+retryable_codes: [429, 503]
+budget: 2m
+`
+	var r Retry
+	if err := yaml.Unmarshal([]byte(input), &r); err != nil {
+		t.Fatalf("yaml.Unmarshal() error = %v", err)
+	}
+
+	if r.MaxRetries == nil || *r.MaxRetries != 0 {
+		t.Errorf("MaxRetries = %v, want 0", r.MaxRetries)
+	}
+	if r.BaseDelay != 500*time.Millisecond || r.MaxDelay != 20*time.Second || r.Budget != 2*time.Minute {
+		t.Errorf("BaseDelay, MaxDelay, Budget = %v, %v, %v", r.BaseDelay, r.MaxDelay, r.Budget)
+	}
+	if r.BackoffFactor != 3 {
+		t.Errorf("BackoffFactor = %g, want 3", r.BackoffFactor)
+	}
+	if r.Jitter == nil || *r.Jitter != 0 {
+		t.Errorf("Jitter = %v, want 0", r.Jitter)
+	}
+	if len(r.RetryableCodes) != 2 || r.RetryableCodes[0] != 429 || r.RetryableCodes[1] != 503 {
+		t.Errorf("RetryableCodes = %v, want [429 503]", r.RetryableCodes)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "fmt"
+
+const (
+	// SchemaModeUnion は全ストリームの列をまとめた1つの表に出力するモード（既定）
+	// ストリームにない列は null_value で埋める
+	SchemaModeUnion = "union"
+	// SchemaModeSplit は列の構成が同じストリームごとに別の表に出力するモード
+	SchemaModeSplit = "split"
+)
+
+// SchemaModeOrDefault は出力する表の構成（union または split）を返す
+func (c *Config) SchemaModeOrDefault() string {
+	if c.SchemaMode == "" {
+		return SchemaModeUnion
+	}
+	return c.SchemaMode
+}
+
+// validateSchemaMode はschema_mode を検証する
+func (c *ConfigServiceImpl) validateSchemaMode(config *Config) error {
+	switch config.SchemaModeOrDefault() {
+	case SchemaModeUnion, SchemaModeSplit:
+		return nil
+	default:
+		return fmt.Errorf("schema_mode には %s または %s を指定してください: %s", SchemaModeUnion, SchemaModeSplit, config.SchemaMode)
+	}
+}
+
+// SplitBySchema は列の構成ごとに別の表に出力するかどうかを返す
+func (c *Config) SplitBySchema() bool {
+	return c.SchemaModeOrDefault() == SchemaModeSplit
+}
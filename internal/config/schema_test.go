@@ -0,0 +1,69 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateConfig_SchemaMode(t *testing.T) {
+	service := &ConfigServiceImpl{}
+
+	tests := []struct {
+		name      string
+		mode      string
+		wantSplit bool
+		wantErr   string
+	}{
+		{name: "省略時はunion", mode: ""},
+		{name: "union", mode: "union"},
+		{name: "split", mode: "split", wantSplit: true},
+		{name: "不正なモード", mode: "merge", wantErr: "schema_mode には union または split を指定してください: merge"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				StartDate: "2024-01-01",
+				EndDate:   "2024-01-31",
+				Account:   "123456789",
+				Properties: []Property{{
+					ID: "987654321",
+					Streams: []Stream{{
+						ID:         "1234567",
+						Dimensions: []string{"date"},
+						Metrics:    []string{"sessions"},
+					}},
+				}},
+				SchemaMode: tt.mode,
+			}
+
+			err := service.ValidateConfig(config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ValidateConfig() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ValidateConfig() error = %v", err)
+			}
+			if got := config.SplitBySchema(); got != tt.wantSplit {
+				t.Errorf("SplitBySchema() = %v, want %v", got, tt.wantSplit)
+			}
+		})
+	}
+}
@@ -85,6 +85,11 @@ func (e *GAError) Error() string {
 	return strings.Join(parts, " ")
 }
 
+// Unwrap は原因となったエラーを返す（errors.Is / errors.As で使用する）
+func (e *GAError) Unwrap() error {
+	return e.Cause
+}
+
 // GetUserFriendlyMessage はユーザー向けの分かりやすいメッセージを返す
 func (e *GAError) GetUserFriendlyMessage() string {
 	switch e.Type {
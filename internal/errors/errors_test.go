@@ -16,6 +16,7 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -182,6 +183,24 @@ func TestGAError_WithContext(t *testing.T) {
 	}
 }
 
+func TestGAError_Unwrap(t *testing.T) {
+	cause := errors.New("underlying error")
+	wrapped := fmt.Errorf("取得に失敗しました: %w", NewAPIError(APIRequestFailed, cause))
+
+	if !errors.Is(wrapped, cause) {
+		t.Error("errors.Is() should find the cause through GAError")
+	}
+
+	var gaError *GAError
+	if !errors.As(wrapped, &gaError) || gaError.Type != APIError {
+		t.Errorf("errors.As() = %v, want APIError", gaError)
+	}
+
+	if (&GAError{}).Unwrap() != nil {
+		t.Error("Unwrap() without cause should return nil")
+	}
+}
+
 func TestNewAuthError(t *testing.T) {
 	cause := errors.New("underlying error")
 	gaError := NewAuthError(AuthTokenExpired, cause)
@@ -0,0 +1,188 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package incremental は差分取得の状態ファイルを管理し、ストリームごとに取得する期間を決める
+package incremental
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/analytics"
+	"github.com/ymotongpoo/ga/internal/config"
+)
+
+// State は差分取得の状態（ストリームごとの取得済みの最終日）を表す構造体
+type State struct {
+	Streams map[string]StreamState `json:"streams"` // キーは "プロパティID/ストリームID"
+}
+
+// StreamState はストリームの取得状態を表す構造体
+type StreamState struct {
+	PropertyID string    `json:"property_id"`
+	StreamID   string    `json:"stream_id"`
+	LastDate   string    `json:"last_date"`  // 取得済みの最終日（YYYY-MM-DD形式）
+	UpdatedAt  time.Time `json:"updated_at"` // 最後に取得した日時
+}
+
+// Fetch は今回取得するストリームと期間を表す構造体
+type Fetch struct {
+	PropertyID string
+	StreamID   string
+	StartDate  string // YYYY-MM-DD形式
+	EndDate    string // YYYY-MM-DD形式
+}
+
+// NewState は空の状態を作成する
+func NewState() *State {
+	return &State{Streams: make(map[string]StreamState)}
+}
+
+// Load は状態ファイルを読み込む（ファイルが存在しない場合は空の状態を返す）
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return NewState(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("状態ファイル '%s' の読み込みに失敗しました: %w", path, err)
+	}
+
+	state := NewState()
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("状態ファイル '%s' の形式が不正です: %w", path, err)
+	}
+	if state.Streams == nil {
+		state.Streams = make(map[string]StreamState)
+	}
+	return state, nil
+}
+
+// Save は状態ファイルを書き込む
+// 書き込み途中で中断しても以前の状態が壊れないよう、一時ファイルに書き込んでから置き換える
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("状態のJSONへの変換に失敗しました: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("状態ファイルのディレクトリの作成に失敗しました: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("状態ファイル '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("状態ファイル '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	return nil
+}
+
+// Plan は取得済みの最終日と再取得する日数から各ストリームの取得開始日を決め、cfg のストリームに設定する
+// 新しく取得する日がないストリームは cfg から除き、今回取得するストリームと期間を返す
+// cfg の start_date と end_date は解決済み（YYYY-MM-DD形式）である必要がある
+func (s *State) Plan(cfg *config.Config, overlapDays int) ([]Fetch, error) {
+	start, err := time.Parse(config.DateLayout, cfg.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("start_date の形式が不正です: %s", cfg.StartDate)
+	}
+	end, err := time.Parse(config.DateLayout, cfg.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("end_date の形式が不正です: %s", cfg.EndDate)
+	}
+
+	var fetches []Fetch
+	var properties []config.Property
+	for _, property := range cfg.Properties {
+		var streams []config.Stream
+		for _, stream := range property.Streams {
+			from := start
+			if last, ok := s.Streams[stateKey(property.ID, stream.ID)]; ok {
+				lastDate, err := time.Parse(config.DateLayout, last.LastDate)
+				if err != nil {
+					return nil, fmt.Errorf("プロパティ %s, ストリーム %s の取得済みの最終日の形式が不正です: %s", property.ID, stream.ID, last.LastDate)
+				}
+				if next := lastDate.AddDate(0, 0, 1-overlapDays); next.After(from) {
+					from = next
+				}
+			}
+
+			if from.After(end) {
+				fmt.Printf("プロパティ %s, ストリーム %s: %s まで取得済みのためスキップします\n", property.ID, stream.ID, cfg.EndDate)
+				continue
+			}
+
+			stream.FetchStartDate = from.Format(config.DateLayout)
+			streams = append(streams, stream)
+			fetches = append(fetches, Fetch{
+				PropertyID: property.ID,
+				StreamID:   stream.ID,
+				StartDate:  stream.FetchStartDate,
+				EndDate:    cfg.EndDate,
+			})
+			fmt.Printf("プロパティ %s, ストリーム %s: %s - %s を取得します\n", property.ID, stream.ID, stream.FetchStartDate, cfg.EndDate)
+		}
+		if len(streams) > 0 {
+			property.Streams = streams
+			properties = append(properties, property)
+		}
+	}
+
+	cfg.Properties = properties
+	return fetches, nil
+}
+
+// Record はストリームの取得済みの最終日を今回取得した期間の終了日に更新する
+func (s *State) Record(fetches []Fetch, now time.Time) {
+	for _, fetch := range fetches {
+		s.Streams[stateKey(fetch.PropertyID, fetch.StreamID)] = StreamState{
+			PropertyID: fetch.PropertyID,
+			StreamID:   fetch.StreamID,
+			LastDate:   fetch.EndDate,
+			UpdatedAt:  now,
+		}
+	}
+}
+
+// Succeeded は取得に失敗したストリームを除いた取得を返す
+// failures のストリームIDが空の場合（プロパティ全体の失敗）はそのプロパティのすべてのストリームを除く
+func Succeeded(fetches []Fetch, failures []analytics.RequestFailure) []Fetch {
+	var succeeded []Fetch
+	for _, fetch := range fetches {
+		if !failed(failures, fetch) {
+			succeeded = append(succeeded, fetch)
+		}
+	}
+	return succeeded
+}
+
+// failed は取得が失敗したストリームかを判定する
+func failed(failures []analytics.RequestFailure, fetch Fetch) bool {
+	for _, failure := range failures {
+		if failure.PropertyID == fetch.PropertyID && (failure.StreamID == "" || failure.StreamID == fetch.StreamID) {
+			return true
+		}
+	}
+	return false
+}
+
+// stateKey はストリームの状態のキーを返す
+func stateKey(propertyID, streamID string) string {
+	return propertyID + "/" + streamID
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package incremental
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/analytics"
+	"github.com/ymotongpoo/ga/internal/config"
+)
+
+// testConfig は2つのストリームを持つ解決済みの設定を作成する
+func testConfig() *config.Config {
+	return &config.Config{
+		StartDate: "2024-01-01",
+		EndDate:   "2024-01-31",
+		Properties: []config.Property{
+			{ID: "111", Streams: []config.Stream{{ID: "1"}, {ID: "2"}}},
+			{ID: "222", Streams: []config.Stream{{ID: "3"}}},
+		},
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	state, err := Load(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(state.Streams) != 0 {
+		t.Errorf("Streams = %v, want empty", state.Streams)
+	}
+}
+
+func TestState_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+	now := time.Date(2024, 2, 1, 3, 0, 0, 0, time.UTC)
+
+	state := NewState()
+	state.Record([]Fetch{{PropertyID: "111", StreamID: "1", StartDate: "2024-01-01", EndDate: "2024-01-31"}}, now)
+	if err := state.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("一時ファイルが残っています: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]StreamState{
+		"111/1": {PropertyID: "111", StreamID: "1", LastDate: "2024-01-31", UpdatedAt: now},
+	}
+	if !reflect.DeepEqual(loaded.Streams, want) {
+		t.Errorf("Streams = %+v, want %+v", loaded.Streams, want)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() error = nil, want error")
+	}
+}
+
+func TestState_Plan(t *testing.T) {
+	state := NewState()
+	state.Streams["111/1"] = StreamState{LastDate: "2024-01-20"} // 3日分を遡って 2024-01-18 から
+	state.Streams["111/2"] = StreamState{LastDate: "2023-12-01"} // 期間の開始日より前なので start_date から
+	state.Streams["222/3"] = StreamState{LastDate: "2024-01-31"} // 最終日まで取得済みでも3日分を再取得
+
+	cfg := testConfig()
+	fetches, err := state.Plan(cfg, 3)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	want := []Fetch{
+		{PropertyID: "111", StreamID: "1", StartDate: "2024-01-18", EndDate: "2024-01-31"},
+		{PropertyID: "111", StreamID: "2", StartDate: "2024-01-01", EndDate: "2024-01-31"},
+		{PropertyID: "222", StreamID: "3", StartDate: "2024-01-29", EndDate: "2024-01-31"},
+	}
+	if !reflect.DeepEqual(fetches, want) {
+		t.Errorf("Plan() = %+v, want %+v", fetches, want)
+	}
+	if got := cfg.Properties[0].Streams[0].FetchStartDate; got != "2024-01-18" {
+		t.Errorf("FetchStartDate = %s, want 2024-01-18", got)
+	}
+}
+
+func TestState_Plan_UpToDate(t *testing.T) {
+	state := NewState()
+	state.Streams["111/1"] = StreamState{LastDate: "2024-01-31"}
+	state.Streams["222/3"] = StreamState{LastDate: "2024-01-31"}
+
+	cfg := testConfig()
+	fetches, err := state.Plan(cfg, 0)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	// 再取得しない場合、最終日まで取得済みのストリームとそれだけを持つプロパティは除かれる
+	if len(fetches) != 1 || fetches[0].StreamID != "2" {
+		t.Errorf("Plan() = %+v, want stream 2 only", fetches)
+	}
+	if len(cfg.Properties) != 1 || len(cfg.Properties[0].Streams) != 1 || cfg.Properties[0].Streams[0].ID != "2" {
+		t.Errorf("Properties = %+v", cfg.Properties)
+	}
+}
+
+func TestState_Plan_InvalidDate(t *testing.T) {
+	state := NewState()
+	state.Streams["111/1"] = StreamState{LastDate: "yesterday"}
+
+	if _, err := state.Plan(testConfig(), 3); err == nil {
+		t.Error("Plan() error = nil, want error")
+	}
+}
+
+func TestSucceeded(t *testing.T) {
+	fetches := []Fetch{
+		{PropertyID: "111", StreamID: "1"},
+		{PropertyID: "111", StreamID: "2"},
+		{PropertyID: "222", StreamID: "3"},
+		{PropertyID: "333", StreamID: "4"},
+	}
+	failures := []analytics.RequestFailure{
+		{PropertyID: "111", StreamID: "2"},
+		{PropertyID: "222"}, // プロパティ全体の失敗
+	}
+
+	want := []Fetch{{PropertyID: "111", StreamID: "1"}, {PropertyID: "333", StreamID: "4"}}
+	if got := Succeeded(fetches, failures); !reflect.DeepEqual(got, want) {
+		t.Errorf("Succeeded() = %+v, want %+v", got, want)
+	}
+}
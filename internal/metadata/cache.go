@@ -0,0 +1,93 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultTTL はメタデータキャッシュの既定の有効期間
+const DefaultTTL = 24 * time.Hour
+
+// Cache はプロパティごとのメタデータをディスクに保存するキャッシュ
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewCache は指定したディレクトリにメタデータを保存するキャッシュを作成する
+func NewCache(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl}
+}
+
+// DefaultCacheDir はメタデータキャッシュの既定の保存先を返す
+func DefaultCacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %w", err)
+	}
+	return filepath.Join(cacheDir, "ga", "metadata"), nil
+}
+
+// Load はキャッシュからプロパティのメタデータを読み込む
+// キャッシュが存在しない場合は os.ErrNotExist をラップしたエラーを返す
+func (c *Cache) Load(propertyID string) (*Metadata, error) {
+	data, err := os.ReadFile(c.path(propertyID))
+	if err != nil {
+		return nil, fmt.Errorf("メタデータキャッシュの読み込みに失敗しました: %w", err)
+	}
+
+	var md Metadata
+	if err := json.Unmarshal(data, &md); err != nil {
+		return nil, fmt.Errorf("メタデータキャッシュの形式が不正です: %w", err)
+	}
+	return &md, nil
+}
+
+// Save はプロパティのメタデータをキャッシュに保存する
+func (c *Cache) Save(md *Metadata) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("キャッシュディレクトリの作成に失敗しました: %w", err)
+	}
+
+	data, err := json.MarshalIndent(md, "", "  ")
+	if err != nil {
+		return fmt.Errorf("メタデータのシリアライズに失敗しました: %w", err)
+	}
+
+	// 書き込み途中のファイルを読まないよう一時ファイルから置き換える
+	tmp := c.path(md.PropertyID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("メタデータキャッシュの書き込みに失敗しました: %w", err)
+	}
+	if err := os.Rename(tmp, c.path(md.PropertyID)); err != nil {
+		return fmt.Errorf("メタデータキャッシュの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// TTL はキャッシュの有効期間を返す
+func (c *Cache) TTL() time.Duration {
+	return c.ttl
+}
+
+// path はプロパティのキャッシュファイルのパスを返す
+func (c *Cache) path(propertyID string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("property_%s.json", propertyID))
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCache_SaveAndLoad(t *testing.T) {
+	cache := NewCache(t.TempDir(), time.Hour)
+
+	if _, err := cache.Load("123"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("存在しないキャッシュのエラー = %v, want os.ErrNotExist", err)
+	}
+
+	fetchedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	md := &Metadata{
+		PropertyID: "123",
+		FetchedAt:  fetchedAt,
+		Dimensions: []Field{{APIName: "date", UIName: "Date"}},
+		Metrics:    []Field{{APIName: "sessions", Type: "TYPE_INTEGER"}},
+	}
+	if err := cache.Save(md); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := cache.Load("123")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !loaded.FetchedAt.Equal(fetchedAt) || len(loaded.Dimensions) != 1 || loaded.Metrics[0].Type != "TYPE_INTEGER" {
+		t.Errorf("Load() = %+v", loaded)
+	}
+}
+
+func TestMetadata_Expired(t *testing.T) {
+	md := &Metadata{FetchedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}
+
+	if md.Expired(DefaultTTL, md.FetchedAt.Add(time.Hour)) {
+		t.Error("1時間後に期限切れと判定されました")
+	}
+	if !md.Expired(DefaultTTL, md.FetchedAt.Add(25*time.Hour)) {
+		t.Error("25時間後に期限切れと判定されませんでした")
+	}
+}
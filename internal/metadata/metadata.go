@@ -0,0 +1,102 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metadata はGA4 Metadata APIから取得したディメンション・メトリクスの定義を扱う
+package metadata
+
+import (
+	"time"
+
+	"google.golang.org/api/analyticsdata/v1beta"
+)
+
+// Field はディメンションまたはメトリクスの定義を表す構造体
+type Field struct {
+	APIName          string `json:"api_name"`
+	UIName           string `json:"ui_name,omitempty"`
+	Category         string `json:"category,omitempty"`
+	Type             string `json:"type,omitempty"` // メトリクスの型（TYPE_INTEGER, TYPE_SECONDS など）
+	CustomDefinition bool   `json:"custom_definition,omitempty"`
+}
+
+// Metadata はプロパティで利用できるディメンションとメトリクスの一覧を表す構造体
+type Metadata struct {
+	PropertyID string    `json:"property_id"`
+	FetchedAt  time.Time `json:"fetched_at"`
+	Dimensions []Field   `json:"dimensions"`
+	Metrics    []Field   `json:"metrics"`
+}
+
+// FromResponse はMetadata APIのレスポンスからMetadataを作成する
+func FromResponse(propertyID string, response *analyticsdata.Metadata, fetchedAt time.Time) *Metadata {
+	md := &Metadata{
+		PropertyID: propertyID,
+		FetchedAt:  fetchedAt,
+	}
+
+	for _, dim := range response.Dimensions {
+		md.Dimensions = append(md.Dimensions, Field{
+			APIName:          dim.ApiName,
+			UIName:           dim.UiName,
+			Category:         dim.Category,
+			CustomDefinition: dim.CustomDefinition,
+		})
+	}
+
+	for _, metric := range response.Metrics {
+		md.Metrics = append(md.Metrics, Field{
+			APIName:          metric.ApiName,
+			UIName:           metric.UiName,
+			Category:         metric.Category,
+			Type:             metric.Type,
+			CustomDefinition: metric.CustomDefinition,
+		})
+	}
+
+	return md
+}
+
+// Dimension は指定したAPI名のディメンション定義を返す
+func (m *Metadata) Dimension(name string) (Field, bool) {
+	return findField(m.Dimensions, name)
+}
+
+// Metric は指定したAPI名のメトリクス定義を返す
+func (m *Metadata) Metric(name string) (Field, bool) {
+	return findField(m.Metrics, name)
+}
+
+// Expired は取得日時からttlが経過しているかを判定する
+func (m *Metadata) Expired(ttl time.Duration, now time.Time) bool {
+	return now.Sub(m.FetchedAt) > ttl
+}
+
+// findField はフィールド一覧からAPI名が一致するものを探す
+func findField(fields []Field, name string) (Field, bool) {
+	for _, field := range fields {
+		if field.APIName == name {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+// apiNames はフィールド一覧のAPI名を返す
+func apiNames(fields []Field) []string {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.APIName)
+	}
+	return names
+}
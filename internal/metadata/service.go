@@ -0,0 +1,121 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/analyticsdata/v1beta"
+	"google.golang.org/api/option"
+)
+
+// Source はプロパティのメタデータを提供するインターフェース
+type Source interface {
+	// Get は指定したプロパティのメタデータを返す
+	Get(ctx context.Context, propertyID string) (*Metadata, error)
+}
+
+// Service はMetadata APIとディスクキャッシュからメタデータを提供する
+// 同じ実行中に同じプロパティのメタデータを取得するのは1回だけ
+type Service struct {
+	data   *analyticsdata.Service // nilの場合はオフライン（キャッシュのみ）
+	cache  *Cache
+	loaded map[string]*Metadata
+}
+
+// NewService はMetadata APIを利用する新しいServiceを作成する
+func NewService(ctx context.Context, token *oauth2.Token, cache *Cache) (*Service, error) {
+	tokenSource := oauth2.StaticTokenSource(token)
+
+	data, err := analyticsdata.NewService(ctx, option.WithTokenSource(tokenSource))
+	if err != nil {
+		return nil, fmt.Errorf("Analytics Data APIサービスの作成に失敗しました: %w", err)
+	}
+
+	return newService(data, cache), nil
+}
+
+// NewOfflineService はキャッシュ済みのメタデータのみを利用するServiceを作成する
+// 有効期間を過ぎたキャッシュも利用する
+func NewOfflineService(cache *Cache) *Service {
+	return newService(nil, cache)
+}
+
+// newService はServiceを作成する
+func newService(data *analyticsdata.Service, cache *Cache) *Service {
+	return &Service{
+		data:   data,
+		cache:  cache,
+		loaded: make(map[string]*Metadata),
+	}
+}
+
+// Get は指定したプロパティのメタデータを返す
+// 有効なキャッシュがあればそれを使い、なければMetadata APIから取得してキャッシュに保存する
+func (s *Service) Get(ctx context.Context, propertyID string) (*Metadata, error) {
+	if md, ok := s.loaded[propertyID]; ok {
+		return md, nil
+	}
+
+	cached, cacheErr := s.cache.Load(propertyID)
+
+	if s.data == nil {
+		if cacheErr != nil {
+			return nil, fmt.Errorf("プロパティ %s のメタデータがキャッシュにありません（オンラインで一度 'ga validate' を実行してください）: %w", propertyID, cacheErr)
+		}
+		s.loaded[propertyID] = cached
+		return cached, nil
+	}
+
+	if cacheErr == nil && !cached.Expired(s.cache.TTL(), time.Now()) {
+		s.loaded[propertyID] = cached
+		return cached, nil
+	}
+
+	md, err := s.fetch(ctx, propertyID)
+	if err != nil {
+		// 取得に失敗しても期限切れのキャッシュがあればそれを使う
+		if cacheErr == nil {
+			fmt.Printf("⚠️  プロパティ %s のメタデータの取得に失敗したため、%s に取得したキャッシュを使用します: %v\n",
+				propertyID, cached.FetchedAt.Format(time.RFC3339), err)
+			s.loaded[propertyID] = cached
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	if err := s.cache.Save(md); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+
+	s.loaded[propertyID] = md
+	return md, nil
+}
+
+// fetch はMetadata APIからプロパティのメタデータを取得する
+func (s *Service) fetch(ctx context.Context, propertyID string) (*Metadata, error) {
+	fmt.Printf("プロパティ %s のメタデータを取得中...\n", propertyID)
+
+	name := fmt.Sprintf("properties/%s/metadata", propertyID)
+	response, err := s.data.Properties.GetMetadata(name).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("プロパティ %s のメタデータの取得に失敗しました: %w", propertyID, err)
+	}
+
+	return FromResponse(propertyID, response, time.Now()), nil
+}
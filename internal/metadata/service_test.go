@@ -0,0 +1,132 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/api/analyticsdata/v1beta"
+	"google.golang.org/api/option"
+)
+
+// newTestService はテスト用のMetadata APIサーバーに接続するServiceを作成する
+func newTestService(t *testing.T, cache *Cache, handler http.HandlerFunc) *Service {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	data, err := analyticsdata.NewService(context.Background(),
+		option.WithEndpoint(server.URL+"/"),
+		option.WithHTTPClient(server.Client()),
+	)
+	if err != nil {
+		t.Fatalf("analyticsdata.NewService() error = %v", err)
+	}
+	return newService(data, cache)
+}
+
+// metadataHandler はメタデータを返すハンドラーで、呼び出し回数を数える
+func metadataHandler(t *testing.T, calls *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		if r.URL.Path != "/v1beta/properties/123/metadata" {
+			t.Errorf("リクエストパス = %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(&analyticsdata.Metadata{
+			Dimensions: []*analyticsdata.DimensionMetadata{
+				{ApiName: "date", UiName: "Date"},
+				{ApiName: "customEvent:article_id", UiName: "Article ID", CustomDefinition: true},
+			},
+			Metrics: []*analyticsdata.MetricMetadata{
+				{ApiName: "sessions", Type: "TYPE_INTEGER"},
+			},
+		})
+	}
+}
+
+func TestService_Get(t *testing.T) {
+	cache := NewCache(t.TempDir(), time.Hour)
+	calls := 0
+	service := newTestService(t, cache, metadataHandler(t, &calls))
+
+	md, err := service.Get(context.Background(), "123")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, ok := md.Dimension("customEvent:article_id"); !ok {
+		t.Error("カスタムディメンションが含まれていません")
+	}
+
+	// 同じ実行中は再取得しない
+	if _, err := service.Get(context.Background(), "123"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("API呼び出し回数 = %d, want 1", calls)
+	}
+
+	// 有効なキャッシュがあれば別のServiceでもAPIを呼ばない
+	another := newTestService(t, cache, metadataHandler(t, &calls))
+	if _, err := another.Get(context.Background(), "123"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("キャッシュ利用時のAPI呼び出し回数 = %d, want 1", calls)
+	}
+}
+
+func TestService_Get_ExpiredCache(t *testing.T) {
+	cache := NewCache(t.TempDir(), time.Hour)
+	cache.Save(&Metadata{PropertyID: "123", FetchedAt: time.Now().Add(-2 * time.Hour)})
+
+	calls := 0
+	service := newTestService(t, cache, metadataHandler(t, &calls))
+	md, err := service.Get(context.Background(), "123")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if calls != 1 || len(md.Dimensions) == 0 {
+		t.Errorf("期限切れのキャッシュが再取得されませんでした（呼び出し回数 %d）", calls)
+	}
+
+	// APIエラー時は期限切れのキャッシュを使う
+	failing := newTestService(t, NewCache(cache.dir, 0), func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"code":500,"message":"internal"}}`, http.StatusInternalServerError)
+	})
+	if _, err := failing.Get(context.Background(), "123"); err != nil {
+		t.Errorf("期限切れキャッシュへのフォールバックに失敗しました: %v", err)
+	}
+}
+
+func TestOfflineService_Get(t *testing.T) {
+	cache := NewCache(t.TempDir(), time.Hour)
+	service := NewOfflineService(cache)
+
+	if _, err := service.Get(context.Background(), "123"); err == nil {
+		t.Error("キャッシュがない場合にエラーが発生しませんでした")
+	}
+
+	// オフラインでは期限切れのキャッシュも使う
+	cache.Save(&Metadata{PropertyID: "123", FetchedAt: time.Now().Add(-48 * time.Hour)})
+	if _, err := service.Get(context.Background(), "123"); err != nil {
+		t.Errorf("Get() error = %v", err)
+	}
+}
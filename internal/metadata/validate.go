@@ -0,0 +1,222 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ymotongpoo/ga/internal/config"
+	"github.com/ymotongpoo/ga/internal/errors"
+)
+
+// customPrefixes はプロパティごとのカスタム定義に使われるAPI名の接頭辞
+var customPrefixes = []string{"customEvent:", "customUser:", "customItem:"}
+
+// expressionMetricPattern は計算指標の式に含まれるメトリクス名
+var expressionMetricPattern = regexp.MustCompile(`[A-Za-z][A-Za-z0-9_]*(:[A-Za-z0-9_]+)?`)
+
+// maxSuggestions は「もしかして」で提示する候補の最大数
+const maxSuggestions = 3
+
+// ValidateConfig は設定ファイルのディメンション・メトリクスとフィルタのフィールド名を
+// プロパティのメタデータで検証する
+// 問題をまとめて報告できるよう、全ストリームを検証してからエラーを返す
+func ValidateConfig(ctx context.Context, cfg *config.Config, source Source) error {
+	var problems []string
+
+	for i, property := range cfg.Properties {
+		md, err := source.Get(ctx, property.ID)
+		if err != nil {
+			return err
+		}
+
+		for j, stream := range property.Streams {
+			path := fmt.Sprintf("properties[%d].streams[%d]", i, j)
+			problems = append(problems, validateStream(md, stream, path)...)
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.NewValidationError(
+			fmt.Sprintf("ディメンションとメトリクスの検証に失敗しました:\n  - %s", strings.Join(problems, "\n  - ")),
+			nil,
+		)
+	}
+
+	return nil
+}
+
+// validateStream は1つのストリームのフィールド名を検証し、問題の一覧を返す
+func validateStream(md *Metadata, stream config.Stream, path string) []string {
+	var problems []string
+
+	for _, name := range stream.Dimensions {
+		if err := md.ValidateDimension(name); err != nil {
+			problems = append(problems, fmt.Sprintf("%s.dimensions: %v", path, err))
+		}
+	}
+	for _, name := range stream.Metrics {
+		if err := md.ValidateMetric(name); err != nil {
+			problems = append(problems, fmt.Sprintf("%s.metrics: %v", path, err))
+		}
+	}
+	for _, name := range filterFields(stream.DimensionFilter) {
+		if err := md.ValidateDimension(name); err != nil {
+			problems = append(problems, fmt.Sprintf("%s.dimension_filter: %v", path, err))
+		}
+	}
+	for _, name := range stream.CalculatedMetricNames() {
+		for _, ref := range expressionMetrics(stream.CalculatedMetrics[name]) {
+			if err := md.ValidateMetric(ref); err != nil {
+				problems = append(problems, fmt.Sprintf("%s.calculated_metrics.%s: %v", path, name, err))
+			}
+		}
+	}
+	for _, name := range filterFields(stream.MetricFilter) {
+		// 計算指標の別名はメタデータに存在しない
+		if _, ok := stream.CalculatedMetrics[name]; ok {
+			continue
+		}
+		if err := md.ValidateMetric(name); err != nil {
+			problems = append(problems, fmt.Sprintf("%s.metric_filter: %v", path, err))
+		}
+	}
+
+	return problems
+}
+
+// ValidateDimension はディメンション名がプロパティで利用できるかを検証する
+func (m *Metadata) ValidateDimension(name string) error {
+	if _, ok := m.Dimension(name); ok {
+		return nil
+	}
+	if _, ok := m.Metric(name); ok {
+		return fmt.Errorf("%s はメトリクスです（metrics に指定してください）", name)
+	}
+	return unknownFieldError("ディメンション", name, apiNames(m.Dimensions))
+}
+
+// ValidateMetric はメトリクス名がプロパティで利用できるかを検証する
+func (m *Metadata) ValidateMetric(name string) error {
+	if _, ok := m.Metric(name); ok {
+		return nil
+	}
+	if _, ok := m.Dimension(name); ok {
+		return fmt.Errorf("%s はディメンションです（dimensions に指定してください）", name)
+	}
+	return unknownFieldError("メトリクス", name, apiNames(m.Metrics))
+}
+
+// unknownFieldError は不明なフィールド名のエラーを候補の提案付きで作成する
+func unknownFieldError(kind, name string, candidates []string) error {
+	message := fmt.Sprintf("不明な%sです: %s", kind, name)
+	for _, prefix := range customPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			message = fmt.Sprintf("このプロパティにカスタム%sとして登録されていません: %s", kind, name)
+			break
+		}
+	}
+
+	if suggestions := Suggest(name, candidates); len(suggestions) > 0 {
+		message += fmt.Sprintf("（もしかして: %s）", strings.Join(suggestions, ", "))
+	}
+	return fmt.Errorf("%s", message)
+}
+
+// Suggest はnameに近い候補を編集距離の近い順に最大maxSuggestions件返す
+// 大文字小文字の違いは距離に含めない
+func Suggest(name string, candidates []string) []string {
+	type scored struct {
+		name     string
+		distance int
+	}
+
+	threshold := max(2, len(name)/3)
+	lowerName := strings.ToLower(name)
+
+	var matches []scored
+	for _, candidate := range candidates {
+		distance := levenshtein(lowerName, strings.ToLower(candidate))
+		if distance <= threshold {
+			matches = append(matches, scored{name: candidate, distance: distance})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	var result []string
+	for i := 0; i < len(matches) && i < maxSuggestions; i++ {
+		result = append(result, matches[i].name)
+	}
+	return result
+}
+
+// levenshtein は2つの文字列の編集距離を計算する
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// expressionMetrics は計算指標の式で参照しているメトリクス名を出現順に返す
+func expressionMetrics(expression string) []string {
+	return expressionMetricPattern.FindAllString(expression, -1)
+}
+
+// filterFields はフィルタ式で使われているフィールド名を出現順に返す
+func filterFields(expr *config.FilterExpression) []string {
+	if expr == nil {
+		return nil
+	}
+
+	var fields []string
+	for i := range expr.And {
+		fields = append(fields, filterFields(&expr.And[i])...)
+	}
+	for i := range expr.Or {
+		fields = append(fields, filterFields(&expr.Or[i])...)
+	}
+	fields = append(fields, filterFields(expr.Not)...)
+	if expr.Field != "" {
+		fields = append(fields, expr.Field)
+	}
+	return fields
+}
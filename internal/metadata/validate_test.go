@@ -0,0 +1,152 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ymotongpoo/ga/internal/config"
+)
+
+// staticSource はテスト用の固定メタデータを返すSource
+type staticSource map[string]*Metadata
+
+func (s staticSource) Get(ctx context.Context, propertyID string) (*Metadata, error) {
+	return s[propertyID], nil
+}
+
+func testMetadata() *Metadata {
+	return &Metadata{
+		PropertyID: "987654321",
+		Dimensions: []Field{
+			{APIName: "date"}, {APIName: "pagePath"}, {APIName: "pageTitle"}, {APIName: "deviceCategory"},
+			{APIName: "customEvent:article_id", CustomDefinition: true},
+			{APIName: "customUser:plan", CustomDefinition: true},
+		},
+		Metrics: []Field{
+			{APIName: "sessions"}, {APIName: "activeUsers"}, {APIName: "newUsers"}, {APIName: "screenPageViews"},
+			{APIName: "customEvent:reading_time", CustomDefinition: true},
+		},
+	}
+}
+
+func TestMetadata_ValidateFields(t *testing.T) {
+	md := testMetadata()
+
+	tests := []struct {
+		name      string
+		dimension bool
+		field     string
+		wantErr   string
+	}{
+		{name: "既知のディメンション", dimension: true, field: "pagePath"},
+		{name: "カスタムディメンション", dimension: true, field: "customUser:plan"},
+		{name: "既知のメトリクス", field: "sessions"},
+		{name: "カスタム指標", field: "customEvent:reading_time"},
+		{name: "綴り間違い", dimension: true, field: "pagePaht", wantErr: "もしかして: pagePath"},
+		{name: "大文字小文字の違い", field: "ActiveUsers", wantErr: "もしかして: activeUsers"},
+		{name: "未登録のカスタム定義", dimension: true, field: "customEvent:article", wantErr: "カスタムディメンションとして登録されていません"},
+		{name: "メトリクスをディメンションに指定", dimension: true, field: "sessions", wantErr: "metrics に指定してください"},
+		{name: "候補なし", field: "zzzzzzzzzz", wantErr: "不明なメトリクスです"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.dimension {
+				err = md.ValidateDimension(tt.field)
+			} else {
+				err = md.ValidateMetric(tt.field)
+			}
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("予期しないエラー: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("エラー = %v, want %q を含む", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"sessions", "engagedSessions", "newUsers", "activeUsers", "totalUsers"}
+
+	if got := Suggest("sesions", candidates); !reflect.DeepEqual(got, []string{"sessions"}) {
+		t.Errorf("Suggest(sesions) = %v", got)
+	}
+	if got := Suggest("activeUser", candidates); len(got) == 0 || got[0] != "activeUsers" {
+		t.Errorf("Suggest(activeUser) = %v", got)
+	}
+	if got := Suggest("bounceRate", candidates); len(got) != 0 {
+		t.Errorf("Suggest(bounceRate) = %v, want なし", got)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	cfg := &config.Config{
+		Properties: []config.Property{{
+			ID: "987654321",
+			Streams: []config.Stream{
+				{ID: "1", Dimensions: []string{"date", "pagePath"}, Metrics: []string{"sessions"}},
+				{
+					ID:         "2",
+					Dimensions: []string{"date", "devicecategory"},
+					Metrics:    []string{"sessions"},
+					DimensionFilter: &config.FilterExpression{
+						And: []config.FilterExpression{{Field: "pagePath"}, {Field: "pageTitel"}},
+					},
+					MetricFilter: &config.FilterExpression{Field: "newUser"},
+				},
+				{
+					ID:         "3",
+					Dimensions: []string{"date"},
+					Metrics:    []string{"customEvent:reading_time"},
+					CalculatedMetrics: map[string]string{
+						"pages_per_session": "screenPageViews/sessions",
+						"views_per_user":    "screenPageView/(activeUsers+1)",
+					},
+					MetricFilter: &config.FilterExpression{Field: "pages_per_session"},
+				},
+			},
+		}},
+	}
+
+	err := ValidateConfig(context.Background(), cfg, staticSource{"987654321": testMetadata()})
+	if err == nil {
+		t.Fatal("不正なフィールド名でエラーが発生しませんでした")
+	}
+
+	message := err.Error()
+	for _, want := range []string{
+		"properties[0].streams[1].dimensions: 不明なディメンションです: devicecategory（もしかして: deviceCategory）",
+		"properties[0].streams[1].dimension_filter: 不明なディメンションです: pageTitel（もしかして: pageTitle）",
+		"properties[0].streams[1].metric_filter: 不明なメトリクスです: newUser（もしかして: newUsers）",
+		"properties[0].streams[2].calculated_metrics.views_per_user: 不明なメトリクスです: screenPageView（もしかして: screenPageViews）",
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("エラーメッセージに %q が含まれていません:\n%s", want, message)
+		}
+	}
+	if strings.Contains(message, "streams[0]") || strings.Contains(message, "pages_per_session") {
+		t.Errorf("正しいフィールドがエラーに含まれています:\n%s", message)
+	}
+}
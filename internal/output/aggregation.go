@@ -0,0 +1,90 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"strings"
+
+	"github.com/ymotongpoo/ga/internal/analytics"
+)
+
+// JSONReport は集計値を含む場合のJSON出力の構造体
+// 集計値がない場合はレコードの配列のみを出力する
+type JSONReport struct {
+	Records      []JSONRecord                 `json:"records"`
+	Aggregations map[string][]JSONAggregation `json:"aggregations"` // 集計の種類（total, minimum, maximum）-> ストリームごとの集計値
+}
+
+// JSONAggregation はJSON出力用の集計値の構造体
+type JSONAggregation struct {
+	PropertyID string         `json:"property_id"`
+	StreamID   string         `json:"stream_id,omitempty"`
+	Metrics    map[string]any `json:"metrics"` // 型が分かるメトリクスは数値、それ以外は文字列
+}
+
+// jsonAggregations はReportDataの集計値を集計の種類ごとにまとめる
+func jsonAggregations(data *analytics.ReportData) map[string][]JSONAggregation {
+	aggregations := make(map[string][]JSONAggregation)
+	for _, aggregation := range data.Aggregations {
+		aggregations[aggregation.Type] = append(aggregations[aggregation.Type], JSONAggregation{
+			PropertyID: aggregation.PropertyID,
+			StreamID:   aggregation.StreamID,
+			Metrics:    typedMetrics(data.Schema, aggregation.Metrics),
+		})
+	}
+	return aggregations
+}
+
+// aggregationLabel はCSVのフッター行に表示する集計の種類のラベルを返す
+func aggregationLabel(aggregationType string) string {
+	return "(" + aggregationType + ")"
+}
+
+// aggregationFooter はReportDataの集計値をデータ行と同じ列の並びのフッター行にする
+// property_id, stream_id 以外の最初のディメンション列に集計の種類のラベルを入れ、
+// その他のディメンション列と集計値のないメトリクス列は空にする
+func aggregationFooter(data *analytics.ReportData) [][]string {
+	if len(data.Aggregations) == 0 {
+		return nil
+	}
+
+	labelIndex := -1
+	for i, header := range data.Headers {
+		lower := strings.ToLower(header)
+		if lower != "property_id" && lower != "stream_id" && columnIsDimension(data.Schema, header) {
+			labelIndex = i
+			break
+		}
+	}
+
+	rows := make([][]string, 0, len(data.Aggregations))
+	for _, aggregation := range data.Aggregations {
+		row := make([]string, len(data.Headers))
+		for i, header := range data.Headers {
+			switch {
+			case i == labelIndex:
+				row[i] = aggregationLabel(aggregation.Type)
+			case strings.ToLower(header) == "property_id":
+				row[i] = aggregation.PropertyID
+			case strings.ToLower(header) == "stream_id":
+				row[i] = aggregation.StreamID
+			case !columnIsDimension(data.Schema, header):
+				row[i] = aggregation.Metrics[header]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
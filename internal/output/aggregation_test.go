@@ -0,0 +1,172 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ymotongpoo/ga/internal/analytics"
+)
+
+// createAggregatedTestData はAPIが計算した集計値を含むテストデータを作成する
+func createAggregatedTestData() *analytics.ReportData {
+	return &analytics.ReportData{
+		Headers: []string{"property_id", "stream_id", "pagePath", "activeUsers", "bounceRate"},
+		Rows: [][]string{
+			{"987654321", "1234567", "/a", "80", "0.5"},
+			{"987654321", "1234567", "/b", "40", "0.25"},
+		},
+		Schema: &analytics.Schema{
+			Dimensions: []string{"property_id", "stream_id", "pagePath"},
+			Metrics:    []string{"activeUsers", "bounceRate"},
+			MetricTypes: map[string]analytics.MetricType{
+				"activeUsers": analytics.MetricTypeInteger,
+				"bounceRate":  analytics.MetricTypeFloat,
+			},
+		},
+		Aggregations: []analytics.Aggregation{
+			{PropertyID: "987654321", StreamID: "1234567", Type: "total", Metrics: map[string]string{"activeUsers": "100", "bounceRate": "0.4"}},
+			{PropertyID: "987654321", StreamID: "1234567", Type: "maximum", Metrics: map[string]string{"activeUsers": "80", "bounceRate": "0.5"}},
+		},
+	}
+}
+
+func TestWriteCSV_AggregationFooter(t *testing.T) {
+	outputService := NewOutputService()
+
+	var buf bytes.Buffer
+	if err := outputService.WriteCSV(createAggregatedTestData(), &buf); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("CSVの読み込みに失敗しました: %v", err)
+	}
+	if len(records) != 5 {
+		t.Fatalf("行数 = %d, want 5（ヘッダー + データ2行 + 集計2行）", len(records))
+	}
+
+	// 集計行はURL結合せず、ディメンション列にラベルを入れる
+	want := [][]string{
+		{"987654321", "1234567", "(total)", "100", "0.4"},
+		{"987654321", "1234567", "(maximum)", "80", "0.5"},
+	}
+	if !reflect.DeepEqual(records[3:], want) {
+		t.Errorf("集計行 = %v, want %v", records[3:], want)
+	}
+}
+
+func TestAggregationFooter_MissingMetrics(t *testing.T) {
+	data := createAggregatedTestData()
+	data.Aggregations = []analytics.Aggregation{
+		{PropertyID: "111", StreamID: "2", Type: "minimum", Metrics: map[string]string{"activeUsers": "1"}},
+	}
+
+	got := aggregationFooter(data)
+	want := [][]string{{"111", "2", "(minimum)", "1", ""}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("aggregationFooter() = %v, want %v", got, want)
+	}
+
+	data.Aggregations = nil
+	if got := aggregationFooter(data); got != nil {
+		t.Errorf("集計値がない場合 aggregationFooter() = %v, want nil", got)
+	}
+}
+
+func TestWriteJSON_Aggregations(t *testing.T) {
+	outputService := NewOutputService()
+
+	var buf bytes.Buffer
+	if err := outputService.WriteJSON(createAggregatedTestData(), &buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var report struct {
+		Records      []map[string]any            `json:"records"`
+		Aggregations map[string][]map[string]any `json:"aggregations"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("JSONの解析に失敗しました: %v\n%s", err, buf.String())
+	}
+	if len(report.Records) != 2 {
+		t.Errorf("records = %d 件, want 2", len(report.Records))
+	}
+
+	totals := report.Aggregations["total"]
+	if len(totals) != 1 {
+		t.Fatalf("aggregations.total = %v, want 1件", totals)
+	}
+	if totals[0]["property_id"] != "987654321" || totals[0]["stream_id"] != "1234567" {
+		t.Errorf("aggregations.total[0] = %v", totals[0])
+	}
+	metrics, _ := totals[0]["metrics"].(map[string]any)
+	if metrics["activeUsers"] != float64(100) || metrics["bounceRate"] != 0.4 {
+		t.Errorf("aggregations.total[0].metrics = %v, want 数値の 100, 0.4", metrics)
+	}
+	if _, ok := report.Aggregations["minimum"]; ok {
+		t.Error("指定していない集計の種類は出力しない必要があります")
+	}
+
+	// 集計値がない場合は従来どおりレコードの配列を出力する
+	data := createAggregatedTestData()
+	data.Aggregations = nil
+	buf.Reset()
+	if err := outputService.WriteJSON(data, &buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	var records []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Errorf("集計値がない場合は配列である必要があります: %v", err)
+	}
+}
+
+func TestWriteWithOptions_JSONAggregations(t *testing.T) {
+	outputService := NewOutputService()
+	outputPath := filepath.Join(t.TempDir(), "report.json")
+
+	options := OutputOptions{
+		OutputPath:        outputPath,
+		Format:            FormatJSON,
+		OverwriteExisting: true,
+		JSONOptions:       &JSONWriteOptions{CompactOutput: boolPtr(true)},
+		QuietMode:         true,
+	}
+	if err := outputService.WriteWithOptions(createAggregatedTestData(), options); err != nil {
+		t.Fatalf("WriteWithOptions() error = %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("出力ファイルの読み込みに失敗しました: %v", err)
+	}
+	var report JSONReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("JSONの解析に失敗しました: %v\n%s", err, content)
+	}
+	if len(report.Records) != 2 || len(report.Aggregations["total"]) != 1 {
+		t.Errorf("オプション付きの出力でも集計値を出力する必要があります: %s", content)
+	}
+	if bytes.Contains(bytes.TrimSpace(content), []byte("\n")) {
+		t.Errorf("CompactOutput が適用されていません: %s", content)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import "github.com/ymotongpoo/ga/internal/analytics"
+
+// JSONCohortPeriod はコホートレポートの1期間分のメトリクスを表すJSON出力用の構造体
+type JSONCohortPeriod struct {
+	Period  int            `json:"period"`  // 獲得期間を0とする期間の番号
+	Metrics map[string]any `json:"metrics"` // メトリクス名 -> 値（値のない期間はnull）
+}
+
+// nestCohortPeriods はコホートレポートの生成列をmetricsから取り出し、期間ごとに入れ子にする
+// コホートレポートでない場合はnilを返す
+func nestCohortPeriods(schema *analytics.Schema, metrics map[string]any) []JSONCohortPeriod {
+	if schema == nil || len(schema.CohortColumns) == 0 {
+		return nil
+	}
+
+	var periods []JSONCohortPeriod
+	index := make(map[int]int)
+	for _, column := range schema.CohortColumns {
+		value, ok := metrics[column.Name]
+		if !ok {
+			continue
+		}
+		delete(metrics, column.Name)
+
+		i, ok := index[column.Period]
+		if !ok {
+			i = len(periods)
+			index[column.Period] = i
+			periods = append(periods, JSONCohortPeriod{Period: column.Period, Metrics: make(map[string]any)})
+		}
+		periods[i].Metrics[column.Metric] = value
+	}
+	return periods
+}
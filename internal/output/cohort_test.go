@@ -0,0 +1,105 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ymotongpoo/ga/internal/analytics"
+)
+
+// createCohortTestData はコホートレポートの継続率の表のテストデータを作成する
+func createCohortTestData() *analytics.ReportData {
+	return &analytics.ReportData{
+		Headers: []string{"property_id", "stream_id", "cohort", "cohortActiveUsers (week 0)", "cohortActiveUsers (week 1)"},
+		Rows: [][]string{
+			{"987654321", "1234567", "2024-01-01", "100", "40"},
+			{"987654321", "1234567", "2024-01-08", "80", ""},
+		},
+		Schema: &analytics.Schema{
+			Dimensions: []string{"property_id", "stream_id", "cohort"},
+			Metrics:    []string{"cohortActiveUsers (week 0)", "cohortActiveUsers (week 1)"},
+			MetricTypes: map[string]analytics.MetricType{
+				"cohortActiveUsers (week 0)": analytics.MetricTypeInteger,
+				"cohortActiveUsers (week 1)": analytics.MetricTypeInteger,
+			},
+			CohortColumns: []analytics.CohortColumn{
+				{Name: "cohortActiveUsers (week 0)", Period: 0, Metric: "cohortActiveUsers"},
+				{Name: "cohortActiveUsers (week 1)", Period: 1, Metric: "cohortActiveUsers"},
+			},
+		},
+	}
+}
+
+func TestWriteCSV_CohortMatrix(t *testing.T) {
+	outputService := NewOutputService()
+
+	var buf bytes.Buffer
+	if err := outputService.WriteCSV(createCohortTestData(), &buf); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	want := "property_id,stream_id,cohort,cohortActiveUsers (week 0),cohortActiveUsers (week 1)\n" +
+		"987654321,1234567,2024-01-01,100,40\n" +
+		"987654321,1234567,2024-01-08,80,\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteCSV() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteJSON_CohortPeriods(t *testing.T) {
+	outputService := NewOutputService()
+
+	var buf bytes.Buffer
+	if err := outputService.WriteJSON(createCohortTestData(), &buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "(week 0)") {
+		t.Errorf("期間の生成列は periods に入れ子にする必要があります:\n%s", buf.String())
+	}
+
+	var records []struct {
+		Dimensions map[string]string `json:"dimensions"`
+		Metrics    map[string]any    `json:"metrics"`
+		Periods    []struct {
+			Period  int            `json:"period"`
+			Metrics map[string]any `json:"metrics"`
+		} `json:"periods"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("JSONの解析に失敗しました: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("レコード数 = %d, want 2", len(records))
+	}
+
+	second := records[1]
+	if second.Dimensions["cohort"] != "2024-01-08" || len(second.Metrics) != 0 {
+		t.Errorf("records[1] = %+v", second)
+	}
+	if len(second.Periods) != 2 || second.Periods[0].Period != 0 || second.Periods[1].Period != 1 {
+		t.Fatalf("records[1].periods = %+v", second.Periods)
+	}
+	if got := second.Periods[0].Metrics; !reflect.DeepEqual(got, map[string]any{"cohortActiveUsers": float64(80)}) {
+		t.Errorf("periods[0].metrics = %v", got)
+	}
+	if got := second.Periods[1].Metrics; !reflect.DeepEqual(got, map[string]any{"cohortActiveUsers": nil}) {
+		t.Errorf("経過していない期間は null である必要があります: %v", got)
+	}
+}
@@ -108,7 +108,9 @@ func TestCSVJSONDataConsistency(t *testing.T) {
 				if isDimension(header) {
 					jsonValue, exists = jsonRecord.Dimensions[header]
 				} else {
-					jsonValue, exists = jsonRecord.Metrics[header]
+					var value any
+					value, exists = jsonRecord.Metrics[header]
+					jsonValue, _ = value.(string)
 				}
 
 				if !exists {
@@ -184,7 +186,7 @@ func TestCSVJSONDataConsistency(t *testing.T) {
 				if isDimension(header) {
 					jsonValue = jsonRecord.Dimensions[header]
 				} else {
-					jsonValue = jsonRecord.Metrics[header]
+					jsonValue, _ = jsonRecord.Metrics[header].(string)
 				}
 
 				if csvValue != jsonValue {
@@ -285,7 +287,7 @@ func TestCSVJSONDataConsistency(t *testing.T) {
 				if isDimension(header) {
 					jsonValue = jsonRecord.Dimensions[header]
 				} else {
-					jsonValue = jsonRecord.Metrics[header]
+					jsonValue, _ = jsonRecord.Metrics[header].(string)
 				}
 
 				if csvValue != jsonValue {
@@ -355,7 +357,7 @@ func TestCSVJSONDataConsistency(t *testing.T) {
 				if isDimension(header) {
 					jsonValue = jsonRecord.Dimensions[header]
 				} else {
-					jsonValue = jsonRecord.Metrics[header]
+					jsonValue, _ = jsonRecord.Metrics[header].(string)
 				}
 
 				// 空文字列が正しく保持されていることを確認
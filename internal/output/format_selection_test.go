@@ -464,7 +464,7 @@ func TestFormatConsistencyAcrossOperations(t *testing.T) {
 				if isDimension(header) {
 					jsonValue = jsonRecord.Dimensions[header]
 				} else {
-					jsonValue = jsonRecord.Metrics[header]
+					jsonValue, _ = jsonRecord.Metrics[header].(string)
 				}
 
 				if csvValue != jsonValue {
@@ -0,0 +1,218 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ymotongpoo/ga/internal/analytics"
+)
+
+// Replacement は差分取得で既存の出力から置き換えるストリームと期間を表す構造体
+type Replacement struct {
+	PropertyID string
+	StreamID   string
+	StartDate  string // YYYY-MM-DD形式
+	EndDate    string // YYYY-MM-DD形式
+}
+
+// covers は行のプロパティ・ストリーム・date ディメンション（YYYYMMDD形式）が置き換えの対象かを判定する
+func (r Replacement) covers(propertyID, streamID, date string) bool {
+	if r.PropertyID != propertyID || r.StreamID != streamID {
+		return false
+	}
+	start := strings.ReplaceAll(r.StartDate, "-", "")
+	end := strings.ReplaceAll(r.EndDate, "-", "")
+	return start <= date && date <= end
+}
+
+// replaced は行がいずれかの置き換えの対象かを判定する
+func replaced(replacements []Replacement, propertyID, streamID, date string) bool {
+	for _, r := range replacements {
+		if r.covers(propertyID, streamID, date) {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeOutput は差分取得したデータを既存の出力ファイルに反映する
+// 既存のファイルから replacements の期間の行を除き、取得したデータの行を末尾に追加する
+// ファイルが存在しない場合は新規に作成する
+func (o *OutputServiceImpl) MergeOutput(data *analytics.ReportData, filename string, format OutputFormat, replacements []Replacement) error {
+	if err := o.ValidateData(data); err != nil {
+		return fmt.Errorf("出力データの検証に失敗しました: %w", err)
+	}
+	if len(data.Tables) > 1 {
+		return fmt.Errorf("列の構成が異なる複数の表は既存の出力ファイルに反映できません")
+	}
+
+	existing, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return o.WriteToFileWithErrorHandling(data, filename, format)
+	}
+	if err != nil {
+		return fmt.Errorf("既存の出力ファイル '%s' の読み込みに失敗しました: %w", filename, err)
+	}
+
+	var merged []byte
+	var kept, removed int
+	switch format {
+	case FormatCSV:
+		merged, kept, removed, err = o.mergeCSV(existing, data, replacements)
+	case FormatJSON:
+		merged, kept, removed, err = o.mergeJSON(existing, data, replacements)
+	default:
+		err = fmt.Errorf("サポートされていない出力形式です: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("既存の出力ファイル '%s' への反映に失敗しました: %w", filename, err)
+	}
+
+	// 書き込み途中で中断しても既存の出力が壊れないよう、一時ファイルに書き込んでから置き換える
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, merged, 0644); err != nil {
+		return o.handleFileCreationError(tmp, err)
+	}
+	if err := os.Rename(tmp, filename); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("ファイル '%s' の置き換えに失敗しました: %w", filename, err)
+	}
+
+	fmt.Printf("📄 %s出力を更新しました: %s\n", strings.ToUpper(format.String()), filename)
+	fmt.Printf("   - 既存の行: %d行（置き換えた行: %d行）\n", kept, removed)
+	fmt.Printf("   - 追加した行: %d行\n", len(data.Rows))
+	return nil
+}
+
+// mergeCSV は既存のCSVと取得したデータを統合し、統合したCSVと残した行数・除いた行数を返す
+func (o *OutputServiceImpl) mergeCSV(existing []byte, data *analytics.ReportData, replacements []Replacement) ([]byte, int, int, error) {
+	var rendered bytes.Buffer
+	if err := o.WriteCSV(data, &rendered); err != nil {
+		return nil, 0, 0, err
+	}
+	added, err := o.readCSV(rendered.Bytes())
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	records, err := o.readCSV(existing)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("CSVの形式が不正です: %w", err)
+	}
+	if len(records) == 0 {
+		return rendered.Bytes(), 0, 0, nil
+	}
+
+	header := added[0]
+	if !slices.Equal(records[0], header) {
+		return nil, 0, 0, fmt.Errorf("列の構成が今回の取得結果と異なります（既存: %s, 今回: %s）。--full-refresh で出力ファイルを作り直してください", strings.Join(records[0], ","), strings.Join(header, ","))
+	}
+	propertyIndex := slices.Index(header, "property_id")
+	streamIndex := slices.Index(header, "stream_id")
+	dateIndex := slices.Index(header, "date")
+	if propertyIndex < 0 || streamIndex < 0 || dateIndex < 0 {
+		return nil, 0, 0, fmt.Errorf("property_id, stream_id, date の列が必要です")
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Comma = o.csvWriter.delimiter
+	writer.Write(header)
+
+	kept, removed := 0, 0
+	for _, record := range records[1:] {
+		if len(record) == len(header) && replaced(replacements, record[propertyIndex], record[streamIndex], record[dateIndex]) {
+			removed++
+			continue
+		}
+		writer.Write(record)
+		kept++
+	}
+	for _, record := range added[1:] {
+		writer.Write(record)
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, 0, 0, fmt.Errorf("CSV書き込み中にエラーが発生しました: %w", err)
+	}
+	return buf.Bytes(), kept, removed, nil
+}
+
+// readCSV は出力ファイルと同じ区切り文字でCSVを読み込む
+func (o *OutputServiceImpl) readCSV(data []byte) ([][]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = o.csvWriter.delimiter
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+// mergeJSON は既存のJSONと取得したデータを統合し、統合したJSONと残したレコード数・除いたレコード数を返す
+// record_index と total_records は統合後のレコードの順に振り直す
+func (o *OutputServiceImpl) mergeJSON(existing []byte, data *analytics.ReportData, replacements []Replacement) ([]byte, int, int, error) {
+	var rendered bytes.Buffer
+	if err := o.WriteJSON(data, &rendered); err != nil {
+		return nil, 0, 0, err
+	}
+	added, err := decodeJSONRecords(rendered.Bytes())
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	records, err := decodeJSONRecords(existing)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("JSONの形式が不正です（レコードの配列である必要があります）: %w", err)
+	}
+
+	var merged []JSONRecord
+	removed := 0
+	for _, record := range records {
+		if replaced(replacements, record.Metadata.PropertyID, record.Metadata.StreamID, record.Dimensions["date"]) {
+			removed++
+			continue
+		}
+		merged = append(merged, record)
+	}
+	kept := len(merged)
+	merged = append(merged, added...)
+
+	for i := range merged {
+		merged[i].Metadata.RecordIndex = i + 1
+		merged[i].Metadata.TotalRecords = len(merged)
+	}
+
+	var buf bytes.Buffer
+	if err := o.jsonWriter.writeRecords(merged, &buf); err != nil {
+		return nil, 0, 0, err
+	}
+	return buf.Bytes(), kept, removed, nil
+}
+
+// decodeJSONRecords はJSONレコードの配列を読み込む（メトリクスの数値は桁を保ったまま読み込む）
+func decodeJSONRecords(data []byte) ([]JSONRecord, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var records []JSONRecord
+	if err := decoder.Decode(&records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
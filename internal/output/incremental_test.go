@@ -0,0 +1,203 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ymotongpoo/ga/internal/analytics"
+)
+
+// createIncrementalTestData は差分取得した2日分のテストデータを作成する
+func createIncrementalTestData() *analytics.ReportData {
+	return &analytics.ReportData{
+		Headers: []string{"property_id", "stream_id", "date", "sessions"},
+		Rows: [][]string{
+			{"111", "1", "20240130", "35"},
+			{"111", "1", "20240131", "40"},
+		},
+		Schema: &analytics.Schema{
+			Dimensions:  []string{"property_id", "stream_id", "date"},
+			Metrics:     []string{"sessions"},
+			MetricTypes: map[string]analytics.MetricType{"sessions": analytics.MetricTypeInteger},
+		},
+		Summary: analytics.ReportSummary{DateRange: "2024-01-30 - 2024-01-31"},
+	}
+}
+
+// incrementalReplacements は 2024-01-30 - 2024-01-31 を置き換える指定
+var incrementalReplacements = []Replacement{
+	{PropertyID: "111", StreamID: "1", StartDate: "2024-01-30", EndDate: "2024-01-31"},
+}
+
+func TestMergeOutput_CSV(t *testing.T) {
+	outputService := NewOutputService()
+	path := filepath.Join(t.TempDir(), "daily.csv")
+
+	existing := `property_id,stream_id,date,sessions
+111,1,20240129,10
+111,1,20240130,20
+111,2,20240130,5
+`
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := outputService.MergeOutput(createIncrementalTestData(), path, FormatCSV, incrementalReplacements); err != nil {
+		t.Fatalf("MergeOutput() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("CSVの読み込みに失敗しました: %v", err)
+	}
+
+	// 置き換える期間の行（ストリーム 1 の 2024-01-30）を除き、取得した行を末尾に追加する
+	want := [][]string{
+		{"property_id", "stream_id", "date", "sessions"},
+		{"111", "1", "20240129", "10"},
+		{"111", "2", "20240130", "5"},
+		{"111", "1", "20240130", "35"},
+		{"111", "1", "20240131", "40"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("統合後のCSV = %v, want %v", records, want)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("一時ファイルが残っています: %v", err)
+	}
+}
+
+func TestMergeOutput_CSVHeaderMismatch(t *testing.T) {
+	outputService := NewOutputService()
+	path := filepath.Join(t.TempDir(), "daily.csv")
+
+	existing := "property_id,stream_id,date,activeUsers\n111,1,20240129,10\n"
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	err := outputService.MergeOutput(createIncrementalTestData(), path, FormatCSV, incrementalReplacements)
+	if err == nil || !strings.Contains(err.Error(), "--full-refresh") {
+		t.Errorf("MergeOutput() error = %v, want header mismatch", err)
+	}
+
+	// 失敗した場合は既存のファイルを変更しない
+	data, _ := os.ReadFile(path)
+	if string(data) != existing {
+		t.Errorf("既存のファイルが変更されています:\n%s", data)
+	}
+}
+
+func TestMergeOutput_NewFile(t *testing.T) {
+	outputService := NewOutputService()
+	path := filepath.Join(t.TempDir(), "daily.csv")
+
+	if err := outputService.MergeOutput(createIncrementalTestData(), path, FormatCSV, incrementalReplacements); err != nil {
+		t.Fatalf("MergeOutput() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if got := strings.Count(string(data), "\n"); got != 3 {
+		t.Errorf("行数 = %d, want 3:\n%s", got, data)
+	}
+}
+
+func TestMergeOutput_JSON(t *testing.T) {
+	outputService := NewOutputService()
+	path := filepath.Join(t.TempDir(), "daily.json")
+
+	existing := []JSONRecord{
+		{
+			Dimensions: map[string]string{"date": "20240129"},
+			Metrics:    map[string]any{"sessions": 10},
+			Metadata:   JSONMetadata{PropertyID: "111", StreamID: "1", RecordIndex: 1, TotalRecords: 2},
+		},
+		{
+			Dimensions: map[string]string{"date": "20240130"},
+			Metrics:    map[string]any{"sessions": 20},
+			Metadata:   JSONMetadata{PropertyID: "111", StreamID: "1", RecordIndex: 2, TotalRecords: 2},
+		},
+	}
+	data, err := json.Marshal(existing)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := outputService.MergeOutput(createIncrementalTestData(), path, FormatJSON, incrementalReplacements); err != nil {
+		t.Fatalf("MergeOutput() error = %v", err)
+	}
+
+	merged, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var records []JSONRecord
+	if err := json.Unmarshal(merged, &records); err != nil {
+		t.Fatalf("JSONの解析に失敗しました: %v", err)
+	}
+
+	var dates []string
+	for i, record := range records {
+		dates = append(dates, record.Dimensions["date"])
+		if record.Metadata.RecordIndex != i+1 || record.Metadata.TotalRecords != 3 {
+			t.Errorf("レコード %d の record_index = %d, total_records = %d", i, record.Metadata.RecordIndex, record.Metadata.TotalRecords)
+		}
+	}
+	if want := []string{"20240129", "20240130", "20240131"}; !reflect.DeepEqual(dates, want) {
+		t.Errorf("date = %v, want %v", dates, want)
+	}
+	if got := records[1].Metrics["sessions"]; got != float64(35) {
+		t.Errorf("置き換えた 2024-01-30 の sessions = %v, want 35", got)
+	}
+}
+
+func TestReplacement_covers(t *testing.T) {
+	r := Replacement{PropertyID: "111", StreamID: "1", StartDate: "2024-01-30", EndDate: "2024-01-31"}
+
+	tests := []struct {
+		propertyID, streamID, date string
+		want                       bool
+	}{
+		{"111", "1", "20240130", true},
+		{"111", "1", "20240131", true},
+		{"111", "1", "20240129", false},
+		{"111", "1", "20240201", false},
+		{"111", "2", "20240130", false},
+		{"222", "1", "20240130", false},
+	}
+	for _, tt := range tests {
+		if got := r.covers(tt.propertyID, tt.streamID, tt.date); got != tt.want {
+			t.Errorf("covers(%s, %s, %s) = %v, want %v", tt.propertyID, tt.streamID, tt.date, got, tt.want)
+		}
+	}
+}
@@ -68,7 +68,7 @@ func TestJSONWriter_ComprehensiveUnitTests(t *testing.T) {
 					"date":     "2023-01-01",
 					"pagePath": "/home",
 				},
-				Metrics: map[string]string{
+				Metrics: map[string]any{
 					"sessions":    "1250",
 					"activeUsers": "1100",
 				},
@@ -145,7 +145,7 @@ func TestJSONWriter_ComprehensiveUnitTests(t *testing.T) {
 		records := []JSONRecord{
 			{
 				Dimensions: map[string]string{"date": "2023-01-01"},
-				Metrics:    map[string]string{"sessions": "1250"},
+				Metrics:    map[string]any{"sessions": "1250"},
 				Metadata: JSONMetadata{
 					RetrievedAt:  "2023-02-01T10:30:00Z",
 					RecordIndex:  1,
@@ -394,7 +394,7 @@ func TestCSVJSONOutputComparison(t *testing.T) {
 				if isDimension(header) {
 					jsonValue = jsonRecord.Dimensions[header]
 				} else {
-					jsonValue = jsonRecord.Metrics[header]
+					jsonValue, _ = jsonRecord.Metrics[header].(string)
 				}
 
 				if csvValue != jsonValue {
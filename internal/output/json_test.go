@@ -30,7 +30,7 @@ func TestJSONRecord_Structure(t *testing.T) {
 			"date":     "2023-01-01",
 			"pagePath": "/home",
 		},
-		Metrics: map[string]string{
+		Metrics: map[string]any{
 			"sessions":    "1250",
 			"activeUsers": "1100",
 		},
@@ -138,7 +138,7 @@ func TestCreateKeyValuePairs(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			dimensions, metrics := outputService.createKeyValuePairs(tt.headers, tt.row)
+			dimensions, metrics := outputService.createKeyValuePairs(tt.headers, tt.row, nil)
 
 			// ディメンションの検証
 			if len(dimensions) != len(tt.expectedDims) {
@@ -206,6 +206,11 @@ func TestIsDimension(t *testing.T) {
 		{"engagementDuration", false},
 		{"totalRevenue", false},
 
+		// 比較期間の列
+		{"sessions_comparison", false},
+		{"sessions_change", false},
+		{"sessions_change_pct", false},
+
 		// 不明なフィールド（デフォルトはディメンション）
 		{"unknownField", true},
 		{"customDimension", true},
@@ -414,7 +419,7 @@ func TestJSONWriter_WriteRecords(t *testing.T) {
 				"date":     "2023-01-01",
 				"pagePath": "/home",
 			},
-			Metrics: map[string]string{
+			Metrics: map[string]any{
 				"sessions":    "1250",
 				"activeUsers": "1100",
 			},
@@ -467,7 +472,7 @@ func TestJSONWriter_WriteRecordsWithOptions(t *testing.T) {
 	records := []JSONRecord{
 		{
 			Dimensions: map[string]string{"date": "2023-01-01"},
-			Metrics:    map[string]string{"sessions": "1250"},
+			Metrics:    map[string]any{"sessions": "1250"},
 			Metadata: JSONMetadata{
 				RetrievedAt:  "2023-02-01T10:30:00Z",
 				RecordIndex:  1,
@@ -635,7 +640,7 @@ func TestJSONWriter_FormatJSONForDisplay(t *testing.T) {
 				"date":     "2023-01-01",
 				"pagePath": "/home",
 			},
-			Metrics: map[string]string{
+			Metrics: map[string]any{
 				"sessions": "1250",
 			},
 			Metadata: JSONMetadata{
@@ -688,7 +693,7 @@ func TestJSONWriter_UTF8Encoding(t *testing.T) {
 				"pagePath":  "/ホーム",
 				"pageTitle": "ホームページ",
 			},
-			Metrics: map[string]string{
+			Metrics: map[string]any{
 				"sessions": "1250",
 			},
 			Metadata: JSONMetadata{
@@ -1282,3 +1287,158 @@ func TestConvertToJSONRecords(t *testing.T) {
 		t.Errorf("2番目のレコードのRecordIndexが一致しません: 期待=2, 実際=%d", secondRecord.Metadata.RecordIndex)
 	}
 }
+
+func TestWriteJSON_UsesSchema(t *testing.T) {
+	outputService := NewOutputService()
+
+	// keyEvents と sessionSource は列名からは分類できないが、スキーマに従って分類される
+	data := &analytics.ReportData{
+		Headers: []string{"property_id", "stream_id", "sessionSource", "keyEvents"},
+		Rows:    [][]string{{"987654321", "1234567", "google", "12"}},
+		Schema: &analytics.Schema{
+			Dimensions: []string{"property_id", "stream_id", "sessionSource"},
+			Metrics:    []string{"keyEvents"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := outputService.WriteJSON(data, &buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var records []JSONRecord
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("JSONの解析に失敗しました: %v", err)
+	}
+	if records[0].Metrics["keyEvents"] != "12" {
+		t.Errorf("keyEvents がメトリクスに分類されていません: %+v", records[0])
+	}
+	if records[0].Dimensions["sessionSource"] != "google" {
+		t.Errorf("sessionSource がディメンションに分類されていません: %+v", records[0])
+	}
+}
+
+func TestWriteJSON_Pivot(t *testing.T) {
+	outputService := NewOutputService()
+
+	data := &analytics.ReportData{
+		Headers: []string{"property_id", "stream_id", "country", "sessions (desktop)", "sessions (mobile)"},
+		Rows:    [][]string{{"987654321", "1234567", "Japan", "10", "20"}},
+		Schema: &analytics.Schema{
+			Dimensions: []string{"property_id", "stream_id", "country"},
+			Metrics:    []string{"sessions (desktop)", "sessions (mobile)"},
+			PivotColumns: []analytics.PivotColumn{
+				{Name: "sessions (desktop)", Values: []string{"desktop"}, Metric: "sessions"},
+				{Name: "sessions (mobile)", Values: []string{"mobile"}, Metric: "sessions"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := outputService.WriteJSON(data, &buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var records []JSONRecord
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("JSONの解析に失敗しました: %v", err)
+	}
+	record := records[0]
+	if record.Pivot["desktop"]["sessions"] != "10" || record.Pivot["mobile"]["sessions"] != "20" {
+		t.Errorf("pivot = %+v, want desktop/mobile ごとの sessions", record.Pivot)
+	}
+	if len(record.Metrics) != 0 {
+		t.Errorf("ピボットの生成列が metrics に残っています: %+v", record.Metrics)
+	}
+	if record.Dimensions["country"] != "Japan" {
+		t.Errorf("dimensions = %+v", record.Dimensions)
+	}
+
+	// CSVでは生成された列名をそのままヘッダーに使う
+	buf.Reset()
+	if err := outputService.WriteCSV(data, &buf); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	if header := strings.SplitN(buf.String(), "\n", 2)[0]; header != "property_id,stream_id,country,sessions (desktop),sessions (mobile)" {
+		t.Errorf("CSVヘッダー = %q", header)
+	}
+}
+
+func TestWriteJSON_PropertyQuota(t *testing.T) {
+	outputService := NewOutputService()
+
+	quota := &analytics.PropertyQuota{
+		TokensPerDay:  analytics.QuotaStatus{Consumed: 100, Remaining: 199900},
+		TokensPerHour: analytics.QuotaStatus{Consumed: 100, Remaining: 39900},
+	}
+	data := &analytics.ReportData{
+		Headers: []string{"property_id", "stream_id", "pagePath", "sessions"},
+		Rows: [][]string{
+			{"987654321", "1234567", "/", "10"},
+			{"111111111", "7654321", "/", "20"},
+		},
+		Quotas: map[string]*analytics.PropertyQuota{"987654321": quota},
+	}
+
+	var buf bytes.Buffer
+	if err := outputService.WriteJSON(data, &buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var records []JSONRecord
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("JSONの解析に失敗しました: %v", err)
+	}
+	if got := records[0].Metadata.PropertyQuota; got == nil || *got != *quota {
+		t.Errorf("metadata.property_quota = %+v, want %+v", got, quota)
+	}
+	if got := records[1].Metadata.PropertyQuota; got != nil {
+		t.Errorf("クォータのないプロパティの metadata.property_quota = %+v, want nil", got)
+	}
+	if !strings.Contains(buf.String(), `"tokens_per_hour"`) {
+		t.Errorf("JSONに tokens_per_hour が含まれていません: %s", buf.String())
+	}
+}
+
+func TestWriteJSON_ResponseMetadata(t *testing.T) {
+	outputService := NewOutputService()
+
+	metadata := &analytics.ResponseMetadata{
+		PropertyID:            "987654321",
+		StreamID:              "1234567",
+		CurrencyCode:          "JPY",
+		TimeZone:              "Asia/Tokyo",
+		SubjectToThresholding: true,
+		Sampling:              []analytics.SamplingMetadata{{SamplesReadCount: 100, SamplingSpaceSize: 400}},
+	}
+	data := &analytics.ReportData{
+		Headers: []string{"property_id", "stream_id", "pagePath", "sessions"},
+		Rows: [][]string{
+			{"987654321", "1234567", "/", "10"},
+			{"111111111", "7654321", "/", "20"},
+		},
+		Metadata: []*analytics.ResponseMetadata{metadata},
+	}
+
+	var buf bytes.Buffer
+	if err := outputService.WriteJSON(data, &buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var records []JSONRecord
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("JSONの解析に失敗しました: %v", err)
+	}
+	got := records[0].Metadata.ResponseMetadata
+	if got == nil || got.CurrencyCode != "JPY" || !got.SubjectToThresholding || got.SamplingRate() != 0.25 {
+		t.Errorf("metadata.response_metadata = %+v, want %+v", got, metadata)
+	}
+	if got := records[1].Metadata.ResponseMetadata; got != nil {
+		t.Errorf("メタデータのないストリームの metadata.response_metadata = %+v, want nil", got)
+	}
+	for _, want := range []string{`"subject_to_thresholding": true`, `"sampling_space_size": 400`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("JSONに %s が含まれていません: %s", want, buf.String())
+		}
+	}
+}
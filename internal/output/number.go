@@ -0,0 +1,181 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ymotongpoo/ga/internal/analytics"
+	"github.com/ymotongpoo/ga/internal/config"
+)
+
+// typedMetrics はメトリクスの値を列の型に応じてJSONの数値に変換する
+// 型が不明な列と数値として解釈できない値は文字列のまま、空の値はnullにする
+func typedMetrics(schema *analytics.Schema, values map[string]string) map[string]any {
+	metrics := make(map[string]any, len(values))
+	for column, value := range values {
+		metrics[column] = typedValue(schema.MetricType(column), value)
+	}
+	return metrics
+}
+
+// typedValue は1つのメトリクスの値を型に応じて変換する
+func typedValue(metricType analytics.MetricType, value string) any {
+	if !metricType.IsNumeric() {
+		return value
+	}
+	if value == "" {
+		return nil
+	}
+
+	if metricType.IsInteger() {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return value
+	}
+	return f
+}
+
+// numberFormatter はCSV・表形式で出力するメトリクスの値を書式設定する
+// nilの場合は値をそのまま出力する
+type numberFormatter struct {
+	separators    config.NumberSeparators // 桁区切りと小数点（Groupingが空の場合は区切らない）
+	decimalPlaces int                     // 小数を丸める桁数（負の場合は丸めない）
+	hms           bool                    // 時間のメトリクスを hh:mm:ss で出力する
+}
+
+// newNumberFormatter は設定からnumberFormatterを作成する
+// 書式を変更しない設定の場合はnilを返す
+func newNumberFormatter(format *config.NumberFormat) *numberFormatter {
+	if format == nil {
+		return nil
+	}
+
+	f := &numberFormatter{
+		separators:    config.NumberSeparators{Decimal: "."},
+		decimalPlaces: -1,
+		hms:           format.DurationOrDefault() == config.DurationFormatHMS,
+	}
+	if separators, ok := config.LocaleSeparators(format.Locale); ok {
+		f.separators = separators
+	}
+	if format.DecimalPlaces != nil {
+		f.decimalPlaces = *format.DecimalPlaces
+	}
+
+	if f.separators.Grouping == "" && f.separators.Decimal == "." && f.decimalPlaces < 0 && !f.hms {
+		return nil
+	}
+	return f
+}
+
+// SetNumberFormat はCSV・表形式で出力するメトリクスの書式を設定する
+func (o *OutputServiceImpl) SetNumberFormat(format *config.NumberFormat) {
+	o.numberFormatter = newNumberFormatter(format)
+}
+
+// formatRow は行のメトリクス列を書式設定した新しい行を返す
+// headersは書式設定前の列名（スキーマの列名）
+func (f *numberFormatter) formatRow(row []string, headers []string, schema *analytics.Schema) []string {
+	if f == nil || schema == nil {
+		return row
+	}
+
+	formatted := make([]string, len(row))
+	copy(formatted, row)
+	for i, header := range headers {
+		if i < len(formatted) {
+			formatted[i] = f.format(schema.MetricType(header), formatted[i])
+		}
+	}
+	return formatted
+}
+
+// format は1つのメトリクスの値を型に応じて書式設定する
+// 型が不明な列と数値として解釈できない値はそのまま返す
+func (f *numberFormatter) format(metricType analytics.MetricType, value string) string {
+	if f == nil || !metricType.IsNumeric() || value == "" {
+		return value
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return value
+	}
+
+	if unit := metricType.DurationUnit(); f.hms && unit > 0 {
+		return formatHMS(time.Duration(number * float64(unit)))
+	}
+
+	digits := value
+	switch {
+	case metricType.IsInteger():
+		// 整数はAPIの値の桁をそのまま使う
+	case f.decimalPlaces >= 0:
+		// FormatFloatは偶数丸めのため、四捨五入してから桁数を揃える
+		scale := math.Pow10(f.decimalPlaces)
+		digits = strconv.FormatFloat(math.Round(number*scale)/scale, 'f', f.decimalPlaces, 64)
+	case strings.ContainsAny(value, "eE"):
+		digits = strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return f.localize(digits)
+}
+
+// localize は "-1234.5" 形式の数値の文字列に桁区切りを入れ、小数点をロケールの記号にする
+func (f *numberFormatter) localize(digits string) string {
+	sign := ""
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign, digits = digits[:1], digits[1:]
+	}
+	integer, fraction, hasFraction := strings.Cut(digits, ".")
+
+	if f.separators.Grouping != "" && len(integer) > 3 {
+		var grouped strings.Builder
+		head := len(integer) % 3
+		if head > 0 {
+			grouped.WriteString(integer[:head])
+		}
+		for i := head; i < len(integer); i += 3 {
+			if grouped.Len() > 0 {
+				grouped.WriteString(f.separators.Grouping)
+			}
+			grouped.WriteString(integer[i : i+3])
+		}
+		integer = grouped.String()
+	}
+
+	if !hasFraction {
+		return sign + integer
+	}
+	return sign + integer + f.separators.Decimal + fraction
+}
+
+// formatHMS は時間を hh:mm:ss 形式にする（秒未満は四捨五入する）
+func formatHMS(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	seconds := int64(d.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%s%02d:%02d:%02d", sign, seconds/3600, seconds/60%60, seconds%60)
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ymotongpoo/ga/internal/analytics"
+	"github.com/ymotongpoo/ga/internal/config"
+)
+
+// createTypedTestData は型付きのスキーマを持つテストデータを作成する
+func createTypedTestData() *analytics.ReportData {
+	return &analytics.ReportData{
+		Headers: []string{"property_id", "stream_id", "date", "sessions", "averageSessionDuration", "purchaseRevenue", "bounceRate"},
+		Rows: [][]string{
+			{"987654321", "1234567", "20240101", "1250", "3725.6", "1234567.891", "0.4567"},
+			{"987654321", "1234567", "20240102", "", "59.4", "0", "1"},
+		},
+		Schema: &analytics.Schema{
+			Dimensions: []string{"property_id", "stream_id", "date"},
+			Metrics:    []string{"sessions", "averageSessionDuration", "purchaseRevenue", "bounceRate"},
+			MetricTypes: map[string]analytics.MetricType{
+				"sessions":               analytics.MetricTypeInteger,
+				"averageSessionDuration": analytics.MetricTypeSeconds,
+				"purchaseRevenue":        analytics.MetricTypeCurrency,
+				"bounceRate":             analytics.MetricTypeFloat,
+			},
+		},
+	}
+}
+
+func TestTypedValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		metricType analytics.MetricType
+		value      string
+		want       any
+	}{
+		{name: "整数", metricType: analytics.MetricTypeInteger, value: "1250", want: int64(1250)},
+		{name: "小数", metricType: analytics.MetricTypeFloat, value: "0.25", want: 0.25},
+		{name: "時間", metricType: analytics.MetricTypeSeconds, value: "120.5", want: 120.5},
+		{name: "整数型の小数", metricType: analytics.MetricTypeInteger, value: "1.5", want: 1.5},
+		{name: "空の値", metricType: analytics.MetricTypeInteger, value: "", want: nil},
+		{name: "数値でない値", metricType: analytics.MetricTypeFloat, value: "(other)", want: "(other)"},
+		{name: "型が不明", metricType: "", value: "1250", want: "1250"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := typedValue(tt.metricType, tt.value); got != tt.want {
+				t.Errorf("typedValue(%q, %q) = %#v, want %#v", tt.metricType, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteJSON_TypedMetrics(t *testing.T) {
+	outputService := NewOutputService()
+
+	var buf bytes.Buffer
+	if err := outputService.WriteJSON(createTypedTestData(), &buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	for _, want := range []string{`"sessions": 1250`, `"averageSessionDuration": 3725.6`, `"purchaseRevenue": 1234567.891`, `"sessions": null`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("JSONに %s が含まれていません: %s", want, buf.String())
+		}
+	}
+
+	var records []JSONRecord
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("JSONの解析に失敗しました: %v", err)
+	}
+	if got, ok := records[0].Metrics["bounceRate"].(float64); !ok || got != 0.4567 {
+		t.Errorf("metrics.bounceRate = %#v, want 0.4567", records[0].Metrics["bounceRate"])
+	}
+	// ディメンションは文字列のまま出力する
+	if got := records[0].Dimensions["date"]; got != "20240101" {
+		t.Errorf("dimensions.date = %q, want %q", got, "20240101")
+	}
+}
+
+func TestNumberFormatter_Format(t *testing.T) {
+	two := 2
+	zero := 0
+
+	tests := []struct {
+		name       string
+		format     *config.NumberFormat
+		metricType analytics.MetricType
+		value      string
+		want       string
+	}{
+		{name: "桁区切り（整数）", format: &config.NumberFormat{Locale: "en-US"}, metricType: analytics.MetricTypeInteger, value: "1234567", want: "1,234,567"},
+		{name: "桁区切りなし（3桁以下）", format: &config.NumberFormat{Locale: "en-US"}, metricType: analytics.MetricTypeInteger, value: "-123", want: "-123"},
+		{name: "ドイツ語の小数点", format: &config.NumberFormat{Locale: "de-DE"}, metricType: analytics.MetricTypeCurrency, value: "1234567.891", want: "1.234.567,891"},
+		{name: "フランス語の桁区切り", format: &config.NumberFormat{Locale: "fr"}, metricType: analytics.MetricTypeFloat, value: "-1234.5", want: "-1\u00a0234,5"},
+		{name: "丸め", format: &config.NumberFormat{DecimalPlaces: &two}, metricType: analytics.MetricTypeFloat, value: "0.4567", want: "0.46"},
+		{name: "丸めと桁区切り", format: &config.NumberFormat{Locale: "ja-JP", DecimalPlaces: &zero}, metricType: analytics.MetricTypeCurrency, value: "1234.5", want: "1,235"},
+		{name: "整数は丸めない", format: &config.NumberFormat{DecimalPlaces: &two}, metricType: analytics.MetricTypeInteger, value: "10", want: "10"},
+		{name: "指数表記", format: &config.NumberFormat{Locale: "en"}, metricType: analytics.MetricTypeFloat, value: "1.5E-4", want: "0.00015"},
+		{name: "hh:mm:ss（秒）", format: &config.NumberFormat{Duration: config.DurationFormatHMS}, metricType: analytics.MetricTypeSeconds, value: "3725.6", want: "01:02:06"},
+		{name: "hh:mm:ss（ミリ秒）", format: &config.NumberFormat{Duration: config.DurationFormatHMS}, metricType: analytics.MetricTypeMilliseconds, value: "59400", want: "00:00:59"},
+		{name: "hh:mm:ss（負の値）", format: &config.NumberFormat{Duration: config.DurationFormatHMS}, metricType: analytics.MetricTypeSeconds, value: "-90", want: "-00:01:30"},
+		{name: "hh:mm:ss（100時間以上）", format: &config.NumberFormat{Duration: config.DurationFormatHMS}, metricType: analytics.MetricTypeHours, value: "123", want: "123:00:00"},
+		{name: "時間以外には hms を適用しない", format: &config.NumberFormat{Duration: config.DurationFormatHMS}, metricType: analytics.MetricTypeInteger, value: "3600", want: "3600"},
+		{name: "型が不明", format: &config.NumberFormat{Locale: "en"}, metricType: "", value: "1234567", want: "1234567"},
+		{name: "空の値", format: &config.NumberFormat{Locale: "en"}, metricType: analytics.MetricTypeInteger, value: "", want: ""},
+		{name: "数値でない値", format: &config.NumberFormat{Locale: "en"}, metricType: analytics.MetricTypeInteger, value: "N/A", want: "N/A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatter := newNumberFormatter(tt.format)
+			if got := formatter.format(tt.metricType, tt.value); got != tt.want {
+				t.Errorf("format(%q, %q) = %q, want %q", tt.metricType, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewNumberFormatter_NoChange(t *testing.T) {
+	for _, format := range []*config.NumberFormat{nil, {}, {Duration: config.DurationFormatSeconds}} {
+		if got := newNumberFormatter(format); got != nil {
+			t.Errorf("newNumberFormatter(%+v) = %+v, want nil", format, got)
+		}
+	}
+}
+
+func TestWriteCSV_NumberFormat(t *testing.T) {
+	outputService := NewOutputService()
+	two := 2
+	outputService.SetNumberFormat(&config.NumberFormat{Locale: "de-DE", DecimalPlaces: &two, Duration: config.DurationFormatHMS})
+
+	var buf bytes.Buffer
+	if err := outputService.WriteCSV(createTypedTestData(), &buf); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("CSVの行数 = %d, want 3: %s", len(lines), buf.String())
+	}
+	// ディメンション（日付・ID）は書式設定しない
+	if want := `987654321,1234567,20240101,1.250,01:02:06,"1.234.567,89","0,46"`; lines[1] != want {
+		t.Errorf("1行目 = %s, want %s", lines[1], want)
+	}
+	if want := `987654321,1234567,20240102,,00:00:59,"0,00","1,00"`; lines[2] != want {
+		t.Errorf("2行目 = %s, want %s", lines[2], want)
+	}
+
+	// 書式の設定がない場合はAPIの値のまま出力する
+	outputService.SetNumberFormat(nil)
+	buf.Reset()
+	if err := outputService.WriteCSV(createTypedTestData(), &buf); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "20240101,1250,3725.6,1234567.891,0.4567") {
+		t.Errorf("書式設定なしのCSV = %s", buf.String())
+	}
+}
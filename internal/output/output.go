@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/ymotongpoo/ga/internal/analytics"
+	"github.com/ymotongpoo/ga/internal/config"
 	"github.com/ymotongpoo/ga/internal/url"
 )
 
@@ -107,6 +108,14 @@ type OutputService interface {
 	ValidateOutputOptions(options OutputOptions) error
 	// GetOutputSummary は出力データのサマリー情報を取得する
 	GetOutputSummary(data *analytics.ReportData, format OutputFormat) string
+	// WriteTable はReportDataを列を揃えた表形式でWriterに出力する
+	WriteTable(data *analytics.ReportData, writer io.Writer) error
+	// AppendNDJSON はReportDataをNDJSON形式でファイルの末尾に追記する
+	AppendNDJSON(data *analytics.ReportData, filename string) error
+	// MergeOutput は差分取得したデータを既存の出力ファイルに反映する
+	MergeOutput(data *analytics.ReportData, filename string, format OutputFormat, replacements []Replacement) error
+	// SetNumberFormat はCSV・表形式で出力するメトリクスの書式を設定する
+	SetNumberFormat(format *config.NumberFormat)
 }
 
 // CSVWriter はCSV出力を行う構造体
@@ -128,9 +137,11 @@ type JSONWriter struct {
 // JSONRecord はJSON出力用のレコード構造体
 // 要件4.6, 4.12: ディメンションとメトリクスのキー・バリューペア、メタデータを含む
 type JSONRecord struct {
-	Dimensions map[string]string `json:"dimensions"`
-	Metrics    map[string]string `json:"metrics"`
-	Metadata   JSONMetadata      `json:"metadata"`
+	Dimensions map[string]string         `json:"dimensions"`
+	Metrics    map[string]any            `json:"metrics"`           // 型が分かるメトリクスは数値、それ以外は文字列
+	Pivot      map[string]map[string]any `json:"pivot,omitempty"`   // ピボット表の列の値の組み合わせ -> メトリクス
+	Periods    []JSONCohortPeriod        `json:"periods,omitempty"` // コホートレポートの期間ごとのメトリクス
+	Metadata   JSONMetadata              `json:"metadata"`
 }
 
 // JSONMetadata はJSON出力用のメタデータ構造体
@@ -140,16 +151,23 @@ type JSONMetadata struct {
 	PropertyID   string `json:"property_id,omitempty"`
 	StreamID     string `json:"stream_id,omitempty"`
 	DateRange    string `json:"date_range"`
+	StartDate    string `json:"start_date,omitempty"`
+	EndDate      string `json:"end_date,omitempty"`
 	RecordIndex  int    `json:"record_index"`
 	TotalRecords int    `json:"total_records"`
 	OutputFormat string `json:"output_format"`
 	ToolVersion  string `json:"tool_version,omitempty"`
+
+	PropertyQuota    *analytics.PropertyQuota    `json:"property_quota,omitempty"`    // 取得後のプロパティのクォータの状態
+	ResponseMetadata *analytics.ResponseMetadata `json:"response_metadata,omitempty"` // サンプリング・しきい値などのデータの精度に関する情報
 }
 
 // OutputServiceImpl はOutputServiceの実装
 type OutputServiceImpl struct {
 	csvWriter  *CSVWriter
 	jsonWriter *JSONWriter
+
+	numberFormatter *numberFormatter // CSV・表形式のメトリクスの書式（nilの場合はそのまま出力）
 }
 
 // NewOutputService は新しいOutputServiceを作成する
@@ -184,8 +202,6 @@ func (o *OutputServiceImpl) WriteCSV(data *analytics.ReportData, writer io.Write
 	urlProcessor := url.NewURLProcessor(data.StreamURLs)
 	processedHeaders, pagePathIndex := o.processHeaders(data.Headers)
 
-
-
 	// ヘッダー行を書き込み
 	if len(processedHeaders) > 0 {
 		if err := csvWriter.Write(processedHeaders); err != nil {
@@ -196,11 +212,20 @@ func (o *OutputServiceImpl) WriteCSV(data *analytics.ReportData, writer io.Write
 	// データ行を書き込み（URL結合処理付き）
 	for i, row := range data.Rows {
 		processedRow := o.processRow(row, pagePathIndex, urlProcessor, data.Headers)
+		processedRow = o.numberFormatter.formatRow(processedRow, data.Headers, data.Schema)
 		if err := csvWriter.Write(processedRow); err != nil {
 			return fmt.Errorf("データ行 %d の書き込みに失敗しました: %w", i+1, err)
 		}
 	}
 
+	// APIが計算した集計値をフッター行として書き込み
+	for i, row := range aggregationFooter(data) {
+		row = o.numberFormatter.formatRow(row, data.Headers, data.Schema)
+		if err := csvWriter.Write(row); err != nil {
+			return fmt.Errorf("集計行 %d の書き込みに失敗しました: %w", i+1, err)
+		}
+	}
+
 	// バッファをフラッシュしてエラーをチェック
 	if err := csvWriter.Error(); err != nil {
 		return fmt.Errorf("CSV書き込み中にエラーが発生しました: %w", err)
@@ -236,7 +261,10 @@ func (o *OutputServiceImpl) WriteJSON(data *analytics.ReportData, writer io.Writ
 		processedRow := o.processRowForJSON(row, data.Headers, urlProcessor)
 
 		// ディメンションとメトリクスのキー・バリューペアを作成
-		dimensions, metrics := o.createKeyValuePairs(data.Headers, processedRow)
+		dimensions, values := o.createKeyValuePairs(data.Headers, processedRow, data.Schema)
+		metrics := typedMetrics(data.Schema, values)
+		pivot := nestPivotMetrics(data.Schema, metrics)
+		periods := nestCohortPeriods(data.Schema, metrics)
 
 		// プロパティIDとストリームIDを抽出
 		propertyID := o.extractPropertyID(processedRow, data.Headers)
@@ -245,28 +273,49 @@ func (o *OutputServiceImpl) WriteJSON(data *analytics.ReportData, writer io.Writ
 		record := JSONRecord{
 			Dimensions: dimensions,
 			Metrics:    metrics,
+			Pivot:      pivot,
+			Periods:    periods,
 			Metadata: JSONMetadata{
-				RetrievedAt:  retrievedAt,
-				PropertyID:   propertyID,
-				StreamID:     streamID,
-				DateRange:    data.Summary.DateRange,
-				RecordIndex:  recordIndex + 1, // 1ベースのインデックス
-				TotalRecords: totalRecords,
-				OutputFormat: "json",
-				ToolVersion:  "ga-tool-v1.0", // バージョン情報
+				RetrievedAt:   retrievedAt,
+				PropertyID:    propertyID,
+				StreamID:      streamID,
+				DateRange:     data.Summary.DateRange,
+				StartDate:     data.Summary.StartDate,
+				EndDate:       data.Summary.EndDate,
+				RecordIndex:   recordIndex + 1, // 1ベースのインデックス
+				TotalRecords:  totalRecords,
+				OutputFormat:  "json",
+				ToolVersion:   "ga-tool-v1.0", // バージョン情報
+				PropertyQuota: data.Quotas[propertyID],
+
+				ResponseMetadata: data.MetadataFor(propertyID, streamID),
 			},
 		}
 
 		records = append(records, record)
 	}
 
+	// 集計値がある場合はレコードと集計値をまとめたオブジェクトとして出力
+	if len(data.Aggregations) > 0 {
+		return o.jsonWriter.encode(JSONReport{Records: records, Aggregations: jsonAggregations(data)}, writer)
+	}
+
 	// JSONライターを使用して出力
 	return o.jsonWriter.writeRecords(records, writer)
 }
 
+// writeJSONWithOptions はオプション付きでWriteJSONと同じ内容（URL結合・集計値を含む）を出力する
+func (o *OutputServiceImpl) writeJSONWithOptions(data *analytics.ReportData, writer io.Writer, options JSONWriteOptions) error {
+	restore := o.jsonWriter.applyOptions(options)
+	defer restore()
+
+	return o.WriteJSON(data, writer)
+}
+
 // createKeyValuePairs はヘッダーと行データからディメンションとメトリクスのキー・バリューペアを作成する
 // 要件4.6: ディメンションとメトリクスのキー・バリューペア変換
-func (o *OutputServiceImpl) createKeyValuePairs(headers []string, row []string) (map[string]string, map[string]string) {
+// schema がnilの場合は列名から推測して分類する
+func (o *OutputServiceImpl) createKeyValuePairs(headers []string, row []string, schema *analytics.Schema) (map[string]string, map[string]string) {
 	dimensions := make(map[string]string)
 	metrics := make(map[string]string)
 
@@ -284,7 +333,7 @@ func (o *OutputServiceImpl) createKeyValuePairs(headers []string, row []string)
 		}
 
 		// ディメンションとメトリクスを分類
-		if isDimension(header) {
+		if columnIsDimension(schema, header) {
 			dimensions[displayHeader] = value
 		} else {
 			metrics[displayHeader] = value
@@ -294,6 +343,30 @@ func (o *OutputServiceImpl) createKeyValuePairs(headers []string, row []string)
 	return dimensions, metrics
 }
 
+// nestPivotMetrics はピボット表の生成列をmetricsから取り出し、列の値の組み合わせごとに入れ子にする
+// ピボット表でない場合はnilを返す
+func nestPivotMetrics(schema *analytics.Schema, metrics map[string]any) map[string]map[string]any {
+	if schema == nil || len(schema.PivotColumns) == 0 {
+		return nil
+	}
+
+	pivot := make(map[string]map[string]any)
+	for _, column := range schema.PivotColumns {
+		value, ok := metrics[column.Name]
+		if !ok {
+			continue
+		}
+		delete(metrics, column.Name)
+
+		label := column.Label()
+		if pivot[label] == nil {
+			pivot[label] = make(map[string]any)
+		}
+		pivot[label][column.Metric] = value
+	}
+	return pivot
+}
+
 // extractPropertyID は行データからプロパティIDを抽出する
 func (o *OutputServiceImpl) extractPropertyID(row []string, headers []string) string {
 	for i, header := range headers {
@@ -314,9 +387,25 @@ func (o *OutputServiceImpl) extractStreamID(row []string, headers []string) stri
 	return ""
 }
 
+// columnIsDimension は列がディメンションかどうかを判定する
+// スキーマに含まれる列はAPIレスポンスのヘッダーに従い、それ以外は列名から推測する
+func columnIsDimension(schema *analytics.Schema, header string) bool {
+	if schema != nil && schema.Has(header) {
+		return schema.IsDimension(header)
+	}
+	return isDimension(header)
+}
+
 // isDimension はヘッダー名がディメンションかどうかを判定する
 // 要件4.6: ディメンションとメトリクスの正確な分類
 func isDimension(header string) bool {
+	// 比較期間の列（<metric>_comparison, <metric>_change, <metric>_change_pct）は常にメトリクス
+	for _, suffix := range []string{analytics.ComparisonSuffix, analytics.ChangeSuffix, analytics.ChangePctSuffix} {
+		if strings.HasSuffix(header, suffix) && len(header) > len(suffix) {
+			return false
+		}
+	}
+
 	headerLower := strings.ToLower(header)
 
 	// 明確にメトリクスと判定できるもの
@@ -464,6 +553,11 @@ func (o *OutputServiceImpl) ValidateData(data *analytics.ReportData) error {
 
 // WriteOutput は出力先と形式に応じて適切な出力方法を選択する
 func (o *OutputServiceImpl) WriteOutput(data *analytics.ReportData, outputPath string, format OutputFormat) error {
+	// schema_mode: split で列の構成が複数ある場合は表ごとに出力する
+	if data != nil && len(data.Tables) > 1 {
+		return o.writeTables(data.Tables, outputPath, format)
+	}
+
 	// データの妥当性を検証
 	if err := o.ValidateData(data); err != nil {
 		return fmt.Errorf("出力データの検証に失敗しました: %w", err)
@@ -478,6 +572,37 @@ func (o *OutputServiceImpl) WriteOutput(data *analytics.ReportData, outputPath s
 	return o.WriteToFileWithErrorHandling(data, outputPath, format)
 }
 
+// writeTables は列の構成ごとの表を出力する
+// ファイルの場合は表ごとに番号を付けたファイル（例: report_1.csv）に、標準出力の場合は空行で区切って出力する
+func (o *OutputServiceImpl) writeTables(tables []*analytics.ReportData, outputPath string, format OutputFormat) error {
+	for i, table := range tables {
+		if err := o.ValidateData(table); err != nil {
+			return fmt.Errorf("表 %d の出力データの検証に失敗しました: %w", i+1, err)
+		}
+
+		if outputPath == "" || outputPath == "-" {
+			if i > 0 {
+				fmt.Fprintln(os.Stdout)
+			}
+			if err := o.WriteToConsole(table, format); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := o.WriteToFileWithErrorHandling(table, tableFilename(outputPath, i+1), format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tableFilename は出力ファイル名に表の番号を付けたファイル名を返す（例: report.csv -> report_1.csv）
+func tableFilename(path string, number int) string {
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s_%d%s", strings.TrimSuffix(path, ext), number, ext)
+}
+
 // WriteToFileWithErrorHandling はエラーハンドリングを強化したファイル出力
 func (o *OutputServiceImpl) WriteToFileWithErrorHandling(data *analytics.ReportData, filename string, format OutputFormat) error {
 	if filename == "" {
@@ -614,6 +739,12 @@ func (o *OutputServiceImpl) handleFileCreationError(filename string, err error)
 // writeRecords はJSONレコード配列をWriterに出力する
 // 要件4.6, 4.9: 構造化されたJSON配列の生成、UTF-8エンコーディング対応
 func (jw *JSONWriter) writeRecords(records []JSONRecord, writer io.Writer) error {
+	// JSON配列として出力
+	return jw.encode(records, writer)
+}
+
+// encode は値をJSONライターの設定でWriterに出力する
+func (jw *JSONWriter) encode(value any, writer io.Writer) error {
 	// JSON エンコーダーを作成（UTF-8エンコーディング）
 	encoder := json.NewEncoder(writer)
 
@@ -626,8 +757,7 @@ func (jw *JSONWriter) writeRecords(records []JSONRecord, writer io.Writer) error
 
 	encoder.SetEscapeHTML(jw.escapeHTML)
 
-	// JSON配列として出力
-	if err := encoder.Encode(records); err != nil {
+	if err := encoder.Encode(value); err != nil {
 		return fmt.Errorf("JSON書き込み中にエラーが発生しました: %w", err)
 	}
 
@@ -636,7 +766,14 @@ func (jw *JSONWriter) writeRecords(records []JSONRecord, writer io.Writer) error
 
 // writeRecordsWithOptions はオプション付きでJSONレコード配列を出力する
 func (jw *JSONWriter) writeRecordsWithOptions(records []JSONRecord, writer io.Writer, options JSONWriteOptions) error {
-	// 一時的にオプションを適用
+	restore := jw.applyOptions(options)
+	defer restore()
+
+	return jw.writeRecords(records, writer)
+}
+
+// applyOptions はJSONライターに一時的にオプションを適用し、設定を元に戻す関数を返す
+func (jw *JSONWriter) applyOptions(options JSONWriteOptions) func() {
 	originalIndent := jw.indent
 	originalEscapeHTML := jw.escapeHTML
 	originalCompactOutput := jw.compactOutput
@@ -651,15 +788,11 @@ func (jw *JSONWriter) writeRecordsWithOptions(records []JSONRecord, writer io.Wr
 		jw.compactOutput = *options.CompactOutput
 	}
 
-	// 出力実行
-	err := jw.writeRecords(records, writer)
-
-	// 設定を元に戻す
-	jw.indent = originalIndent
-	jw.escapeHTML = originalEscapeHTML
-	jw.compactOutput = originalCompactOutput
-
-	return err
+	return func() {
+		jw.indent = originalIndent
+		jw.escapeHTML = originalEscapeHTML
+		jw.compactOutput = originalCompactOutput
+	}
 }
 
 // JSONWriteOptions はJSON出力のオプションを定義する
@@ -831,7 +964,7 @@ func (o *OutputServiceImpl) writeToConsoleWithOptions(data *analytics.ReportData
 		return o.WriteCSV(data, os.Stdout)
 	case FormatJSON:
 		if options.JSONOptions != nil {
-			return o.jsonWriter.writeRecordsWithOptions(o.convertToJSONRecords(data), os.Stdout, *options.JSONOptions)
+			return o.writeJSONWithOptions(data, os.Stdout, *options.JSONOptions)
 		}
 		return o.WriteJSON(data, os.Stdout)
 	default:
@@ -880,7 +1013,7 @@ func (o *OutputServiceImpl) writeToFileWithOptions(data *analytics.ReportData, o
 		}
 	case FormatJSON:
 		if options.JSONOptions != nil {
-			writeErr = o.jsonWriter.writeRecordsWithOptions(o.convertToJSONRecords(data), file, *options.JSONOptions)
+			writeErr = o.writeJSONWithOptions(data, file, *options.JSONOptions)
 		} else {
 			writeErr = o.WriteJSON(data, file)
 		}
@@ -927,11 +1060,20 @@ func (o *OutputServiceImpl) writeCSVWithOptions(data *analytics.ReportData, writ
 
 	// データ行書き込み
 	for i, row := range data.Rows {
+		row = o.numberFormatter.formatRow(row, data.Headers, data.Schema)
 		if err := csvWriter.Write(row); err != nil {
 			return fmt.Errorf("データ行 %d の書き込みに失敗しました: %w", i+1, err)
 		}
 	}
 
+	// 集計行書き込み
+	for i, row := range aggregationFooter(data) {
+		row = o.numberFormatter.formatRow(row, data.Headers, data.Schema)
+		if err := csvWriter.Write(row); err != nil {
+			return fmt.Errorf("集計行 %d の書き込みに失敗しました: %w", i+1, err)
+		}
+	}
+
 	return csvWriter.Error()
 }
 
@@ -946,22 +1088,32 @@ func (o *OutputServiceImpl) convertToJSONRecords(data *analytics.ReportData) []J
 			continue
 		}
 
-		dimensions, metrics := o.createKeyValuePairs(data.Headers, row)
+		dimensions, values := o.createKeyValuePairs(data.Headers, row, data.Schema)
+		metrics := typedMetrics(data.Schema, values)
+		pivot := nestPivotMetrics(data.Schema, metrics)
+		periods := nestCohortPeriods(data.Schema, metrics)
 		propertyID := o.extractPropertyID(row, data.Headers)
 		streamID := o.extractStreamID(row, data.Headers)
 
 		record := JSONRecord{
 			Dimensions: dimensions,
 			Metrics:    metrics,
+			Pivot:      pivot,
+			Periods:    periods,
 			Metadata: JSONMetadata{
-				RetrievedAt:  retrievedAt,
-				PropertyID:   propertyID,
-				StreamID:     streamID,
-				DateRange:    data.Summary.DateRange,
-				RecordIndex:  recordIndex + 1,
-				TotalRecords: totalRecords,
-				OutputFormat: "json",
-				ToolVersion:  "ga-tool-v1.0",
+				RetrievedAt:   retrievedAt,
+				PropertyID:    propertyID,
+				StreamID:      streamID,
+				DateRange:     data.Summary.DateRange,
+				StartDate:     data.Summary.StartDate,
+				EndDate:       data.Summary.EndDate,
+				RecordIndex:   recordIndex + 1,
+				TotalRecords:  totalRecords,
+				OutputFormat:  "json",
+				ToolVersion:   "ga-tool-v1.0",
+				PropertyQuota: data.Quotas[propertyID],
+
+				ResponseMetadata: data.MetadataFor(propertyID, streamID),
 			},
 		}
 
@@ -1030,8 +1182,6 @@ func (o *OutputServiceImpl) processRow(row []string, pagePathIndex int, urlProce
 	// ストリームIDを取得
 	streamID := o.extractStreamIDFromRow(row, headers)
 
-
-
 	// pagePathとベースURLを結合
 	pagePath := row[pagePathIndex]
 	fullURL := urlProcessor.ProcessPagePath(streamID, pagePath)
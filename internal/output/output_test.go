@@ -462,6 +462,57 @@ func TestWriteOutput_ToFile(t *testing.T) {
 	}
 }
 
+func TestWriteOutput_SplitTables(t *testing.T) {
+	service := NewOutputService()
+	data := &analytics.ReportData{
+		Headers: []string{"property_id", "stream_id", "city", "country", "sessions"},
+		Rows: [][]string{
+			{"111", "1", "Tokyo", "", "10"},
+			{"111", "2", "", "Japan", "20"},
+		},
+		Tables: []*analytics.ReportData{
+			{Headers: []string{"property_id", "stream_id", "city", "sessions"}, Rows: [][]string{{"111", "1", "Tokyo", "10"}}},
+			{Headers: []string{"property_id", "stream_id", "country", "sessions"}, Rows: [][]string{{"111", "2", "Japan", "20"}}},
+		},
+	}
+
+	dir := t.TempDir()
+	if err := service.WriteOutput(data, dir+"/report.csv", FormatCSV); err != nil {
+		t.Fatalf("WriteOutput() failed: %v", err)
+	}
+
+	want := map[string]string{
+		"report_1.csv": "property_id,stream_id,city,sessions\n111,1,Tokyo,10\n",
+		"report_2.csv": "property_id,stream_id,country,sessions\n111,2,Japan,20\n",
+	}
+	for name, content := range want {
+		got, err := os.ReadFile(dir + "/" + name)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", name, got, content)
+		}
+	}
+	if _, err := os.Stat(dir + "/report.csv"); !os.IsNotExist(err) {
+		t.Errorf("split の場合は全列をまとめた report.csv を作成しない: %v", err)
+	}
+}
+
+func TestTableFilename(t *testing.T) {
+	tests := map[string]string{
+		"report.csv":        "report_2.csv",
+		"out/data.json":     "out/data_2.json",
+		"no_extension":      "no_extension_2",
+		"dir.v1/report.csv": "dir.v1/report_2.csv",
+	}
+	for path, want := range tests {
+		if got := tableFilename(path, 2); got != want {
+			t.Errorf("tableFilename(%q, 2) = %q, want %q", path, got, want)
+		}
+	}
+}
+
 func TestWriteOutput_InvalidData(t *testing.T) {
 	service := NewOutputService()
 
@@ -0,0 +1,99 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ymotongpoo/ga/internal/analytics"
+)
+
+// WriteTable はReportDataを列を揃えた表形式でWriterに出力する
+// ga realtime のターミナル表示に使用する
+func (o *OutputServiceImpl) WriteTable(data *analytics.ReportData, writer io.Writer) error {
+	if data == nil {
+		return fmt.Errorf("出力データがnilです")
+	}
+
+	tw := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+
+	if len(data.Headers) > 0 {
+		fmt.Fprintln(tw, strings.Join(data.Headers, "\t"))
+
+		separators := make([]string, len(data.Headers))
+		for i, header := range data.Headers {
+			separators[i] = strings.Repeat("-", len(header))
+		}
+		fmt.Fprintln(tw, strings.Join(separators, "\t"))
+	}
+
+	for _, row := range data.Rows {
+		row = o.numberFormatter.formatRow(row, data.Headers, data.Schema)
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("表の書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// WriteNDJSON はReportDataを1行1レコードのJSON（NDJSON）形式でWriterに出力する
+func (o *OutputServiceImpl) WriteNDJSON(data *analytics.ReportData, writer io.Writer) error {
+	if data == nil {
+		return fmt.Errorf("出力データがnilです")
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(o.jsonWriter.escapeHTML)
+
+	for i, record := range o.convertToJSONRecords(data) {
+		record.Metadata.OutputFormat = "ndjson"
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("レコード %d の書き込みに失敗しました: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// AppendNDJSON はReportDataをNDJSON形式でファイルの末尾に追記する
+// ファイルが存在しない場合は作成する
+func (o *OutputServiceImpl) AppendNDJSON(data *analytics.ReportData, filename string) error {
+	if strings.TrimSpace(filename) == "" {
+		return fmt.Errorf("ファイル名が指定されていません")
+	}
+	if err := o.ensureDirectoryExists(filename); err != nil {
+		return fmt.Errorf("出力ディレクトリの作成に失敗しました: %w", err)
+	}
+
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return o.handleFileCreationError(filename, err)
+	}
+
+	if err := o.WriteNDJSON(data, file); err != nil {
+		file.Close()
+		return fmt.Errorf("ファイル '%s' への追記に失敗しました: %w", filename, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("ファイル '%s' のクローズに失敗しました: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Yoshi Yamaguchi
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ymotongpoo/ga/internal/analytics"
+)
+
+// createRealtimeTestData はリアルタイムレポート形式のテストデータを作成する
+func createRealtimeTestData() *analytics.ReportData {
+	return &analytics.ReportData{
+		Headers: []string{"property_id", "country", "activeUsers"},
+		Rows: [][]string{
+			{"987654321", "Japan", "42"},
+			{"987654321", "United States", "7"},
+		},
+		Schema: &analytics.Schema{
+			Dimensions: []string{"property_id", "country"},
+			Metrics:    []string{"activeUsers"},
+		},
+		Summary: analytics.ReportSummary{TotalRows: 2, DateRange: "直近30分"},
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	service := NewOutputService()
+
+	var buf bytes.Buffer
+	if err := service.WriteTable(createRealtimeTestData(), &buf); err != nil {
+		t.Fatalf("WriteTable() error = %v", err)
+	}
+
+	want := "property_id  country        activeUsers\n" +
+		"-----------  -------        -----------\n" +
+		"987654321    Japan          42\n" +
+		"987654321    United States  7\n"
+	if buf.String() != want {
+		t.Errorf("WriteTable() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestAppendNDJSON(t *testing.T) {
+	service := NewOutputService()
+	filename := filepath.Join(t.TempDir(), "realtime", "live.ndjson")
+
+	// 2回追記すると4行になる
+	for i := 0; i < 2; i++ {
+		if err := service.AppendNDJSON(createRealtimeTestData(), filename); err != nil {
+			t.Fatalf("AppendNDJSON() error = %v", err)
+		}
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatalf("ファイルを開けません: %v", err)
+	}
+	defer file.Close()
+
+	var records []JSONRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record JSONRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("NDJSONの行を解析できません: %v: %s", err, scanner.Text())
+		}
+		records = append(records, record)
+	}
+
+	if len(records) != 4 {
+		t.Fatalf("レコード数 = %d, want 4", len(records))
+	}
+	if records[0].Dimensions["country"] != "Japan" || records[0].Metrics["activeUsers"] != "42" {
+		t.Errorf("records[0] = %+v", records[0])
+	}
+	if records[0].Metadata.OutputFormat != "ndjson" || records[0].Metadata.PropertyID != "987654321" {
+		t.Errorf("records[0].Metadata = %+v", records[0].Metadata)
+	}
+}
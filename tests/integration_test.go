@@ -180,9 +180,9 @@ properties:
     streams:
       - stream: "1234567"
         dimensions: ["date"]
-        metrics: ["invalidMetric"]
+        metrics: ["invalid metric"]
 `,
-			expectedError: "無効なメトリクスが含まれています",
+			expectedError: "メトリクス名の形式が不正です",
 		},
 	}
 